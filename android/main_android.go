@@ -8,12 +8,12 @@ import (
 	_ "image/png"
 
 	"github.com/hajimehoshi/ebiten/v2/mobile"
-	sol "oddstream.games/gosol/sol"
+	"oddstream.games/gosol/game"
 )
 
 func init() {
-	sol.NewGame() // sets sol.TheGame
-	mobile.SetGame(sol.TheGame)
+	game.NewGame() // sets game.TheGame
+	mobile.SetGame(game.TheGame)
 }
 
 // Dummy is a dummy exported function.
@@ -30,9 +30,13 @@ const (
 	ordinalMask CardID = 0b0000000000001111 // 0..15 0xf
 	proneFlag   CardID = 0b0001000000000000 // single bit
 	jokerFlag   CardID = 0b0010000000000000 // single bit
+	jokerMask   CardID = 0b1100000000000000 // 0..3, which joker in the pack
 )
 
 func (cid CardID) String() string {
+	if cid.Joker() {
+		return fmt.Sprintf("%d Joker %d", cid.Pack(), cid.JokerNumber())
+	}
 	return fmt.Sprintf("%d %s %d", cid.Pack(), cid.StringSuit(), cid.Ordinal())
 }
 
@@ -101,6 +105,11 @@ func (cid CardID) Joker() bool {
 	return cid&jokerFlag == jokerFlag
 }
 
+// JokerNumber returns which of the jokers in its pack this card is
+func (cid CardID) JokerNumber() int {
+	return int((cid & jokerMask) >> 14)
+}
+
 func (cid CardID) Black() bool {
 	suit := cid.Suit()
 	return suit == CLUB || suit == SPADE
@@ -118,8 +127,21 @@ func NewCardID(pack, suit, ordinal int) CardID {
 	return CardID(u)
 }
 
+// MaxOrdinal is the highest ordinal a card can have, because that's all ordinalMask can hold
+const MaxOrdinal = 15
+
+// MaxJokers is the most jokers a pack can have, because that's all jokerMask can number
+const MaxJokers = 4
+
+// NewJokerID makes the ID of a joker; there can be up to MaxJokers jokers in a pack,
+// and they need different IDs, so the baize can tell them apart
+func NewJokerID(pack, number int) CardID {
+	return NewCardID(pack, NOSUIT, 0) | CardID(number)<<14&jokerMask
+}
+
+// PackSuitOrdinal returns the card id without the prone flag, which is enough to identify a card on the baize
 func (cid CardID) PackSuitOrdinal() CardID {
-	return cid & (packMask | suitMask | ordinalMask)
+	return cid & (packMask | suitMask | ordinalMask | jokerMask)
 }
 
 // SameCard returns true if the two cards have the same ordinal and suit; pack is ignored
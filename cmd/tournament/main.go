@@ -0,0 +1,93 @@
+// Command tournament plays seeded deals of each variant with the built in bot players,
+// without any graphics, and reports how each player got on.
+//
+//	$ go run ./cmd/tournament -variants "Klondike,Freecell" -players greedy,lookahead -deals 1000
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"oddstream.games/gosol/sol"
+)
+
+func main() {
+	var variants, players string
+	var deals int
+	var seed uint64
+	var groups string
+	var easy, hard float64
+
+	flag.StringVar(&variants, "variants", "", "comma separated variants to play (default all)")
+	flag.StringVar(&players, "players", "random,greedy,lookahead", "comma separated players, from "+strings.Join(sol.PlayerNames(), ","))
+	flag.IntVar(&deals, "deals", 100, "number of deals of each variant each player plays")
+	flag.Uint64Var(&seed, "seed", 1, "number of the first deal")
+	flag.StringVar(&groups, "groups", "", "print \"> Easier\" and \"> Harder\" variant groups from how this player got on")
+	flag.Float64Var(&easy, "easy", 50, "with -groups, variants won at least this percent of the time are easier")
+	flag.Float64Var(&hard, "hard", 5, "with -groups, variants won no more than this percent of the time are harder")
+	flag.Parse()
+
+	var vs []string
+	if variants == "" {
+		vs = sol.VariantNames("> All", nil)
+	} else {
+		vs = strings.Split(variants, ",")
+	}
+	for _, v := range vs {
+		if _, ok := sol.LookupVariant(v); !ok {
+			fmt.Fprintf(os.Stderr, "Don't know how to play '%s'\n", v)
+			os.Exit(2)
+		}
+	}
+	ps := strings.Split(players, ",")
+	if groups != "" {
+		ps = append(ps, groups)
+	}
+	for _, p := range ps {
+		if _, ok := sol.Players[p]; !ok {
+			fmt.Fprintf(os.Stderr, "Don't know a player called '%s'\n", p)
+			os.Exit(2)
+		}
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	var results []sol.TournamentResult
+	for _, v := range vs {
+		for _, r := range sol.Tournament(ctx, []string{v}, dedupe(ps), deals, seed) {
+			fmt.Println(r)
+			results = append(results, r)
+		}
+	}
+
+	if groups != "" {
+		easier, harder := sol.EasierAndHarder(results, groups, easy, hard)
+		fmt.Printf("\"> Easier\": {%s},\n", quote(easier))
+		fmt.Printf("\"> Harder\": {%s},\n", quote(harder))
+	}
+}
+
+func dedupe(strs []string) []string {
+	var seen = make(map[string]bool)
+	var out []string
+	for _, s := range strs {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func quote(strs []string) string {
+	var quoted []string
+	for _, s := range strs {
+		quoted = append(quoted, fmt.Sprintf("%q", s))
+	}
+	return strings.Join(quoted, ", ")
+}
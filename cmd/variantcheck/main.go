@@ -0,0 +1,100 @@
+// Command variantcheck checks variant definition files and scripts before they are put in the
+// config directory's variants folder, and deals a game of each one to make sure it plays.
+//
+//	$ go run ./cmd/variantcheck sol/testdata/variants/*.json sol/testdata/variants/*.lua
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"oddstream.games/gosol/sol"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, "usage: variantcheck file.json|file.lua ...")
+		os.Exit(2)
+	}
+
+	var failed bool
+	for _, fname := range os.Args[1:] {
+		if err := check(fname); err != nil {
+			fmt.Printf("%s: %s\n", fname, err)
+			failed = true
+		} else {
+			fmt.Printf("%s: ok\n", fname)
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// errorCatcher is a headless front end that remembers the errors a game toasts,
+// which is how a script that goes wrong while being played is reported
+type errorCatcher struct {
+	errs []string
+}
+
+func (ec *errorCatcher) Toast(soundEffect string, message string) {
+	if soundEffect == "Error" {
+		ec.errs = append(ec.errs, message)
+	}
+}
+func (*errorCatcher) PlaySound(string) {}
+func (*errorCatcher) CardsChanged()    {}
+func (*errorCatcher) PilesChanged()    {}
+func (*errorCatcher) AfterUserMove()   {}
+
+// check parses a variant definition or script, adds it, and plays a few moves of a deal of it
+func check(fname string) error {
+	data, err := os.ReadFile(fname)
+	if err != nil {
+		return err
+	}
+	var variant string
+	if filepath.Ext(fname) == ".lua" {
+		s, err := sol.ParseVariantScript(filepath.Base(fname), data)
+		if err != nil {
+			return err
+		}
+		if err := sol.AddVariantScript(s); err != nil {
+			return err
+		}
+		variant = s.Name()
+	} else {
+		def, err := sol.ParseVariantDef(data)
+		if err != nil {
+			return err
+		}
+		if err := sol.AddVariant(def); err != nil {
+			return err
+		}
+		variant = def.Name
+	}
+	settings := sol.DefaultSettings()
+	settings.AutoCollect = false
+	b := sol.NewBaize(variant, settings)
+	if b == nil {
+		return fmt.Errorf("cannot make a baize for %s", variant)
+	}
+	ec := &errorCatcher{}
+	b.SetObserver(ec)
+	b.StartFreshGame()
+	b.NewDealFromSeed(1)
+	p := sol.NewRandomPlayer(1)
+	for i := 0; i < 100 && !b.Complete(); i++ {
+		m, ok := p.ChooseMove(b)
+		if !ok || !b.ApplyMove(m) {
+			break
+		}
+	}
+	if len(ec.errs) > 0 {
+		return errors.New(strings.Join(ec.errs, "; "))
+	}
+	return nil
+}
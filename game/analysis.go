@@ -0,0 +1,143 @@
+package game
+
+import (
+	"context"
+	"fmt"
+
+	"oddstream.games/gosol/sol"
+	"oddstream.games/gosol/ui"
+)
+
+// analysisRun runs the solver backwards over the game on a goroutine of its own,
+// to find where the game was lost
+type analysisRun struct {
+	position *sol.SavableBaize // the top of the undo stack when the analysis started
+	analysis *sol.Analysis
+	cancel   context.CancelFunc
+	done     chan struct{}
+	finished bool
+}
+
+// StartAnalysis starts looking for the move that lost the game,
+// or shows the result again if the game hasn't changed since it was found
+func (g *Game) StartAnalysis() {
+	if g.Baize.Complete() {
+		g.UI.ToastError("The game has been won")
+		return
+	}
+	if g.Baize.MovesMade() == 0 {
+		g.UI.ToastError("No moves have been made yet")
+		return
+	}
+	if run := g.analysis; run != nil && run.position == g.Baize.UndoPeek() {
+		if run.finished {
+			g.showAnalysis()
+		} else {
+			g.UI.ToastInfo("Still looking for where it went wrong")
+		}
+		return
+	}
+	g.cancelAnalysis()
+	ctx, cancel := context.WithCancel(context.Background())
+	run := &analysisRun{
+		position: g.Baize.UndoPeek(),
+		analysis: g.Baize.NewAnalysis(),
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+	go func() {
+		run.analysis.Analyse(ctx)
+		close(run.done)
+	}()
+	g.analysis = run
+	g.UI.Toast("Glass", fmt.Sprintf("Looking back over %d positions", run.analysis.Positions()))
+}
+
+func (g *Game) cancelAnalysis() {
+	if g.analysis != nil {
+		g.analysis.cancel()
+		g.analysis = nil
+	}
+}
+
+// updateAnalysis is called every tick, to see if the analysis has finished
+func (g *Game) updateAnalysis() {
+	if g.analysis == nil || g.analysis.finished {
+		return
+	}
+	select {
+	case <-g.analysis.done:
+	default:
+		return
+	}
+	if g.analysis.position != g.Baize.UndoPeek() {
+		g.analysis = nil // the player has moved on
+		return
+	}
+	g.analysis.finished = true
+	g.showAnalysis()
+}
+
+// showAnalysis opens a drawer saying where the game was lost, and offers to go back there
+func (g *Game) showAnalysis() {
+	a := g.analysis.analysis
+	var strs []string
+	var actions []ui.Action
+	switch {
+	case a.StillWinnable():
+		strs = append(strs, "This game can still be won")
+		if a.BetterKnown {
+			strs = append(strs, "Try: "+a.BetterDescription)
+			actions = append(actions, ui.Action{Text: "Show me", Command: "AnalysisShowBetter"})
+		}
+	case a.LastWinnable < 0:
+		strs = append(strs, "The solver couldn't find a position in this game that could be won")
+	default:
+		if a.LastWinnable == 0 {
+			strs = append(strs, "The deal could be won, but the first move lost it")
+		} else {
+			strs = append(strs, fmt.Sprintf("The game could still be won after move %d", a.LastWinnable))
+		}
+		if a.LosingKnown {
+			strs = append(strs, "Then: "+a.LosingDescription)
+		}
+		if a.BetterKnown {
+			strs = append(strs, "Instead: "+a.BetterDescription)
+		}
+		actions = append(actions, ui.Action{Text: fmt.Sprintf("Rewind to move %d", a.LastWinnable), Command: "AnalysisRewind"})
+		if a.LosingKnown {
+			actions = append(actions, ui.Action{Text: "Rewind and show the move that lost", Command: "AnalysisShowLosing"})
+		}
+	}
+	if a.Undecided > 0 {
+		strs = append(strs, fmt.Sprintf("The solver couldn't decide about %d later positions, so the game may have been lost after that", a.Undecided))
+	}
+	strs = append(strs, fmt.Sprintf("Solver positions: %d", a.Nodes))
+	g.UI.ShowAnalysisDrawer(strs, actions)
+}
+
+// analysisCommand carries out an action from the analysis drawer
+func (g *Game) analysisCommand(command string) {
+	if g.analysis == nil || !g.analysis.finished {
+		return
+	}
+	a := g.analysis.analysis
+	switch command {
+	case "AnalysisShowBetter":
+		if g.analysis.position == g.Baize.UndoPeek() {
+			g.showMove(a.Better)
+		}
+	case "AnalysisRewind", "AnalysisShowLosing":
+		if a.LastWinnable < 0 || a.LastWinnable >= g.Baize.MovesMade() {
+			return
+		}
+		g.Baize.RewindTo(a.LastWinnable)
+		g.cancelAnalysis()
+		// let the cards get back to where they were before showing a move
+		if command == "AnalysisShowLosing" {
+			g.pendingHint = &a.Losing
+		} else if a.BetterKnown {
+			g.pendingHint = &a.Better
+		}
+	}
+}
@@ -0,0 +1,704 @@
+package game
+
+import (
+	"fmt"
+	"image"
+	"log"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"oddstream.games/gosol/input"
+	"oddstream.games/gosol/sol"
+	"oddstream.games/gosol/sound"
+	"oddstream.games/gosol/ui"
+)
+
+//--+----1----+----2----+----3----+----4----+----5----+----6----+----7----+----8
+
+func (g *Game) flagSet(flag uint32) bool {
+	return g.dirtyFlags&flag == flag
+}
+
+func (g *Game) setFlag(flag uint32) {
+	g.dirtyFlags |= flag
+}
+
+// sol.Observer interface
+
+// Toast shows a message from the baize to the player
+func (g *Game) Toast(soundEffect string, message string) {
+	g.UI.Toast(soundEffect, message)
+}
+
+// PlaySound plays a sound effect requested by the baize
+func (g *Game) PlaySound(name string) {
+	sound.Play(name)
+}
+
+// CardsChanged is called by the baize when cards have moved or flipped
+func (g *Game) CardsChanged() {
+	g.setFlag(dirtyCardPositions)
+}
+
+// PilesChanged is called by the baize when pile labels or recycles have changed
+func (g *Game) PilesChanged() {
+	g.setFlag(dirtyPileBackgrounds)
+}
+
+// AfterUserMove is called by the baize after the player has made a move
+func (g *Game) AfterUserMove() {
+	g.UI.HideFAB()
+	if g.Baize.Complete() {
+		g.UI.AddButtonToFAB("star", ebiten.KeyN)
+		g.StartSpinning()
+		if !g.demoPlayed {
+			var toastStr = g.Statistics.RecordWonGame(g.Baize)
+			g.UI.Toast("Complete", toastStr)
+			ShowStatisticsDrawer()
+		}
+	} else if g.Baize.Conformant() {
+		g.UI.AddButtonToFAB("done_all", ebiten.KeyC)
+	} else if g.Baize.Moves() == 0 {
+		g.UI.ToastError("No movable cards")
+		g.UI.AddButtonToFAB("star", ebiten.KeyN)
+		g.UI.AddButtonToFAB("restore", ebiten.KeyR)
+		// the FAB has room for three buttons
+		if g.Baize.Bookmarked() {
+			g.UI.AddButtonToFAB("bookmark", ebiten.KeyL)
+		} else {
+			g.UI.AddButtonToFAB("lightbulb", ebiten.KeyW)
+		}
+	}
+}
+
+// startVariant creates a new baize for a variant, and loads any saved game of it
+func (g *Game) startVariant(variant string) bool {
+	var b *sol.Baize = sol.NewBaize(variant, g.Settings)
+	if b == nil {
+		return false
+	}
+	g.replaceBaize(b)
+	if !NoGameLoad && g.Baize.Load() {
+		g.afterLoad()
+	}
+	return true
+}
+
+// replaceBaize stops whatever the old baize was doing, and starts a fresh game on a new one
+func (g *Game) replaceBaize(b *sol.Baize) {
+	if g.Baize != nil {
+		g.StopSpinning()
+	}
+	g.cancelDealSearch()
+	g.cancelAnalysis()
+	g.StopDemo("")
+	g.demoPlayed = false
+	g.pendingHint = nil
+	g.Baize = b
+	g.Baize.SetObserver(g)
+	g.startFreshGame()
+}
+
+// startFreshGame rebuilds the piles of the current baize and deals
+func (g *Game) startFreshGame() {
+	g.Baize.StartFreshGame()
+	g.UI.SetTitle(g.Baize.Variant())
+	g.dirtyFlags = 0xFFFF
+}
+
+// afterLoad sets up the FAB for a game that has just been loaded
+func (g *Game) afterLoad() {
+	g.UI.HideFAB()
+	if g.Baize.Complete() {
+		g.UI.Toast("Complete", "Complete")
+		g.UI.AddButtonToFAB("star", ebiten.KeyN)
+		g.StartSpinning()
+	} else if g.Baize.Conformant() {
+		g.UI.AddButtonToFAB("done_all", ebiten.KeyC)
+	} else if g.Baize.Moves() == 0 {
+		g.UI.Toast("Error", "No movable cards")
+		g.UI.AddButtonToFAB("star", ebiten.KeyN)
+		g.UI.AddButtonToFAB("restore", ebiten.KeyR)
+		// the FAB has room for three buttons
+		if g.Baize.Bookmarked() {
+			g.UI.AddButtonToFAB("bookmark", ebiten.KeyL)
+		} else {
+			g.UI.AddButtonToFAB("lightbulb", ebiten.KeyW)
+		}
+	}
+}
+
+// NewDeal records an abandoned game, if there is one, and deals again.
+// The deal is rated while it is being dealt and, if the player only wants winnable deals,
+// replaced if the solver can't win it.
+func (g *Game) NewDeal() {
+	g.NewDealFromSeed(sol.NewSeed())
+	g.startDealSearch(g.Settings.WinnableDealsOnly(g.Baize.Variant()), "")
+}
+
+// Undo takes back the last move, and offers to make it again
+func (g *Game) Undo() {
+	g.Baize.Undo()
+	g.offerRedo()
+}
+
+// Redo makes the last move that was undone again
+func (g *Game) Redo() {
+	g.Baize.Redo()
+	g.offerRedo()
+}
+
+// offerRedo puts a redo button on the FAB while there are moves that can be redone
+func (g *Game) offerRedo() {
+	g.UI.HideFAB()
+	if g.Baize.CanRedo() {
+		g.UI.AddButtonToFAB("redo", ebiten.KeyY)
+	}
+}
+
+// NewRatedDeal looks for an easy, medium or hard deal, and deals it when it is found
+func (g *Game) NewRatedDeal(rating string) {
+	g.UI.Toast("Glass", fmt.Sprintf("Looking for %s %s deal", article(rating), rating))
+	g.startDealSearch(true, rating)
+}
+
+// NewDealFromSeed records an abandoned game, if there is one, and deals deal #seed
+func (g *Game) NewDealFromSeed(seed uint64) {
+	if g.Baize.MovesMade() > 0 && !g.Baize.Complete() && !g.demoPlayed {
+		toastStr := g.Statistics.RecordLostGame(g.Baize)
+		g.UI.Toast("Fail", toastStr)
+	}
+	g.demoPlayed = false
+	g.cancelDealSearch()
+	g.cancelAnalysis()
+	g.pendingHint = nil
+	g.StopSpinning()
+	g.Baize.NewDealFromSeed(seed)
+}
+
+func (g *Game) ChangeVariant(newVariant string) {
+	// no longer record a lost game here because variants saved in separate .json files
+	if _, ok := sol.LookupVariant(newVariant); !ok {
+		g.UI.Toast("Error", "Do not know how to play "+newVariant)
+		return
+	}
+	g.Baize.Save()
+	g.Settings.Variant = newVariant
+	g.Settings.Save()
+	g.startVariant(newVariant)
+}
+
+// FindPileAt finds the Pile under the mouse position;
+// piles are drawn in order, so where piles overlap (as in Pyramid) the last one is on top
+func (g *Game) FindPileAt(pt image.Point) *sol.Pile {
+	piles := g.Baize.Piles()
+	for j := len(piles) - 1; j >= 0; j-- {
+		p := piles[j]
+		if pt.In(g.pileView(p).ScreenRect()) {
+			return p
+		}
+	}
+	return nil
+}
+
+// FindLowestCardAt finds the bottom-most Card under the mouse position
+func (g *Game) FindLowestCardAt(pt image.Point) *sol.Card {
+	piles := g.Baize.Piles()
+	for j := len(piles) - 1; j >= 0; j-- {
+		p := piles[j]
+		for i := p.Len() - 1; i >= 0; i-- {
+			c := p.Get(i)
+			if pt.In(g.cardView(c).ScreenRect()) {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+// FindHighestCardAt finds the top-most Card under the mouse position
+func (g *Game) FindHighestCardAt(pt image.Point) *sol.Card {
+	for _, p := range g.Baize.Piles() {
+		for _, c := range p.Cards() {
+			if pt.In(g.cardView(c).ScreenRect()) {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+func (g *Game) LargestIntersection(c *sol.Card) *sol.Pile {
+	var largestArea int = 0
+	var pile *sol.Pile = nil
+	cardRect := g.cardView(c).BaizeRect()
+	for _, p := range g.Baize.Piles() {
+		if p == c.Owner() {
+			continue
+		}
+		pileRect := g.pileView(p).FannedBaizeRect()
+		intersectRect := pileRect.Intersect(cardRect)
+		area := intersectRect.Dx() * intersectRect.Dy()
+		if area > largestArea {
+			largestArea = area
+			pile = p
+		}
+	}
+	return pile
+}
+
+// StartDrag return true if the Baize can be dragged
+func (g *Game) StartDrag() bool {
+	g.dragStart = g.dragOffset
+	return true
+}
+
+// DragBy move ('scroll') the Baize by dragging it
+// dx, dy is the difference between where the drag started and where the cursor is now
+func (g *Game) DragBy(dx, dy int) {
+	g.dragOffset.X = g.dragStart.X + dx
+	if g.dragOffset.X > 0 {
+		g.dragOffset.X = 0 // DragOffsetX should only ever be 0 or -ve
+	}
+	g.dragOffset.Y = g.dragStart.Y + dy
+	if g.dragOffset.Y > 0 {
+		g.dragOffset.Y = 0 // DragOffsetY should only ever be 0 or -ve
+	}
+}
+
+// StopDrag stop dragging the Baize
+func (g *Game) StopDrag() {
+	g.setFlag(dirtyCardPositions)
+}
+
+// StartSpinning tells all the cards to start spinning
+func (g *Game) StartSpinning() {
+	g.Baize.ForeachCard(func(c *sol.Card) {
+		c.ClearTap()
+		g.cardView(c).StartSpinning()
+	})
+}
+
+// StopSpinning tells all the cards to stop spinning and return to their upright position
+func (g *Game) StopSpinning() {
+	for _, cv := range g.cardViews {
+		cv.StopSpinning()
+	}
+	g.setFlag(dirtyCardPositions)
+}
+
+/*
+InputStart finds out what object the user input is starting on
+(UI Container > Card > Pile > Baize, in that order)
+then tells that object.
+
+If the Input starts on a Card, then a tail of cards is formed.
+*/
+func (g *Game) InputStart(v input.StrokeEvent) {
+	g.stroke = v.Stroke
+
+	if con := g.UI.FindContainerAt(v.X, v.Y); con != nil {
+		if w := con.FindWidgetAt(v.X, v.Y); w != nil {
+			g.stroke.SetDraggedObject(w)
+		} else {
+			con.StartDrag()
+			g.stroke.SetDraggedObject(con)
+		}
+	} else {
+		// touching the baize takes over from the demo
+		g.StopDemo("Demo stopped")
+		pt := image.Pt(v.X, v.Y)
+		if card := g.FindLowestCardAt(pt); card != nil {
+			if g.cardView(card).Lerping() {
+				g.UI.Toast("Glass", "Confusing to move a moving card")
+				v.Stroke.Cancel()
+			} else {
+				tail := card.Owner().MakeTail(card)
+				g.StartTailDrag(tail)
+				g.stroke.SetDraggedObject(tail)
+			}
+		} else {
+			if p := g.FindPileAt(pt); p != nil {
+				g.stroke.SetDraggedObject(p)
+			} else {
+				if g.StartDrag() {
+					g.stroke.SetDraggedObject(g)
+				} else {
+					v.Stroke.Cancel()
+				}
+			}
+		}
+	}
+}
+
+func (g *Game) InputMove(v input.StrokeEvent) {
+	if v.Stroke.DraggedObject() == nil {
+		return
+		// log.Panic("*** move stroke with nil dragged object ***")
+	}
+	switch obj := v.Stroke.DraggedObject().(type) {
+	case ui.Containery:
+		obj.DragBy(v.Stroke.PositionDiff())
+	case ui.Widgety:
+		obj.Parent().DragBy(v.Stroke.PositionDiff())
+	case []*sol.Card:
+		dx, dy := v.Stroke.PositionDiff()
+		g.DragTailBy(obj, dx, dy)
+	case *sol.Pile:
+		// do nothing
+	case *Game:
+		g.DragBy(v.Stroke.PositionDiff())
+	default:
+		log.Panic("*** unknown move dragging object ***")
+	}
+}
+
+func (g *Game) InputStop(v input.StrokeEvent) {
+	if v.Stroke.DraggedObject() == nil {
+		return
+		// log.Panic("*** stop stroke with nil dragged object ***")
+	}
+	switch obj := v.Stroke.DraggedObject().(type) {
+	case ui.Containery:
+		obj.StopDrag()
+	case ui.Widgety:
+		obj.Parent().StopDrag()
+	case []*sol.Card:
+		tail := obj     // alias for readability
+		card := tail[0] // for readability
+		if g.cardView(card).WasDragged() {
+			// tap handled elsewhere
+			// tap is time-limited
+			if dst := g.LargestIntersection(card); dst == nil {
+				// println("no intersection for", c.String())
+				g.CancelTailDrag(tail)
+			} else {
+				if ok, err := g.Baize.DropTail(tail, dst); !ok {
+					g.UI.ToastError(err.Error())
+					g.CancelTailDrag(tail)
+				} else {
+					// cards that didn't go anywhere will be refanned back into place
+					g.StopTailDrag(tail)
+					g.setFlag(dirtyCardPositions)
+				}
+			}
+		}
+		if sol.DebugMode && g.cardView(card).Dragging() {
+			log.Printf("Card %s is still dragging", card.String())
+		}
+	case *sol.Pile:
+		// do nothing
+	case *Game:
+		// println("stop dragging baize")
+		g.StopDrag()
+	default:
+		log.Panic("*** stop dragging unknown object ***")
+	}
+}
+
+func (g *Game) InputCancel(v input.StrokeEvent) {
+	if v.Stroke.DraggedObject() == nil {
+		log.Print("*** cancel stroke with nil dragged object ***")
+		return
+	}
+	switch obj := v.Stroke.DraggedObject().(type) { // type switch
+	case ui.Containery:
+		obj.CancelDrag()
+	case ui.Widgety:
+		obj.Parent().CancelDrag()
+	case []*sol.Card:
+		g.CancelTailDrag(obj)
+	case *sol.Pile:
+		// do nothing
+	case *Game:
+		// println("stop dragging baize")
+		g.StopDrag()
+	default:
+		log.Panic("*** cancel dragging unknown object ***")
+	}
+}
+
+func (g *Game) InputTap(v input.StrokeEvent) {
+	// stroke sends a tap event, and later sends a cancel event
+	// println("Baize.NotifyCallback() tap", v.X, v.Y)
+	switch obj := v.Stroke.DraggedObject().(type) {
+	case ui.Containery:
+		obj.Tapped()
+	case ui.Widgety:
+		obj.Tapped()
+	case []*sol.Card:
+		if g.Baize.TailTapped(obj) {
+			sound.Play("Slide")
+		} else {
+			g.UI.Toast("Error", "Attention!")
+		}
+	case *sol.Pile:
+		if g.Baize.PileTapped(obj) {
+			sound.Play("Shove")
+		}
+	case *Game:
+		pt := image.Pt(v.X, v.Y)
+		// a tap outside any open ui drawer (ie on the baize) closes the drawer
+		if con := g.UI.VisibleDrawer(); con != nil && !pt.In(image.Rect(con.Rect())) {
+			con.Hide()
+		}
+	default:
+		log.Panic("*** tap unknown object ***")
+	}
+}
+
+// NotifyCallback is called by the Subject (Input/Stroke) when something interesting happens
+func (g *Game) NotifyCallback(v input.StrokeEvent) {
+	switch v.Event {
+	case input.Start:
+		g.InputStart(v)
+	case input.Move:
+		g.InputMove(v)
+	case input.Stop:
+		g.InputStop(v)
+	case input.Cancel:
+		g.InputCancel(v)
+	case input.Tap:
+		g.InputTap(v)
+	default:
+		log.Panic("*** unknown stroke event ***", v.Event)
+	}
+}
+
+// ApplyToTail applies a method func to this card and all the others after it in the tail
+func (g *Game) ApplyToTail(tail []*sol.Card, fn func(*cardView)) {
+	// https://golang.org/ref/spec#Method_expressions
+	// (*cardView).CancelDrag yields a function with the signature func(*cardView)
+	// fn passed as a method expression so add the receiver explicitly
+	for _, c := range tail {
+		fn(g.cardView(c))
+	}
+}
+
+// DragTailBy repositions all the cards in the tail: dx, dy is the position difference from the start of the drag
+func (g *Game) DragTailBy(tail []*sol.Card, dx, dy int) {
+	for _, c := range tail {
+		g.cardView(c).DragBy(dx, dy)
+	}
+}
+
+func (g *Game) StartTailDrag(tail []*sol.Card) {
+	// hiding the mouse cursor creates flickering when tapping
+	// ebiten.SetCursorMode(ebiten.CursorModeHidden)
+	g.ApplyToTail(tail, (*cardView).StartDrag)
+}
+
+func (g *Game) StopTailDrag(tail []*sol.Card) {
+	// ebiten.SetCursorMode(ebiten.CursorModeVisible)
+	g.ApplyToTail(tail, (*cardView).StopDrag)
+}
+
+func (g *Game) CancelTailDrag(tail []*sol.Card) {
+	// ebiten.SetCursorMode(ebiten.CursorModeVisible)
+	g.ApplyToTail(tail, (*cardView).CancelDrag)
+}
+
+func (g *Game) MaxSlotX() int {
+	var maxX int
+	for _, p := range g.Baize.Piles() {
+		if p.Slot().X > maxX {
+			maxX = p.Slot().X
+		}
+	}
+	return maxX
+}
+
+// ScaleCards calculates new width/height of cards and margins
+// returns true if changes were made
+func (g *Game) ScaleCards() bool {
+
+	// const (
+	// 	DefaultRatio = 1.444
+	// 	BridgeRatio  = 1.561
+	// 	PokerRatio   = 1.39
+	// 	OpsoleRatio  = 1.5556 // 3.5/2.25
+	// )
+
+	var OldWidth = CardWidth
+	var OldHeight = CardHeight
+
+	var maxX int = g.MaxSlotX()
+
+	/*
+		71 x 96 = 1:1.352 (Microsoft retro)
+		140 x 190 = 1:1.357 (kenney, large)
+		64 x 89 = 1:1.390 (official poker size)
+		90 x 130 = 1:1.444 (nice looking scalable)
+		89 x 137 = 1:1.539 (measured real card)
+		57 x 89 = 1:1.561 (official bridge size)
+	*/
+
+	// Card padding is 10% of card height/width
+
+	// "add" two extra piles and a LeftMargin to make a half-card-width border
+
+	var slotWidth, slotHeight float64
+	slotWidth = float64(g.WindowWidth) / float64(maxX+2)
+	slotHeight = slotWidth * g.Settings.CardRatio
+
+	PilePaddingX = int(slotWidth / 10)
+	CardWidth = int(slotWidth) - PilePaddingX
+	PilePaddingY = int(slotHeight / 10)
+	CardHeight = int(slotHeight) - PilePaddingY
+
+	TopMargin = ui.ToolbarHeight + CardHeight/3
+	LeftMargin = (CardWidth / 2) + PilePaddingX
+
+	CardCornerRadius = float64(CardWidth) / 10.0 // same as lsol
+
+	return CardWidth != OldWidth || CardHeight != OldHeight
+}
+
+func (g *Game) UpdateToolbar() {
+	g.UI.EnableWidget("toolbarUndo", g.Baize.MovesMade() > 0)
+	g.UI.EnableWidget("toolbarRedo", g.Baize.CanRedo())
+	g.UI.EnableWidget("toolbarCollect", g.Baize.FoundationMoves() > 0)
+}
+
+func (g *Game) UpdateStatusbar() {
+	if g.Baize.Script().Stock().Hidden() {
+		g.UI.SetStock(-1)
+	} else {
+		g.UI.SetStock(g.Baize.Script().Stock().Len())
+	}
+	if g.Baize.Script().Waste() == nil {
+		g.UI.SetWaste(-1) // previous variant may have had a waste, and this one does not
+	} else {
+		g.UI.SetWaste(g.Baize.Script().Waste().Len())
+	}
+	if g.Baize.Seed() == 0 {
+		// a game saved before deals had numbers
+		g.UI.SetMiddle(fmt.Sprintf("MOVES: %d", g.Baize.MovesMade()))
+	} else {
+		g.UI.SetMiddle(fmt.Sprintf("DEAL: %d MOVES: %d", g.Baize.Seed(), g.Baize.MovesMade()))
+	}
+	g.UI.SetPercent(g.Baize.PercentComplete())
+}
+
+func (g *Game) UpdateDrawers() {
+	g.UI.EnableWidget("restartDeal", g.Baize.MovesMade() > 0)
+	g.UI.EnableWidget("gotoBookmark", g.Baize.Bookmarked())
+}
+
+// layoutBaize does the work of ebiten.Game's Layout for the baize
+func (g *Game) layoutBaize(outsideWidth, outsideHeight int) {
+
+	if outsideWidth == 0 || outsideHeight == 0 {
+		log.Println("Baize.Layout called with zero dimension")
+		return
+	}
+
+	if sol.DebugMode && (outsideWidth != g.WindowWidth || outsideHeight != g.WindowHeight) {
+		log.Println("Window resize to", outsideWidth, outsideHeight)
+	}
+
+	if outsideWidth != g.WindowWidth {
+		g.setFlag(dirtyWindowSize | dirtyCardSizes | dirtyPileBackgrounds | dirtyPilePositions | dirtyCardPositions)
+		g.WindowWidth = outsideWidth
+	}
+	if outsideHeight != g.WindowHeight {
+		g.setFlag(dirtyWindowSize | dirtyCardPositions)
+		g.WindowHeight = outsideHeight
+	}
+
+	if g.dirtyFlags != 0 {
+		if g.flagSet(dirtyCardSizes) {
+			if g.ScaleCards() {
+				if sol.DebugMode {
+					log.Printf("ScaleCards %dx%d", CardWidth, CardHeight)
+				}
+				g.setFlag(dirtyCardImages | dirtyPilePositions | dirtyPileBackgrounds)
+			}
+		}
+		if g.flagSet(dirtyCardImages) {
+			CreateCardImages()
+		}
+		if g.flagSet(dirtyPilePositions) {
+			// the baize may have been rebuilt, so forget about the old piles
+			g.pileViews = make(map[*sol.Pile]*pileView)
+			for _, p := range g.Baize.Piles() {
+				g.pileView(p).SetBaizePos(image.Point{
+					X: LeftMargin + (p.Slot().X * (CardWidth + PilePaddingX)) + (p.Offset().X * (CardWidth + PilePaddingX) / 10),
+					Y: TopMargin + (p.Slot().Y * (CardHeight + PilePaddingY)) + (p.Offset().Y * (CardHeight + PilePaddingY) / 10),
+				})
+			}
+		}
+		if g.flagSet(dirtyPileBackgrounds) {
+			if !(CardWidth == 0 || CardHeight == 0) {
+				for _, p := range g.Baize.Piles() {
+					if !p.Hidden() {
+						pv := g.pileView(p)
+						pv.img = pv.Placeholder()
+					}
+				}
+			}
+		}
+		if g.flagSet(dirtyWindowSize) {
+			g.UI.Layout(outsideWidth, outsideHeight)
+		}
+		if g.flagSet(dirtyCardPositions) {
+			g.forgetLostCards()
+			for _, p := range g.Baize.Piles() {
+				g.pileView(p).Scrunch()
+			}
+			g.UpdateToolbar()
+			g.UpdateDrawers()
+			g.UpdateStatusbar()
+			if !g.Settings.AlwaysShowMovableCards {
+				g.Settings.ShowMovableCards = false
+			}
+		}
+		g.dirtyFlags = 0
+	}
+}
+
+// updateBaize updates the baize state (transitions, user input)
+func (g *Game) updateBaize() {
+
+	if g.stroke == nil {
+		input.StartStroke(g) // this will set g.stroke when "start" received
+	} else {
+		g.stroke.Update()
+		if g.stroke.IsReleased() || g.stroke.IsCancelled() {
+			g.stroke = nil
+		}
+	}
+
+	for _, cv := range g.cardViews {
+		cv.Update()
+	}
+
+	if g.naming {
+		g.typeBookmarkName() // instead of running commands
+		return
+	}
+	for k := ebiten.Key(0); k <= ebiten.KeyMax; k++ {
+		if inpututil.IsKeyJustReleased(k) {
+			Execute(k)
+		}
+	}
+}
+
+// drawBaize renders the baize into the screen
+func (g *Game) drawBaize(screen *ebiten.Image) {
+
+	screen.Fill(ExtendedColors[g.Settings.BaizeColor])
+
+	for _, p := range g.Baize.Piles() {
+		g.pileView(p).Draw(screen)
+	}
+	for _, p := range g.Baize.Piles() {
+		g.pileView(p).DrawStaticCards(screen)
+	}
+	for _, p := range g.Baize.Piles() {
+		g.pileView(p).DrawAnimatingCards(screen)
+	}
+	for _, p := range g.Baize.Piles() {
+		g.pileView(p).DrawDraggingCards(screen)
+	}
+}
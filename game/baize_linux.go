@@ -0,0 +1,16 @@
+package game
+
+import (
+	"log"
+	"os/exec"
+)
+
+func (g *Game) Wikipedia() {
+	var cmd *exec.Cmd = exec.Command("xdg-open", g.Baize.Script().Wikipedia())
+	if cmd != nil {
+		err := cmd.Start()
+		if err != nil {
+			log.Println(err)
+		}
+	}
+}
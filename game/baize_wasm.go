@@ -0,0 +1,9 @@
+package game
+
+import (
+	"syscall/js"
+)
+
+func (g *Game) Wikipedia() {
+	js.Global().Get("window").Call("open", g.Baize.Script().Wikipedia())
+}
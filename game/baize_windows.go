@@ -1,12 +1,12 @@
-package sol
+package game
 
 import (
 	"log"
 	"os/exec"
 )
 
-func (b *Baize) Wikipedia() {
-	err := exec.Command("rundll32", "url.dll,FileProtocolHandler", b.script.Wikipedia()).Start()
+func (g *Game) Wikipedia() {
+	err := exec.Command("rundll32", "url.dll,FileProtocolHandler", g.Baize.Script().Wikipedia()).Start()
 	if err != nil {
 		log.Println(err)
 	}
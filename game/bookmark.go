@@ -0,0 +1,150 @@
+package game
+
+import (
+	"fmt"
+	"image"
+	"strconv"
+	"strings"
+
+	"github.com/fogleman/gg"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"oddstream.games/gosol/sol"
+	"oddstream.games/gosol/ui"
+)
+
+// thumbnailWidth is the width of a picture of the baize in the bookmark drawer
+const thumbnailWidth = 120
+
+// ShowBookmarkDrawer lists the bookmarks in this game, with a picture of the baize at each
+func (g *Game) ShowBookmarkDrawer() {
+	var thumbnails []ui.Thumbnail
+	for i, bm := range g.Baize.Bookmarks() {
+		thumbnails = append(thumbnails, ui.Thumbnail{
+			Text:    bm.Name,
+			Img:     g.thumbnail(g.Baize.BookmarkPosition(i)),
+			Command: "GotoBookmark",
+			Data:    strconv.Itoa(i),
+		})
+	}
+	actions := []ui.Action{{Text: "Name a new bookmark...", Command: "NameBookmark"}}
+	g.UI.ShowBookmarkDrawer(thumbnails, actions)
+}
+
+// gotoBookmark goes to the bookmark chosen in the bookmark drawer
+func (g *Game) gotoBookmark(data string) {
+	if i, err := strconv.Atoi(data); err == nil {
+		g.Baize.GotoBookmark(i)
+	}
+}
+
+// thumbnail draws a small picture of a position, with each card a rectangle
+// that is red or black if it is face up, or the color of the card back if not
+func (g *Game) thumbnail(sb *sol.SavableBaize) image.Image {
+	var maxSlot image.Point
+	for _, p := range g.Baize.Piles() {
+		if !p.Hidden() {
+			if p.Slot().X > maxSlot.X {
+				maxSlot.X = p.Slot().X
+			}
+			if p.Slot().Y > maxSlot.Y {
+				maxSlot.Y = p.Slot().Y
+			}
+		}
+	}
+	slotWidth := float64(thumbnailWidth) / float64(maxSlot.X+1)
+	slotHeight := slotWidth * 1.4
+	cardWidth, cardHeight := slotWidth*0.9, slotHeight*0.9
+	height := int(slotHeight * float64(maxSlot.Y+2))
+
+	dc := gg.NewContext(thumbnailWidth, height)
+	dc.SetColor(ExtendedColors[g.Settings.BaizeColor])
+	dc.Clear()
+	for i, p := range g.Baize.Piles() {
+		if p.Hidden() || sb == nil || i >= len(sb.Piles) {
+			continue
+		}
+		x := (float64(p.Slot().X) + float64(p.Offset().X)/10) * slotWidth
+		y := (float64(p.Slot().Y) + float64(p.Offset().Y)/10) * slotHeight
+		cards := sb.Piles[i].Cards
+		if len(cards) == 0 {
+			dc.SetRGBA(1, 1, 1, 0.2)
+			dc.DrawRectangle(x, y, cardWidth, cardHeight)
+			dc.Fill()
+			continue
+		}
+		var dx, dy float64
+		first := 0
+		switch p.FanType() {
+		case sol.FAN_DOWN, sol.FAN_DOWN3:
+			dy = cardHeight / 5
+		case sol.FAN_RIGHT, sol.FAN_RIGHT3:
+			dx = cardWidth / 4
+		case sol.FAN_LEFT, sol.FAN_LEFT3:
+			dx = -cardWidth / 4
+		}
+		switch p.FanType() {
+		case sol.FAN_NONE:
+			first = len(cards) - 1
+		case sol.FAN_DOWN3, sol.FAN_RIGHT3, sol.FAN_LEFT3:
+			first = len(cards) - 3 // only the top three cards are fanned
+		}
+		for j, cid := range cards {
+			if j < first {
+				continue
+			}
+			switch {
+			case cid.Prone():
+				dc.SetColor(ExtendedColors[g.Settings.CardBackColor])
+			case cid.Black():
+				dc.SetRGB(0.1, 0.1, 0.1)
+			default:
+				dc.SetRGB(0.8, 0.1, 0.1)
+			}
+			dc.DrawRectangle(x, y, cardWidth, cardHeight)
+			dc.FillPreserve()
+			dc.SetRGB(1, 1, 1)
+			dc.SetLineWidth(0.5)
+			dc.Stroke()
+			x, y = x+dx, y+dy
+		}
+	}
+	return dc.Image()
+}
+
+// startNamingBookmark starts the player typing a name for a new bookmark
+func (g *Game) startNamingBookmark() {
+	if g.Baize.Complete() {
+		g.UI.ToastError("Cannot bookmark a completed game")
+		return
+	}
+	g.naming = true
+	g.bookmarkName = ""
+	g.UI.ToastInfo("Type a name for the bookmark, then Enter")
+}
+
+// typeBookmarkName takes what the player types as the name of a new bookmark,
+// until Enter makes the bookmark, or Escape forgets about it
+func (g *Game) typeBookmarkName() {
+	typed := ebiten.AppendInputChars(nil)
+	switch {
+	case inpututil.IsKeyJustReleased(ebiten.KeyEnter):
+		g.naming = false
+		g.Baize.AddBookmark(strings.TrimSpace(g.bookmarkName))
+		return
+	case inpututil.IsKeyJustReleased(ebiten.KeyEscape):
+		g.naming = false
+		g.UI.ToastInfo("Bookmark not made")
+		return
+	case inpututil.IsKeyJustReleased(ebiten.KeyBackspace):
+		if name := []rune(g.bookmarkName); len(name) > 0 {
+			g.bookmarkName = string(name[:len(name)-1])
+		}
+	case len(typed) == 0:
+		return
+	}
+	if len([]rune(g.bookmarkName))+len(typed) <= 24 {
+		g.bookmarkName += string(typed)
+	}
+	g.UI.ToastInfo(fmt.Sprintf("Bookmark name: %s", g.bookmarkName))
+}
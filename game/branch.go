@@ -0,0 +1,34 @@
+package game
+
+import (
+	"fmt"
+	"strconv"
+
+	"oddstream.games/gosol/ui"
+)
+
+// ShowBranchDrawer lists the other lines of play in this game, with a picture of the baize at the end of each
+func (g *Game) ShowBranchDrawer() {
+	if g.Baize.Branches() == 0 {
+		g.UI.ToastInfo("No other lines of play; make a different move after an undo to start one")
+		return
+	}
+	var thumbnails []ui.Thumbnail
+	for i := 0; i < g.Baize.Branches(); i++ {
+		fork, moves := g.Baize.BranchMoves(i)
+		thumbnails = append(thumbnails, ui.Thumbnail{
+			Text:    fmt.Sprintf("From move %d, %d more", fork, moves-fork),
+			Img:     g.thumbnail(g.Baize.BranchPosition(i)),
+			Command: "GotoBranch",
+			Data:    strconv.Itoa(i),
+		})
+	}
+	g.UI.ShowBranchDrawer(thumbnails)
+}
+
+// gotoBranch goes to the line of play chosen in the branch drawer
+func (g *Game) gotoBranch(data string) {
+	if i, err := strconv.Atoi(data); err == nil {
+		g.Baize.GotoBranch(i)
+	}
+}
@@ -0,0 +1,424 @@
+package game
+
+import (
+	"image"
+	"log"
+	"math/rand"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"oddstream.games/gosol/sol"
+	"oddstream.games/gosol/util"
+)
+
+/*
+	Cards have several states: idle, being dragged, transitioning, shaking, spinning, flipping.
+	You'd think that cards should have a 'state' enum, but the states can overlap (eg a card
+	can transition and flip at the same time).
+*/
+
+// cardView is how a sol.Card looks on the screen
+type cardView struct {
+	card *sol.Card
+	pos  image.Point
+
+	// lerping things
+	src           image.Point // lerp origin
+	dst           image.Point // lerp destination
+	aniSpeed      float64
+	lerpStartTime time.Time
+	lerping       bool
+	hinting       bool        // true if the lerp is showing a hint, and the card will go back to hintHome
+	hintHome      image.Point // where a hinted card goes back to
+
+	// dragging things
+	dragStart    image.Point // starting point for dragging
+	beingDragged bool        // true if this card is being dragged, or is in a dragged tail
+
+	// flipping things
+	prone         bool    // how the card was last seen, so we know when to flip it
+	flipWidth     float64 // scale of the card width while flipping
+	flipDirection int
+	flipStartTime time.Time
+
+	// spinning things
+	directionX, directionY int     // direction vector when card is spinning
+	angle, spin            float64 // current angle and spin when card is spinning
+	spinStartAfter         time.Time
+}
+
+// cardView finds (or makes) the view of a card
+func (g *Game) cardView(c *sol.Card) *cardView {
+	if g.cardViews == nil {
+		g.cardViews = make(map[*sol.Card]*cardView)
+	}
+	cv, ok := g.cardViews[c]
+	if !ok {
+		// be nice to start the cards in the middle of the screen,
+		// but the screen will be 0,0 when app starts
+		// and ebiten.WindowSize() only works on desktops
+		// so start new cards where the Stock is
+		var pos image.Point
+		if stock := g.Baize.Script().Stock(); stock != nil {
+			pos = g.pileView(stock).pos
+		}
+		cv = &cardView{card: c, pos: pos, prone: c.Prone()}
+		g.cardViews[c] = cv
+	}
+	return cv
+}
+
+// forgetLostCards drops the views of cards that are no longer on the baize (eg after a new deal)
+func (g *Game) forgetLostCards() {
+	var onBaize = make(map[*sol.Card]bool)
+	g.Baize.ForeachCard(func(c *sol.Card) { onBaize[c] = true })
+	for c := range g.cardViews {
+		if !onBaize[c] {
+			delete(g.cardViews, c)
+		}
+	}
+}
+
+// BaizePos returns the x,y baize coords of this card
+func (cv *cardView) BaizePos() image.Point {
+	return cv.pos
+}
+
+// SetBaizePos sets the position of the Card
+func (cv *cardView) SetBaizePos(pos image.Point) {
+	cv.lerping = false
+	cv.hinting = false
+	cv.pos = pos
+}
+
+// BaizeRect gives the x,y baize coords of the card's top left and bottom right corners
+func (cv *cardView) BaizeRect() image.Rectangle {
+	var r image.Rectangle
+	r.Min = cv.pos
+	r.Max = r.Min.Add(image.Point{CardWidth, CardHeight})
+	return r
+}
+
+// ScreenRect gives the x,y screen coords of the card's top left and bottom right corners
+func (cv *cardView) ScreenRect() image.Rectangle {
+	var r image.Rectangle = cv.BaizeRect()
+	r.Min = r.Min.Add(TheGame.dragOffset)
+	r.Max = r.Max.Add(TheGame.dragOffset)
+	return r
+}
+
+// LerpTo starts the transition of this Card to pos
+func (cv *cardView) LerpTo(dst image.Point) {
+
+	cv.hinting = false // a real move replaces a hint
+
+	if cv.Spinning() {
+		return
+	}
+
+	if dst.Eq(cv.pos) {
+		cv.lerping = false
+		return // we are already here
+	}
+
+	if cv.lerping && dst.Eq(cv.dst) {
+		return // repeat request to lerp to dst
+	}
+
+	cv.lerping = true
+	cv.src = cv.pos
+	cv.dst = dst
+	// refanning waste cards can flutter with slow AniSpeed, so go faster if not far to go
+	dist := util.Distance(cv.src, cv.dst)
+	if dist < float64(CardWidth) {
+		cv.aniSpeed = TheGame.Settings.AniSpeed / 2.0
+	} else {
+		cv.aniSpeed = TheGame.Settings.AniSpeed
+	}
+	cv.lerpStartTime = time.Now()
+}
+
+// Hint sends this card towards dst and back again, to show where it could be moved
+func (cv *cardView) Hint(dst image.Point) {
+	home := cv.pos
+	cv.LerpTo(dst)
+	if cv.lerping {
+		cv.hinting = true
+		cv.hintHome = home
+	}
+}
+
+// StartDrag informs card that it is being dragged
+func (cv *cardView) StartDrag() {
+	if cv.Lerping() {
+		log.Printf("StartDrag a transitioning card %s", cv.card.String())
+		// set the drag origin to the be transition destination,
+		// so that cancelling this drag will return the card
+		// to where it thought it was going
+		// doing this will be trapped by Baize, so this is belt-n-braces
+		if cv.hinting {
+			cv.dragStart = cv.hintHome
+		} else {
+			cv.dragStart = cv.dst
+		}
+	} else {
+		cv.dragStart = cv.pos
+	}
+	cv.beingDragged = true
+}
+
+// DragBy repositions the card by the distance it has been dragged
+func (cv *cardView) DragBy(dx, dy int) {
+	cv.SetBaizePos(cv.dragStart.Add(image.Point{dx, dy}))
+}
+
+// StopDrag informs card that it is no longer being dragged
+func (cv *cardView) StopDrag() {
+	cv.beingDragged = false
+}
+
+// CancelDrag informs card that it is no longer being dragged
+func (cv *cardView) CancelDrag() {
+	cv.beingDragged = false
+	cv.LerpTo(cv.dragStart)
+}
+
+// WasDragged returns true of this card has been dragged
+func (cv *cardView) WasDragged() bool {
+	return !cv.pos.Eq(cv.dragStart)
+}
+
+// syncProne starts a flip if the card has been turned over since we last looked
+func (cv *cardView) syncProne() {
+	if cv.prone != cv.card.Prone() {
+		cv.prone = cv.card.Prone()
+		cv.startFlip()
+	}
+}
+
+func (cv *cardView) startFlip() {
+	cv.flipWidth = 1.0    // card starts full width
+	cv.flipDirection = -1 // start by making card narrower
+	cv.flipStartTime = time.Now()
+}
+
+// StartSpinning tells the card to start spinning
+func (cv *cardView) StartSpinning() {
+	cv.directionX = rand.Intn(9) - 4
+	cv.directionY = rand.Intn(9) - 3 // favor falling downwards
+	cv.spin = rand.Float64() - 0.5
+	// delay start of spinning to allow cards to be seen to go/finish their trip to foundations
+	// https://stackoverflow.com/questions/67726230/creating-a-time-duration-from-float64-seconds
+	d := time.Duration(TheGame.Settings.AniSpeed * float64(time.Second))
+	d *= 2.0 // pause for admiration
+	cv.spinStartAfter = time.Now().Add(d)
+}
+
+// StopSpinning tells the card to stop spinning and return to it's upright state
+func (cv *cardView) StopSpinning() {
+	cv.directionX, cv.directionY = 0, 0
+	cv.angle, cv.spin = 0, 0
+	// card may have spun off-screen slightly, and be -ve, which confuses Smoothstep
+	if owner := cv.card.Owner(); owner != nil {
+		cv.pos = TheGame.pileView(owner).pos
+	}
+}
+
+func (cv *cardView) Static() bool {
+	return !cv.lerping && !cv.beingDragged && cv.flipDirection == 0
+}
+
+// Spinning returns true if this card is spinning
+func (cv *cardView) Spinning() bool {
+	return cv.spin != 0.0
+}
+
+// Lerping returns true if this card is lerping
+func (cv *cardView) Lerping() bool {
+	return cv.lerping
+}
+
+// Dragging returns true if this card is being dragged
+func (cv *cardView) Dragging() bool {
+	return cv.beingDragged
+}
+
+// Flipping returns true if this card is flipping
+func (cv *cardView) Flipping() bool {
+	return cv.flipDirection != 0 // will be -1 or +1 if flipping
+}
+
+// Update the card state (transitions)
+func (cv *cardView) Update() error {
+
+	if cv.Spinning() {
+		if time.Now().After(cv.spinStartAfter) {
+			cv.lerping = false
+			cv.pos.X += cv.directionX
+			cv.pos.Y += cv.directionY
+			// pearl from the mudbank:
+			// cannot flip card here (or anytime while spinning)
+			// because Baize.Complete() will fail (and record a lost game)
+			// because UnsortedPairs will "fail" because some cards will be face down
+			// so do not call c.Flip() here
+			cv.angle += cv.spin
+			if cv.angle > 360 {
+				cv.angle -= 360
+			} else if cv.angle < 0 {
+				cv.angle += 360
+			}
+		}
+	}
+
+	if cv.Lerping() {
+		if !cv.pos.Eq(cv.dst) {
+			secs := time.Since(cv.lerpStartTime).Seconds()
+			// secs will start at nearly zero, and rise to about the value of AniSpeed,
+			// because AniSpeed is the number of seconds the card will take to transition.
+			// with AniSpeed at 0.75, this happens (for example) 45 times (we are at @ 60Hz)
+			var t float64 = secs / cv.aniSpeed
+			// with small values of AniSpeed, t can go above 1.0
+			// which is bad: cards appear to fly away, never to be seen again
+			// Smoothstep will correct this
+			cv.pos.X = int(util.Smoothstep(float64(cv.src.X), float64(cv.dst.X), t))
+			cv.pos.Y = int(util.Smoothstep(float64(cv.src.Y), float64(cv.dst.Y), t))
+		} else {
+			cv.lerping = false
+			if cv.hinting {
+				cv.LerpTo(cv.hintHome)
+			}
+		}
+	}
+
+	if cv.Flipping() {
+		// we need to flip faster than we lerp, because flipping happens in two stages
+		t := time.Since(cv.flipStartTime).Seconds() / (TheGame.Settings.AniSpeed / 2.0)
+		if cv.flipDirection < 0 {
+			cv.flipWidth = util.Lerp(1.0, 0.0, t)
+			if cv.flipWidth <= 0.0 {
+				// reverse direction, make card wider
+				cv.flipDirection = 1
+				cv.flipStartTime = time.Now()
+			}
+		} else if cv.flipDirection > 0 {
+			cv.flipWidth = util.Lerp(0.0, 1.0, t)
+			if cv.flipWidth >= 1.0 {
+				cv.flipDirection = 0
+				cv.flipWidth = 1.0
+			}
+		}
+	}
+
+	return nil
+}
+
+// faceImage returns the image of the face of a card
+func faceImage(c *sol.Card) *ebiten.Image {
+	if c.Joker() {
+		return JokerImage
+	}
+	return TheCardFaceImageLibrary[(c.Suit()*15)+(c.Ordinal()-1)]
+}
+
+// Draw renders the card into the screen
+func (cv *cardView) Draw(screen *ebiten.Image) {
+
+	var c *sol.Card = cv.card // for readability
+
+	if c.Owner().Hidden() {
+		return // eg Freecell stock
+	}
+
+	op := &ebiten.DrawImageOptions{}
+
+	var img *ebiten.Image
+	// card prone has already been set to destination state
+	if cv.flipDirection < 0 {
+		if c.Prone() {
+			// card is getting narrower, and it's going to show face down, but show face up
+			img = faceImage(c)
+		} else {
+			// card is getting narrower, and it's going to show face up, but show face down
+			img = CardBackImage
+		}
+	} else {
+		if c.Prone() {
+			img = CardBackImage
+		} else {
+			img = faceImage(c)
+		}
+	}
+
+	if sol.DebugMode && img == nil {
+		log.Panic("Card.Draw no image for ", c.String(), " prone: ", c.Prone())
+	}
+
+	if cv.Flipping() {
+		op.GeoM.Translate(float64(-CardWidth/2), 0)
+		op.GeoM.Scale(cv.flipWidth, 1.0)
+		op.GeoM.Translate(float64(CardWidth/2), 0)
+	}
+
+	if cv.Spinning() {
+		// do this before the baize position translate
+		op.GeoM.Translate(float64(-CardWidth/2), float64(-CardHeight/2))
+		op.GeoM.Rotate(cv.angle * 3.1415926535 / 180.0)
+		op.GeoM.Translate(float64(CardWidth/2), float64(CardHeight/2))
+
+		// naughty to do this here instead of Update(), but Draw() knows the screen dimensions and Update() doesn't
+		w, h := screen.Size()
+		w -= TheGame.dragOffset.X
+		h -= TheGame.dragOffset.Y
+		switch {
+		case cv.pos.X+CardWidth > w:
+			cv.directionX = -rand.Intn(5)
+			cv.spin = rand.Float64() - 0.5
+		case cv.pos.X < 0:
+			cv.directionX = rand.Intn(5)
+			cv.spin = rand.Float64() - 0.5
+		case cv.pos.Y > h+CardHeight:
+			cv.directionX = rand.Intn(5) // go downwards
+			cv.pos.Y = -CardHeight       // start from off screen at top
+		case cv.pos.Y < -CardHeight:
+			cv.directionY = rand.Intn(5) // go downwards
+		}
+	}
+
+	op.GeoM.Translate(float64(cv.pos.X+TheGame.dragOffset.X), float64(cv.pos.Y+TheGame.dragOffset.Y))
+
+	if !cv.Flipping() {
+		if cv.Lerping() || cv.Dragging() {
+			op.GeoM.Translate(4.0, 4.0)
+			screen.DrawImage(CardShadowImage, op)
+			op.GeoM.Translate(-4.0, -4.0)
+		}
+		// no longer "press" the card when dragging it
+		// because this made tapping look a little messy
+	}
+
+	if TheGame.Settings.ShowMovableCards {
+		if c.Owner().IsStock() {
+			// card will be prone because Stock
+			// nb this will color all the stock cards, not just the top card
+			img = MovableCardBackImage
+		} else {
+			if !cv.Flipping() && c.TapWeight() != 0 {
+				// c.destinations has been sorted so weightiest is first
+				switch c.TapWeight() {
+				case 1: // Cell
+					op.ColorM.Scale(1.0, 1.0, 0.9, 1)
+				case 2: // Normal
+					op.ColorM.Scale(1.0, 1.0, 0.8, 1)
+				case 3: // Suit match
+					op.ColorM.Scale(1.0, 1.0, 0.7, 1)
+				case 4: // Discard or Foundation
+					op.ColorM.Scale(1.0, 1.0, 0.6, 1)
+				}
+			}
+		}
+	}
+
+	if img != nil {
+		screen.DrawImage(img, op)
+	}
+}
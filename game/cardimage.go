@@ -1,4 +1,4 @@
-package sol
+package game
 
 import (
 	"image/color"
@@ -103,7 +103,7 @@ var Pips [13][]PipInfo = [13][]PipInfo{
 func cardColor(cid cardid.CardID) color.RGBA {
 	suit := cid.Suit()
 	if TheGame.Settings.ColorfulCards {
-		switch TheGame.Baize.script.CardColors() {
+		switch TheGame.Baize.Script().CardColors() {
 		case 4:
 			switch suit {
 			case cardid.NOSUIT:
@@ -165,14 +165,22 @@ func createFaceImage(ID cardid.CardID) *ebiten.Image {
 	var cardOrdinal = ID.Ordinal()
 	var suitRune rune = ID.SuitRune()
 	var cardColor color.RGBA = cardColor(ID)
-	// if ID.Joker() {
-	// 	// if a joker is pretending to be a certain card, then show it's pretend ordinal and suit, but faded
-	// 	cardColor.A = 64
-	// }
+	if ID.Joker() {
+		// a joker has no ordinal or suit, so gets stars in the corners and a big one in the middle
+		const star = "\u2605"
+		dc.SetColor(cardColor)
+		dc.SetFontFace(schriftbank.CardSymbolRegular)
+		dc.DrawStringAnchored(star, w*COTLX, h*COTLY, 0.5, 0.4)
+		dc.DrawStringAnchored(star, w*COBRX, h*COBRY, 0.5, 0.4)
+		dc.SetFontFace(schriftbank.CardSymbolHuge)
+		dc.DrawStringAnchored(star, w*0.5, h*0.5, 0.5, 0.5)
+		dc.Stroke()
+		return ebiten.NewImageFromImage(dc.Image())
+	}
 
 	// draw the card ordinals in top left and bottom right corners
 	dc.SetColor(cardColor)
-	if cardOrdinal == 10 {
+	if len(util.OrdinalToShortString(cardOrdinal)) > 1 {
 		dc.SetFontFace(schriftbank.CardOrdinalSmall)
 	} else {
 		dc.SetFontFace(schriftbank.CardOrdinal)
@@ -330,11 +338,12 @@ func CreateCardImages() {
 	}
 	schriftbank.MakeCardFonts(CardWidth)
 	for _, suit := range []int{cardid.NOSUIT, cardid.CLUB, cardid.DIAMOND, cardid.HEART, cardid.SPADE} {
-		for ord := 1; ord < 14; ord++ {
+		for ord := 1; ord < 16; ord++ {
 			ID := cardid.NewCardID(0, suit, ord)
-			TheCardFaceImageLibrary[(suit*13)+(ord-1)] = createFaceImage(ID)
+			TheCardFaceImageLibrary[(suit*15)+(ord-1)] = createFaceImage(ID)
 		}
 	}
+	JokerImage = createFaceImage(cardid.NewJokerID(0, 0))
 	CardBackImage = CreateCardBackImage(TheGame.Settings.CardBackColor)
 	MovableCardBackImage = CreateCardBackImage(TheGame.Settings.MovableCardBackColor)
 	CardShadowImage = CreateCardShadowImage()
@@ -0,0 +1,129 @@
+package game
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"oddstream.games/gosol/sol"
+	"oddstream.games/gosol/ui"
+)
+
+var CommandTable = map[ebiten.Key]func(){
+	ebiten.KeyN: func() { TheGame.NewDeal() },
+	ebiten.KeyR: func() { TheGame.Baize.RestartDeal() },
+	ebiten.KeyD: func() { ShowDealNumberPicker() },
+	ebiten.KeyE: func() { ShowRatedDealPicker() },
+	ebiten.KeyU: func() { TheGame.Undo() },
+	ebiten.KeyY: func() { TheGame.Redo() },
+	ebiten.KeyB: func() {
+		if ebiten.IsKeyPressed(ebiten.KeyControl) {
+			TheGame.Baize.LoadPosition()
+		} else {
+			TheGame.Baize.SavePosition()
+		}
+	},
+	ebiten.KeyK:      func() { TheGame.ShowBookmarkDrawer() },
+	ebiten.KeyT:      func() { TheGame.ShowBranchDrawer() }, // T for tree of lines played
+	ebiten.KeyL:      func() { TheGame.Baize.LoadPosition() },
+	ebiten.KeyS:      func() { TheGame.Baize.SavePosition() },
+	ebiten.KeyC:      func() { TheGame.Baize.Collect2() },
+	ebiten.KeyH:      func() { TheGame.ShowHint() },
+	ebiten.KeyW:      func() { TheGame.StartAnalysis() }, // W for where did it go wrong
+	ebiten.KeyP:      func() { TheGame.StartDemo() },     // P for play by itself
+	ebiten.KeySpace:  func() { TheGame.PauseDemo() },
+	ebiten.KeyPeriod: func() { TheGame.StepDemo() },
+	ebiten.KeyEqual:  func() { TheGame.SpeedUpDemo(true) },
+	ebiten.KeyMinus:  func() { TheGame.SpeedUpDemo(false) },
+	ebiten.KeyM: func() {
+		TheGame.Settings.AlwaysShowMovableCards = !TheGame.Settings.AlwaysShowMovableCards
+		TheGame.Settings.ShowMovableCards = TheGame.Settings.AlwaysShowMovableCards
+		if TheGame.Settings.AlwaysShowMovableCards {
+			TheGame.UI.ToastInfo("Movable cards always highlighted")
+		}
+	},
+	ebiten.KeyF: func() { TheGame.UI.ShowVariantPickerEx(sol.VariantGroupNames(), "ShowVariantPicker") },
+	ebiten.KeyA: func() { ShowAniSpeedDrawer() },
+	ebiten.KeyX: func() { ExitRequested = true },
+	// ebiten.KeyTab: func() {
+	// 	if DebugMode {
+	// 		for _, p := range TheGame.Baize.piles {
+	// 			p.Refan()
+	// 		}
+	// 	}
+	// },
+	ebiten.KeyF1: func() { TheGame.Wikipedia() },
+	ebiten.KeyF2: func() { ShowStatisticsDrawer() },
+	ebiten.KeyF3: func() { ShowSettingsDrawer() },
+	ebiten.KeyF5: func() { TheGame.StartSpinning() }, // debug
+	ebiten.KeyF6: func() { TheGame.StopSpinning() },  // debug
+	ebiten.KeyF7: func() {
+		TheGame.UI.AddButtonToFAB("restore", ebiten.KeyR)
+		TheGame.UI.AddButtonToFAB("done_all", ebiten.KeyC)
+	}, // debug
+	ebiten.KeyF8:     func() { TheGame.UI.HideFAB() }, // debug
+	ebiten.KeyMenu:   func() { TheGame.UI.ToggleNavDrawer() },
+	ebiten.KeyEscape: func() { TheGame.UI.HideActiveDrawer() },
+	ebiten.KeyEnter: func() {
+		if TheGame.dealNumber != "" {
+			TheGame.DealNumberKey("Deal")
+		}
+	},
+}
+
+func init() {
+	// typing digits builds up a deal number, started with Enter
+	for k := ebiten.Key0; k <= ebiten.Key9; k++ {
+		var digit string = fmt.Sprint(int(k - ebiten.Key0))
+		CommandTable[k] = func() { TheGame.DealNumberKey(digit) }
+	}
+}
+
+func Execute(cmd interface{}) {
+	TheGame.UI.HideActiveDrawer()
+	TheGame.UI.HideFAB()
+	switch v := cmd.(type) {
+	case ebiten.Key:
+		if fn, ok := CommandTable[v]; ok {
+			fn()
+		}
+	case ui.Command:
+		// a widget has sent a command
+		switch v.Command {
+		case "ShowVariantGroupPicker":
+			TheGame.UI.ShowVariantPickerEx(sol.VariantGroupNames(), "ShowVariantPicker")
+		case "ShowVariantPicker":
+			TheGame.UI.ShowVariantPickerEx(sol.VariantNames(v.Data, TheGame.Statistics), "ChangeVariant")
+		case "ChangeVariant":
+			if _, ok := sol.LookupVariant(v.Data); !ok {
+				TheGame.UI.ToastError(fmt.Sprintf("Don't know how to play '%s'", v.Data))
+			} else if v.Data == TheGame.Baize.Variant() {
+				TheGame.UI.ToastError(fmt.Sprintf("Already playing '%s'", v.Data))
+			} else {
+				TheGame.ChangeVariant(v.Data)
+			}
+		case "DealNumberKey":
+			TheGame.DealNumberKey(v.Data)
+			if v.Data != "Deal" {
+				ShowDealNumberPicker() // keep the keypad open
+			}
+		case "NewRatedDeal":
+			TheGame.NewRatedDeal(strings.ToLower(v.Data))
+		case "AnalysisShowBetter", "AnalysisRewind", "AnalysisShowLosing":
+			TheGame.analysisCommand(v.Command)
+		case "GotoBookmark":
+			TheGame.gotoBookmark(v.Data)
+		case "NameBookmark":
+			TheGame.startNamingBookmark()
+		case "GotoBranch":
+			TheGame.gotoBranch(v.Data)
+		case "SaveSettings":
+			TheGame.Settings.Save() // save now especially if running in a browser
+		default:
+			log.Panic("unknown command", v.Command, v.Data)
+		}
+	default:
+		log.Fatal("Baize.Execute unknown command type", cmd)
+	}
+}
@@ -1,6 +1,6 @@
 // Copyright ©️ 2020-2021 oddstream.games
 
-package sol
+package game
 
 import (
 	"image/color"
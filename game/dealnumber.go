@@ -0,0 +1,47 @@
+package game
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// dealNumberKeys are the keys on the deal number keypad
+var dealNumberKeys = []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "0", "Delete", "Deal"}
+
+// ShowDealNumberPicker shows a keypad for typing in a deal number
+func ShowDealNumberPicker() {
+	TheGame.UI.ShowVariantPickerEx(dealNumberKeys, "DealNumberKey")
+}
+
+// DealNumberKey handles a key typed on the deal number keypad (or the keyboard)
+func (g *Game) DealNumberKey(key string) {
+	switch key {
+	case "Delete":
+		if len(g.dealNumber) > 0 {
+			g.dealNumber = g.dealNumber[:len(g.dealNumber)-1]
+		}
+	case "Deal":
+		g.dealNumberTyped()
+		return
+	default:
+		if len(g.dealNumber) < 20 {
+			g.dealNumber += key
+		}
+	}
+	g.UI.ToastInfo(fmt.Sprintf("Deal #%s", g.dealNumber))
+}
+
+// dealNumberTyped starts the deal whose number has been typed in
+func (g *Game) dealNumberTyped() {
+	if g.dealNumber == "" {
+		g.UI.ToastError("Type a deal number first")
+		return
+	}
+	seed, err := strconv.ParseUint(g.dealNumber, 10, 64)
+	g.dealNumber = ""
+	if err != nil || seed == 0 {
+		g.UI.ToastError("That is not a deal number")
+		return
+	}
+	g.NewDealFromSeed(seed)
+}
@@ -0,0 +1,133 @@
+package game
+
+import (
+	"context"
+	"fmt"
+
+	"oddstream.games/gosol/sol"
+)
+
+// ratedDealKeys are the choices offered by the rated deal picker
+var ratedDealKeys = []string{"Easy", "Medium", "Hard"}
+
+// ShowRatedDealPicker asks the player how hard they want the next deal to be
+func ShowRatedDealPicker() {
+	TheGame.UI.ShowVariantPickerEx(ratedDealKeys, "NewRatedDeal")
+}
+
+// dealSearch runs the solver on a goroutine of its own, while the cards are being dealt,
+// either to rate the deal on the baize, or to find a better one
+type dealSearch struct {
+	variant string
+	seed    uint64 // the deal on the baize when the search started
+	first   uint64 // the first deal to try
+	find    bool   // true to look for a deal to replace the one on the baize
+	rating  string // the rating of deal to look for, or "" for any winnable deal
+	cancel  context.CancelFunc
+	result  chan dealSearchResult
+}
+
+type dealSearchResult struct {
+	seed       uint64
+	difficulty *sol.Difficulty
+	ok         bool
+}
+
+// startDealSearch starts rating the deal on the baize or, if find is true,
+// looking for a winnable deal with the given rating
+func (g *Game) startDealSearch(find bool, rating string) {
+	g.cancelDealSearch()
+	ctx, cancel := context.WithCancel(context.Background())
+	ds := &dealSearch{
+		variant: g.Baize.Variant(),
+		seed:    g.Baize.Seed(),
+		first:   g.Baize.Seed(),
+		find:    find,
+		rating:  rating,
+		cancel:  cancel,
+		result:  make(chan dealSearchResult, 1),
+	}
+	if rating != "" {
+		// the deal on the baize might be being played, so don't offer it again
+		ds.first = sol.NewSeed()
+	}
+	// the goroutine gets its own copy of the settings, so the player can change them meanwhile
+	var settings sol.Settings = *g.Settings
+	go func() {
+		var r dealSearchResult
+		if ds.find {
+			r.seed, r.difficulty, r.ok = sol.FindDeal(ctx, ds.variant, &settings, ds.first, ds.rating)
+		} else {
+			var outcome sol.SolverOutcome
+			r.seed = ds.seed
+			r.difficulty, outcome = sol.RateDeal(ctx, ds.variant, &settings, ds.seed)
+			r.ok = outcome == sol.SOLVED
+		}
+		ds.result <- r
+	}()
+	g.search = ds
+}
+
+func (g *Game) cancelDealSearch() {
+	if g.search != nil {
+		g.search.cancel()
+		g.search = nil
+	}
+}
+
+// updateDealSearch is called every tick, to see if the search has finished
+func (g *Game) updateDealSearch() {
+	if g.search == nil {
+		return
+	}
+	var r dealSearchResult
+	select {
+	case r = <-g.search.result:
+	default:
+		return
+	}
+	ds := g.search
+	g.search = nil
+	if g.Baize.Variant() != ds.variant || g.Baize.Seed() != ds.seed {
+		return // the player has moved on
+	}
+	if !ds.find {
+		if r.ok {
+			g.Baize.SetDifficulty(r.difficulty)
+		}
+		return
+	}
+	if !r.ok {
+		if ds.rating == "" {
+			g.UI.ToastError("Could not find a winnable deal")
+		} else {
+			g.UI.ToastError(fmt.Sprintf("Could not find %s %s deal", article(ds.rating), ds.rating))
+		}
+		return
+	}
+	if r.seed != ds.seed {
+		if g.Baize.MovesMade() > 0 {
+			// the player started on the deal while the search ran; don't throw their moves away
+			g.UI.ToastInfo(fmt.Sprintf("Found deal #%d, but you've already started this one", r.seed))
+			return
+		}
+		if ds.rating == "" {
+			// the player never had a fair deal, so this isn't a lost game
+			g.StopSpinning()
+			g.demoPlayed = false
+			g.Baize.NewDealFromSeed(r.seed)
+		} else {
+			g.NewDealFromSeed(r.seed)
+		}
+	}
+	g.Baize.SetWinnable(true)
+	g.Baize.SetDifficulty(r.difficulty)
+	g.UI.Toast("Glass", fmt.Sprintf("Deal #%d can be won, and is %s", r.seed, r.difficulty.Rating()))
+}
+
+func article(word string) string {
+	if word != "" && (word[0] == 'a' || word[0] == 'e' || word[0] == 'i' || word[0] == 'o' || word[0] == 'u') {
+		return "an"
+	}
+	return "a"
+}
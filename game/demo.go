@@ -0,0 +1,202 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"oddstream.games/gosol/sol"
+)
+
+const (
+	minDemoDelay = 125 * time.Millisecond
+	maxDemoDelay = 4 * time.Second
+)
+
+// demo plays the deal on the baize by itself, one move each time the cards come to rest,
+// through the same Baize.ApplyMove path as a player's drags and taps.
+// It follows the solver's winning moves if the solver can find some,
+// otherwise (or if the plan goes astray) it takes the best hint.
+// When a game is over, it deals again and carries on, so it can be left running as an attract screen.
+type demo struct {
+	paused bool
+	step   bool          // make one move, then pause again
+	delay  time.Duration // pause between moves, once the cards have stopped
+	next   time.Time     // when the next move can be made
+	seed   uint64        // the deal being played
+	plan   []sol.Move    // moves the solver found, still to be made
+	expect uint64        // the baize Hash the next planned move starts from
+	last   sol.Move      // the previous move, so the fallback doesn't just undo it
+	seen   map[uint64]bool
+	cancel context.CancelFunc
+	solved chan sol.SolverResult
+}
+
+// StartDemo starts the baize playing itself, or stops it if it already is
+func (g *Game) StartDemo() {
+	if g.demo != nil {
+		g.StopDemo("Demo stopped")
+		return
+	}
+	g.demo = &demo{delay: g.demoDelay()}
+	g.demo.solve(g.Baize)
+	g.UI.ToastInfo("Demo started; touch a card to take over")
+}
+
+// StopDemo stops the baize playing itself
+func (g *Game) StopDemo(message string) {
+	if g.demo == nil {
+		return
+	}
+	g.demo.cancel()
+	g.demo = nil
+	if message != "" {
+		g.UI.ToastInfo(message)
+	}
+}
+
+// PauseDemo pauses or resumes the demo
+func (g *Game) PauseDemo() {
+	if g.demo == nil {
+		return
+	}
+	g.demo.paused = !g.demo.paused
+	if g.demo.paused {
+		g.UI.ToastInfo("Demo paused")
+	} else {
+		g.UI.ToastInfo("Demo resumed")
+	}
+}
+
+// StepDemo makes the demo's next move, and then pauses it
+func (g *Game) StepDemo() {
+	if g.demo == nil {
+		return
+	}
+	g.demo.paused = true
+	g.demo.step = true
+}
+
+// SpeedUpDemo halves (or, if faster is false, doubles) the pause between the demo's moves
+func (g *Game) SpeedUpDemo(faster bool) {
+	if g.demo == nil {
+		return
+	}
+	if faster {
+		g.demo.delay /= 2
+	} else {
+		g.demo.delay *= 2
+	}
+	if g.demo.delay < minDemoDelay {
+		g.demo.delay = minDemoDelay
+	} else if g.demo.delay > maxDemoDelay {
+		g.demo.delay = maxDemoDelay
+	}
+	g.UI.ToastInfo(fmt.Sprintf("Demo pauses %v between moves", g.demo.delay))
+}
+
+// demoDelay is the pause between moves a demo starts with; slower cards get a longer pause
+func (g *Game) demoDelay() time.Duration {
+	return time.Duration(g.Settings.AniSpeed * float64(time.Second))
+}
+
+// solve starts the solver looking for a plan for the deal on the baize
+func (d *demo) solve(b *sol.Baize) {
+	if d.cancel != nil {
+		d.cancel()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+	d.seed = b.Seed()
+	d.plan = nil
+	d.expect = b.Hash()
+	d.last = sol.Move{Src: -1, Dst: -1}
+	d.seen = make(map[uint64]bool)
+	d.solved = make(chan sol.SolverResult, 1)
+	if s := b.NewSolver(); s != nil {
+		go func(solved chan sol.SolverResult) { solved <- s.Solve(ctx) }(d.solved)
+	} else {
+		close(d.solved)
+	}
+}
+
+// updateDemo is called every tick, and makes the next move once the cards have stopped moving
+func (g *Game) updateDemo() {
+	d := g.demo
+	if d == nil {
+		return
+	}
+	if d.seed != g.Baize.Seed() {
+		d.solve(g.Baize) // there's been a new deal
+	}
+	if d.solved != nil {
+		select {
+		case r, ok := <-d.solved:
+			d.solved = nil
+			if ok && r.Outcome == sol.SOLVED {
+				d.plan = r.Moves
+			}
+		default:
+			return // give the solver a chance to find a plan before making any moves
+		}
+	}
+	if d.paused && !d.step {
+		return
+	}
+	if g.dirtyFlags != 0 || g.stroke != nil {
+		return
+	}
+	for _, cv := range g.cardViews {
+		if !cv.Static() {
+			d.next = time.Now().Add(d.delay)
+			return
+		}
+	}
+	if time.Now().Before(d.next) {
+		return
+	}
+	d.step = false
+
+	if g.Baize.Complete() {
+		g.NewDeal()
+		return
+	}
+	hash := g.Baize.Hash()
+	m, ok := d.nextMove(g.Baize)
+	if !ok || d.seen[hash] {
+		// stuck, or going round in circles
+		g.NewDeal()
+		return
+	}
+	d.seen[hash] = true
+	// the solver doesn't auto collect, so nor does the demo, otherwise it would stray from the plan
+	saved := g.Settings.AutoCollect
+	g.Settings.AutoCollect = false
+	g.demoPlayed = true
+	ok = g.Baize.ApplyMove(m)
+	g.Settings.AutoCollect = saved
+	if !ok {
+		g.StopDemo("The demo made a mistake")
+		return
+	}
+	d.last = m
+	d.expect = g.Baize.Hash()
+}
+
+// nextMove follows the plan while the baize is where the plan expects it to be,
+// otherwise takes the best hint
+func (d *demo) nextMove(b *sol.Baize) (sol.Move, bool) {
+	if len(d.plan) > 0 && d.expect == b.Hash() {
+		m := d.plan[0]
+		d.plan = d.plan[1:]
+		return m, true
+	}
+	d.plan = nil
+	for _, m := range b.Hints() {
+		if m.Dst >= 0 && m.Src == d.last.Dst && m.Dst == d.last.Src && m.N == d.last.N {
+			continue // don't undo the last move
+		}
+		return m, true
+	}
+	return sol.Move{}, false
+}
@@ -1,39 +1,64 @@
-// Package sol provides a polymorphic solitaire engine
-package sol
+// Package game is the Ebiten front end for the sol solitaire engine
+package game
 
 import (
 	"errors"
 	"fmt"
+	"image"
 	"log"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"oddstream.games/gosol/input"
+	"oddstream.games/gosol/sol"
 	"oddstream.games/gosol/sound"
 	"oddstream.games/gosol/ui"
 )
 
+const (
+	dirtyWindowSize = 1 << iota
+	dirtyPilePositions
+	dirtyCardSizes
+	dirtyCardImages
+	dirtyPileBackgrounds
+	dirtyCardPositions
+)
+
 // Game represents a game state
 type Game struct {
-	UI         *ui.UI
-	Baize      *Baize
-	Statistics *Statistics
-	Settings   *Settings
+	UI           *ui.UI
+	Baize        *sol.Baize
+	Statistics   *sol.Statistics
+	Settings     *sol.Settings
+	dirtyFlags   uint32 // what needs doing when we Update
+	stroke       *input.Stroke
+	dragStart    image.Point
+	dragOffset   image.Point
+	WindowWidth  int // the most recent window width given to Layout
+	WindowHeight int // the most recent window height given to Layout
+	cardViews    map[*sol.Card]*cardView
+	pileViews    map[*sol.Pile]*pileView
+	dealNumber   string      // digits of a deal number being typed in
+	naming       bool        // true while the player is typing the name of a bookmark
+	bookmarkName string      // the name of a bookmark being typed in
+	search       *dealSearch // solver running in the background, if any
+	hints        *hintList   // moves suggested for the position on the baize
+	pendingHint  *sol.Move   // move to show once the cards have stopped moving
+	analysis     *analysisRun
+	demo         *demo     // the baize playing itself, if it is
+	demoPlayed   bool      // true if the demo has made moves in this game, so it isn't recorded in the statistics
+	scriptsSeen  time.Time // when the variant scripts were last checked for changes
 }
 
 var (
-	// GosolVersionMajor is the integer version number
-	GosolVersionMajor int = 5
-	// CsolVersionMinor is the integer version number
-	GosolVersionMinor int = 15
-	// CSolVersionDate is the ISO 8601 date of bumping the version number
-	GosolVersionDate string = "2023-02-26"
-	// DebugMode is a boolean set by command line flag -debug
-	DebugMode bool = false
 	// NoGameLoad is a boolean set by command line flag -noload
 	NoGameLoad bool = false
 	// NoGameSave is a boolean set by command line flag -nosave
 	NoGameSave bool = false
 	// NoScrunch stops cards being scrunched
 	NoScrunch bool = false
+	// DealNumber is set by command line flag -deal, to start with a particular deal
+	DealNumber uint64 = 0
 	// CardWidth of cards, start with a silly value to force a rescale/refan
 	CardWidth int = 9
 	// CardHeight of cards, start with a silly value to force a rescale/refan
@@ -50,11 +75,13 @@ var (
 	// TopMargin the gap between top pile and top of baize
 	TopMargin int = ui.ToolbarHeight + CardHeight/3
 	// CardFaceImageLibrary
-	// thirteen suitless cards,
-	// one entry for each face card (4 suits * 13 cards),
-	// suits are 1-indexed (eg club == 1) so image to be used for a card is (suit * 13) + (ord - 1).
+	// fifteen suitless cards,
+	// one entry for each face card (4 suits * 15 cards, as ordinals go up to 15 in extended packs),
+	// suits are 1-indexed (eg club == 1) so image to be used for a card is (suit * 15) + (ord - 1).
 	// can use (ord - 1) as an index to get suitless card
-	TheCardFaceImageLibrary [13 * 5]*ebiten.Image
+	TheCardFaceImageLibrary [15 * 5]*ebiten.Image
+	// JokerImage is the face of every joker
+	JokerImage *ebiten.Image
 	// CardBackImage applies to all cards so is kept globally as an optimization
 	CardBackImage *ebiten.Image
 	// MovableCardBackImage applies to all cards so is kept globally as an optimization
@@ -69,34 +96,38 @@ var TheGame *Game // pointer to object that implements ebiten.Game interface
 
 // NewGame generates a new Game object, which implements ebiten.Game interface
 func NewGame() {
-	TheGame = &Game{Settings: NewSettings()}
+	// let WindowWidth, WindowHeight be zero, so that the first Layout will
+	// trigger card scaling and pile placement
+	TheGame = &Game{Settings: sol.NewSettings(), dirtyFlags: 0xFFFF}
 	if TheGame.Settings.Mute {
 		sound.SetVolume(0.0)
 	} else {
 		sound.SetVolume(TheGame.Settings.Volume)
 	}
-	TheGame.Statistics = NewStatistics()
+	TheGame.Statistics = sol.NewStatistics()
 	TheGame.UI = ui.New(Execute)
-	if TheGame.Baize = NewBaize(TheGame.Settings.Variant); TheGame.Baize == nil {
+	for _, err := range sol.LoadVariants() {
+		TheGame.UI.ToastError(err.Error())
+	}
+	if !TheGame.startVariant(TheGame.Settings.Variant) {
 		log.Panic("cannot create Baize")
 	}
-	TheGame.Baize.StartFreshGame()
-	if !NoGameLoad {
-		TheGame.Baize.Load()
+	if DealNumber != 0 {
+		TheGame.NewDealFromSeed(DealNumber)
 	}
 
-	if TheGame.Settings.LastVersionMajor != GosolVersionMajor || TheGame.Settings.LastVersionMinor != GosolVersionMinor {
+	if TheGame.Settings.LastVersionMajor != sol.GosolVersionMajor || TheGame.Settings.LastVersionMinor != sol.GosolVersionMinor {
 		TheGame.UI.Toast("Glass", fmt.Sprintf("Upgraded from %d.%d to %d.%d",
 			TheGame.Settings.LastVersionMajor,
 			TheGame.Settings.LastVersionMinor,
-			GosolVersionMajor,
-			GosolVersionMinor))
+			sol.GosolVersionMajor,
+			sol.GosolVersionMinor))
 	}
 }
 
 // Layout implements ebiten.Game's Layout.
 func (g *Game) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
-	g.Baize.Layout(outsideWidth, outsideHeight)
+	g.layoutBaize(outsideWidth, outsideHeight)
 	return outsideWidth, outsideHeight
 }
 
@@ -108,7 +139,12 @@ func (g *Game) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeigh
 // the TPS with SetMaxTPS, the fixed timestep will be 1000/60 = 16.666 milliseconds.
 // https://ebitencookbook.vercel.app/blog
 func (g *Game) Update() error {
-	g.Baize.Update()
+	g.updateDealSearch()
+	g.updateAnalysis()
+	g.updatePendingHint()
+	g.updateDemo()
+	g.updateScripts()
+	g.updateBaize()
 	g.UI.Update()
 	if ExitRequested {
 		if !NoGameSave {
@@ -124,6 +160,6 @@ func (g *Game) Update() error {
 // Draw will be called based on the refresh rate of the screen (FPS).
 // https://ebitencookbook.vercel.app/blog
 func (g *Game) Draw(screen *ebiten.Image) {
-	g.Baize.Draw(screen)
+	g.drawBaize(screen)
 	g.UI.Draw(screen)
 }
@@ -0,0 +1,85 @@
+package game
+
+import (
+	"fmt"
+	"image"
+	"strings"
+
+	"oddstream.games/gosol/sol"
+)
+
+// hintList remembers the moves suggested for a position,
+// so that asking again for a hint suggests the next one
+type hintList struct {
+	position *sol.SavableBaize // the top of the undo stack when the hints were found
+	moves    []sol.Move
+	next     int
+}
+
+// ShowHint shows the player a good move, by sending the card(s) towards where they could go.
+// Asking again before making a move shows the next best move, and so on, round and round.
+func (g *Game) ShowHint() {
+	if g.Baize.Complete() {
+		g.UI.ToastError("The game is over")
+		return
+	}
+	if g.hints == nil || g.hints.position != g.Baize.UndoPeek() {
+		g.hints = &hintList{position: g.Baize.UndoPeek(), moves: g.Baize.Hints()}
+	}
+	if len(g.hints.moves) == 0 {
+		g.UI.ToastError("There are no moves to make")
+		return
+	}
+	i := g.hints.next
+	g.hints.next = (g.hints.next + 1) % len(g.hints.moves)
+	g.Baize.UseHint()
+	g.showMove(g.hints.moves[i])
+	if len(g.hints.moves) > 1 {
+		g.UI.ToastInfo(fmt.Sprintf("Hint %d of %d", i+1, len(g.hints.moves)))
+	}
+}
+
+// updatePendingHint shows a move that was waiting for the cards to settle, eg after rewinding
+func (g *Game) updatePendingHint() {
+	if g.pendingHint == nil || g.dirtyFlags != 0 {
+		return
+	}
+	for _, cv := range g.cardViews {
+		if !cv.Static() {
+			return
+		}
+	}
+	g.showMove(*g.pendingHint)
+	g.pendingHint = nil
+}
+
+// showMove animates the card(s) of a move towards their destination and back
+func (g *Game) showMove(m sol.Move) {
+	piles := g.Baize.Piles()
+	src := piles[m.Src]
+	if m.N == 0 || src.Len() < m.N {
+		g.UI.ToastInfo(fmt.Sprintf("Try tapping the %s", strings.ToLower(src.Category())))
+		return
+	}
+	tail := src.Cards()[src.Len()-m.N:]
+	for _, c := range tail {
+		if !g.cardView(c).Static() {
+			return // don't interrupt cards that are already on the move
+		}
+	}
+	head := g.cardView(tail[0])
+	var to image.Point
+	if m.Dst < 0 {
+		// a tap, so just lift the card
+		to = head.BaizePos().Sub(image.Point{0, CardHeight / 4})
+	} else if dst := piles[m.Dst]; dst.Empty() {
+		to = g.pileView(dst).BaizePos()
+	} else {
+		to = g.pileView(dst).posAfter(g.cardView(dst.Peek()).BaizePos(), dst.Peek())
+	}
+	delta := to.Sub(head.BaizePos())
+	for _, c := range tail {
+		cv := g.cardView(c)
+		cv.Hint(cv.BaizePos().Add(delta))
+	}
+}
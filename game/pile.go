@@ -0,0 +1,261 @@
+package game
+
+//lint:file-ignore ST1006 Receiver name will be anything I like, thank you
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/fogleman/gg"
+	"github.com/hajimehoshi/ebiten/v2"
+	"oddstream.games/gosol/schriftbank"
+	"oddstream.games/gosol/sol"
+)
+
+const (
+	CARD_FACE_FAN_FACTOR_V = 3.7
+	CARD_FACE_FAN_FACTOR_H = 4
+	CARD_BACK_FAN_FACTOR   = 8
+)
+
+const (
+	// https://en.wikipedia.org/wiki/Miscellaneous_Symbols
+	RECYCLE_RUNE   = rune(0x267B)
+	NORECYCLE_RUNE = rune(0x2613)
+)
+
+var DefaultFanFactor [7]float64 = [7]float64{
+	1.0,                    // FAN_NONE
+	CARD_FACE_FAN_FACTOR_V, // FAN_DOWN
+	CARD_FACE_FAN_FACTOR_H, // FAN_LEFT,
+	CARD_FACE_FAN_FACTOR_H, // FAN_RIGHT,
+	CARD_FACE_FAN_FACTOR_V, // FAN_DOWN3,
+	CARD_FACE_FAN_FACTOR_H, // FAN_LEFT3,
+	CARD_FACE_FAN_FACTOR_H, // FAN_RIGHT3,
+}
+
+// pileView is how a sol.Pile looks on the screen
+type pileView struct {
+	pile      *sol.Pile
+	pos       image.Point // actual position on baize
+	pos1      image.Point // waste pos #1
+	pos2      image.Point // waste pos #1
+	fanFactor float64
+	img       *ebiten.Image
+}
+
+// pileView finds (or makes) the view of a pile
+func (g *Game) pileView(p *sol.Pile) *pileView {
+	if g.pileViews == nil {
+		g.pileViews = make(map[*sol.Pile]*pileView)
+	}
+	pv, ok := g.pileViews[p]
+	if !ok {
+		pv = &pileView{pile: p, fanFactor: DefaultFanFactor[p.FanType()]}
+		g.pileViews[p] = pv
+	}
+	return pv
+}
+
+// SetBaizePos sets the position of this Pile in Baize coords,
+// and also sets the auxillary waste pile fanned positions
+func (self *pileView) SetBaizePos(pos image.Point) {
+	self.pos = pos
+	switch self.pile.FanType() {
+	case sol.FAN_DOWN3:
+		self.pos1.X = self.pos.X
+		self.pos1.Y = self.pos.Y + int(float64(CardHeight)/CARD_FACE_FAN_FACTOR_V)
+		self.pos2.X = self.pos.X
+		self.pos2.Y = self.pos1.Y + int(float64(CardHeight)/CARD_FACE_FAN_FACTOR_V)
+	case sol.FAN_LEFT3:
+		self.pos1.X = self.pos.X - int(float64(CardWidth)/CARD_FACE_FAN_FACTOR_H)
+		self.pos1.Y = self.pos.Y
+		self.pos2.X = self.pos1.X - int(float64(CardWidth)/CARD_FACE_FAN_FACTOR_H)
+		self.pos2.Y = self.pos.Y
+	case sol.FAN_RIGHT3:
+		self.pos1.X = self.pos.X + int(float64(CardWidth)/CARD_FACE_FAN_FACTOR_H)
+		self.pos1.Y = self.pos.Y
+		self.pos2.X = self.pos1.X + int(float64(CardWidth)/CARD_FACE_FAN_FACTOR_H)
+		self.pos2.Y = self.pos.Y
+	}
+}
+
+func (self *pileView) BaizePos() image.Point {
+	return self.pos
+}
+
+func (self *pileView) ScreenPos() image.Point {
+	return self.pos.Add(TheGame.dragOffset)
+}
+
+func (self *pileView) BaizeRect() image.Rectangle {
+	var r image.Rectangle
+	r.Min = self.pos
+	r.Max = r.Min.Add(image.Point{CardWidth, CardHeight})
+	return r
+}
+
+func (self *pileView) ScreenRect() image.Rectangle {
+	var r image.Rectangle = self.BaizeRect()
+	r.Min = r.Min.Add(TheGame.dragOffset)
+	r.Max = r.Max.Add(TheGame.dragOffset)
+	return r
+}
+
+func (self *pileView) FannedBaizeRect() image.Rectangle {
+	var r image.Rectangle = self.BaizeRect()
+	if self.pile.Len() > 1 {
+		var cPos = TheGame.cardView(self.pile.Peek()).BaizePos()
+		switch self.pile.FanType() {
+		case sol.FAN_NONE:
+			// do nothing
+		case sol.FAN_RIGHT, sol.FAN_RIGHT3:
+			r.Max.X = cPos.X + CardWidth
+		case sol.FAN_LEFT, sol.FAN_LEFT3:
+			r.Max.X = cPos.X - CardWidth
+		case sol.FAN_DOWN, sol.FAN_DOWN3:
+			r.Max.Y = cPos.Y + CardHeight
+		}
+	}
+	return r
+}
+
+func (self *pileView) FannedScreenRect() image.Rectangle {
+	var r image.Rectangle = self.FannedBaizeRect()
+	r.Min = r.Min.Add(TheGame.dragOffset)
+	r.Max = r.Max.Add(TheGame.dragOffset)
+	return r
+}
+
+// posAfter returns the position of the card following one at pos
+func (self *pileView) posAfter(pos image.Point, c *sol.Card) image.Point {
+	switch self.pile.FanType() {
+	case sol.FAN_DOWN:
+		if c.Prone() {
+			pos.Y += int(float64(CardHeight) / float64(CARD_BACK_FAN_FACTOR))
+		} else {
+			pos.Y += int(float64(CardHeight) / self.fanFactor)
+		}
+	case sol.FAN_LEFT:
+		if c.Prone() {
+			pos.X -= int(float64(CardWidth) / float64(CARD_BACK_FAN_FACTOR))
+		} else {
+			pos.X -= int(float64(CardWidth) / self.fanFactor)
+		}
+	case sol.FAN_RIGHT:
+		if c.Prone() {
+			pos.X += int(float64(CardWidth) / float64(CARD_BACK_FAN_FACTOR))
+		} else {
+			pos.X += int(float64(CardWidth) / self.fanFactor)
+		}
+	}
+	return pos
+}
+
+// Refan sends each card in this pile to where it should be
+func (self *pileView) Refan() {
+	var cards []*sol.Card = self.pile.Cards()
+	var pos = self.pos
+	for i, c := range cards {
+		cv := TheGame.cardView(c)
+		cv.syncProne()
+		switch self.pile.FanType() {
+		case sol.FAN_DOWN3, sol.FAN_LEFT3, sol.FAN_RIGHT3:
+			// top card goes to slot 2 (or slot 1 if there are only two cards),
+			// card below it goes to slot 1, all others to slot 0
+			switch {
+			case i == len(cards)-1 && i >= 2:
+				pos = self.pos2
+			case i == len(cards)-1 && i == 1, i == len(cards)-2 && i >= 1:
+				pos = self.pos1
+			default:
+				pos = self.pos
+			}
+		}
+		if !cv.Dragging() {
+			cv.LerpTo(pos)
+		}
+		pos = self.posAfter(pos, c)
+	}
+}
+
+func (self *pileView) DrawStaticCards(screen *ebiten.Image) {
+	for _, c := range self.pile.Cards() {
+		if cv := TheGame.cardView(c); cv.Static() {
+			cv.Draw(screen)
+		}
+	}
+}
+
+func (self *pileView) DrawAnimatingCards(screen *ebiten.Image) {
+	for _, c := range self.pile.Cards() {
+		if cv := TheGame.cardView(c); cv.Lerping() || cv.Flipping() {
+			cv.Draw(screen)
+		}
+	}
+}
+
+func (self *pileView) DrawDraggingCards(screen *ebiten.Image) {
+	for _, c := range self.pile.Cards() {
+		if cv := TheGame.cardView(c); cv.Dragging() {
+			cv.Draw(screen)
+		}
+	}
+}
+
+func (self *pileView) Draw(screen *ebiten.Image) {
+
+	if self.img == nil || self.pile.Hidden() {
+		return
+	}
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(self.pos.X+TheGame.dragOffset.X), float64(self.pos.Y+TheGame.dragOffset.Y))
+
+	if self.pile.IsStock() && TheGame.Baize.Recycles() > 0 {
+		if pt := image.Pt(ebiten.CursorPosition()); pt.In(self.ScreenRect()) {
+			if ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+				op.GeoM.Translate(2, 2)
+			}
+		}
+	}
+
+	screen.DrawImage(self.img, op)
+}
+
+// Placeholder creates the image drawn underneath the cards in this pile
+func (self *pileView) Placeholder() *ebiten.Image {
+	dc := gg.NewContext(CardWidth, CardHeight)
+	dc.SetColor(color.NRGBA{255, 255, 255, 31})
+	dc.SetLineWidth(2)
+	// draw the RoundedRect entirely INSIDE the context
+	dc.DrawRoundedRectangle(1, 1, float64(CardWidth-2), float64(CardHeight-2), CardCornerRadius)
+	switch self.pile.Category() {
+	case "Stock":
+		// farted around trying to use icons for this
+		// but they were 48x48 and got fuzzy when scaled
+		// and were stubbornly white
+		var label rune
+		if TheGame.Baize.Recycles() == 0 {
+			label = NORECYCLE_RUNE
+		} else {
+			label = RECYCLE_RUNE
+		}
+		dc.SetFontFace(schriftbank.CardSymbolHuge)
+		dc.DrawStringAnchored(string(label), float64(CardWidth)*0.5, float64(CardHeight)*0.4, 0.5, 0.5)
+	case "Foundation", "Tableau":
+		if self.pile.Label() != "" {
+			dc.SetFontFace(schriftbank.CardOrdinalLarge)
+			dc.DrawStringAnchored(self.pile.Label(), float64(CardWidth)*0.5, float64(CardHeight)*0.4, 0.5, 0.5)
+		}
+	case "Discard":
+		dc.Fill() // difference for this subpile
+	case "Cell":
+		// just the outline
+	default:
+		// eg Reserve, Waste
+		return nil
+	}
+	dc.Stroke()
+	return ebiten.NewImageFromImage(dc.Image())
+}
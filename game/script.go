@@ -0,0 +1,35 @@
+package game
+
+import (
+	"time"
+
+	"oddstream.games/gosol/sol"
+)
+
+// scriptCheckInterval is how often variant scripts are checked for changes, in debug mode
+const scriptCheckInterval = time.Second
+
+// updateScripts reloads any variant scripts that have been changed, in debug mode,
+// and redeals the game being played if its script was one of them,
+// so rules can be tried out as they are written
+func (g *Game) updateScripts() {
+	if !sol.DebugMode || time.Since(g.scriptsSeen) < scriptCheckInterval {
+		return
+	}
+	g.scriptsSeen = time.Now()
+	reloaded, errs := sol.ReloadScripts()
+	for _, err := range errs {
+		g.UI.ToastError(err.Error())
+	}
+	for _, variant := range reloaded {
+		if variant != g.Baize.Variant() {
+			continue
+		}
+		seed := g.Baize.Seed()
+		if b := sol.NewBaize(variant, g.Settings); b != nil {
+			g.replaceBaize(b)
+			g.Baize.NewDealFromSeed(seed)
+			g.UI.ToastInfo("Reloaded " + variant)
+		}
+	}
+}
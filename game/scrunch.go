@@ -1,9 +1,11 @@
-package sol
+package game
 
 //lint:file-ignore ST1006 Receiver name will be anything I like, thank you
 
 import (
 	"fmt"
+
+	"oddstream.games/gosol/sol"
 )
 
 // func (b *Baize) FindBuddyPiles() {
@@ -66,12 +68,13 @@ import (
 // }
 
 // SizeWithFanFactor calculates the width or height this pile would be if it had a specified fan factor
-func (self *Pile) SizeWithFanFactor(fanFactor float64) int {
+func (self *pileView) SizeWithFanFactor(fanFactor float64) int {
 	var max int
-	switch self.fanType {
-	case FAN_DOWN:
-		for i := 0; i < len(self.cards)-1; i++ {
-			c := self.cards[i]
+	var cards []*sol.Card = self.pile.Cards()
+	switch self.pile.FanType() {
+	case sol.FAN_DOWN:
+		for i := 0; i < len(cards)-1; i++ {
+			c := cards[i]
 			if c.Prone() {
 				max += int(float64(CardHeight) / CARD_BACK_FAN_FACTOR)
 			} else {
@@ -79,9 +82,9 @@ func (self *Pile) SizeWithFanFactor(fanFactor float64) int {
 			}
 		}
 		max += CardHeight
-	case FAN_LEFT, FAN_RIGHT:
-		for i := 0; i < len(self.cards)-1; i++ {
-			c := self.cards[i]
+	case sol.FAN_LEFT, sol.FAN_RIGHT:
+		for i := 0; i < len(cards)-1; i++ {
+			c := cards[i]
 			if c.Prone() {
 				max += int(float64(CardWidth) / CARD_BACK_FAN_FACTOR)
 			} else {
@@ -95,28 +98,28 @@ func (self *Pile) SizeWithFanFactor(fanFactor float64) int {
 
 // Scrunch prepares to refan cards after Push() or Pop(), adjusting the amount of overlap to try to keep them fitting on the screen
 // only Scrunch piles with fanType LEFT/RIGHT/UP/DOWN, ignore the waste-style piles and those that do not fan
-func (self *Pile) Scrunch() {
+func (self *pileView) Scrunch() {
 
-	self.fanFactor = DefaultFanFactor[self.fanType]
+	self.fanFactor = DefaultFanFactor[self.pile.FanType()]
 
-	if NoScrunch || len(self.cards) < 2 {
+	if NoScrunch || self.pile.Len() < 2 {
 		self.Refan()
 		return
 	}
 
 	var maxPileSize int
-	switch self.fanType {
-	case FAN_DOWN:
+	switch self.pile.FanType() {
+	case sol.FAN_DOWN:
 		// baize->dragOffset is always -ve
 		// statusbar height is 24
-		// maxPileSize = TheGame.Baize.WindowHeight - scpos.Y + util.Abs(TheGame.Baize.dragOffset.Y)
-		maxPileSize = TheGame.Baize.WindowHeight - self.ScreenPos().Y + (CardHeight / 2)
-	case FAN_LEFT:
+		// maxPileSize = TheGame.WindowHeight - scpos.Y + util.Abs(TheGame.dragOffset.Y)
+		maxPileSize = TheGame.WindowHeight - self.ScreenPos().Y + (CardHeight / 2)
+	case sol.FAN_LEFT:
 		maxPileSize = self.ScreenPos().X
-	case FAN_RIGHT:
+	case sol.FAN_RIGHT:
 		// baize->dragOffset is always -ve
-		// maxPileSize = TheGame.Baize.WindowWidth - scpos.X + util.Abs(TheGame.Baize.dragOffset.X)
-		maxPileSize = TheGame.Baize.WindowWidth - self.ScreenPos().X
+		// maxPileSize = TheGame.WindowWidth - scpos.X + util.Abs(TheGame.dragOffset.X)
+		maxPileSize = TheGame.WindowWidth - self.ScreenPos().X
 	}
 	if maxPileSize == 0 {
 		// this pile doesn't need scrunching
@@ -126,14 +129,14 @@ func (self *Pile) Scrunch() {
 
 	var nloops int
 	var fanFactor float64
-	for fanFactor = DefaultFanFactor[self.fanType]; fanFactor < 7.0; fanFactor += 0.1 {
+	for fanFactor = DefaultFanFactor[self.pile.FanType()]; fanFactor < 7.0; fanFactor += 0.1 {
 		size := self.SizeWithFanFactor(fanFactor)
-		switch self.fanType {
-		case FAN_DOWN:
+		switch self.pile.FanType() {
+		case sol.FAN_DOWN:
 			if size < maxPileSize {
 				goto exitloop
 			}
-		case FAN_LEFT, FAN_RIGHT:
+		case sol.FAN_LEFT, sol.FAN_RIGHT:
 			if size < maxPileSize {
 				goto exitloop
 			}
@@ -144,9 +147,9 @@ func (self *Pile) Scrunch() {
 	}
 exitloop:
 	self.fanFactor = fanFactor
-	if DebugMode && nloops > 0 {
-		fmt.Printf("%d loops to go from %f to %f", nloops, DefaultFanFactor[self.fanType], self.fanFactor)
-		fmt.Printf(" WindowWidth, Height = %d,%d\n", TheGame.Baize.WindowWidth, TheGame.Baize.WindowHeight)
+	if sol.DebugMode && nloops > 0 {
+		fmt.Printf("%d loops to go from %f to %f", nloops, DefaultFanFactor[self.pile.FanType()], self.fanFactor)
+		fmt.Printf(" WindowWidth, Height = %d,%d\n", TheGame.WindowWidth, TheGame.WindowHeight)
 	}
 	self.Refan()
 }
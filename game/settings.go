@@ -0,0 +1,46 @@
+package game
+
+import (
+	"oddstream.games/gosol/sound"
+	"oddstream.games/gosol/ui"
+)
+
+func ShowSettingsDrawer() {
+	variant := TheGame.Baize.Variant()
+	winnableDeals := TheGame.Settings.WinnableDealsOnly(variant)
+	var BooleanSettings = []ui.BooleanSetting{
+		{Title: "Power moves", Var: &TheGame.Settings.PowerMoves},
+		{Title: "Auto collect", Var: &TheGame.Settings.AutoCollect},
+		{Title: "Safe collect", Var: &TheGame.Settings.SafeCollect},
+		{Title: "Show movable cards", Var: &TheGame.Settings.ShowMovableCards},
+		{Title: "Colorful cards", Var: &TheGame.Settings.ColorfulCards, Update: func() { TheGame.setFlag(dirtyCardImages) }},
+		{Title: "Mute sounds", Var: &TheGame.Settings.Mute, Update: func() {
+			if TheGame.Settings.Mute {
+				sound.SetVolume(0.0)
+			} else {
+				sound.SetVolume(TheGame.Settings.Volume)
+			}
+		}},
+		{Title: "Winnable deals only", Var: &winnableDeals, Update: func() {
+			TheGame.Settings.SetWinnableDealsOnly(variant, winnableDeals)
+		}},
+		{Title: "PySolFC deal numbers", Var: &TheGame.Settings.PySolDeals},
+		{Title: "Mirror baize", Var: &TheGame.Settings.MirrorBaize, Update: func() {
+			savedMoveLog := TheGame.Baize.MoveLog()
+			TheGame.startFreshGame()
+			TheGame.Baize.SetMoveLog(savedMoveLog)
+		}},
+	}
+
+	TheGame.UI.ShowSettingsDrawer(&BooleanSettings)
+}
+
+func ShowAniSpeedDrawer() {
+	var AniSpeedSettings = []ui.FloatSetting{
+		{Title: "Fast", Var: &TheGame.Settings.AniSpeed, Value: 0.3},
+		{Title: "Normal", Var: &TheGame.Settings.AniSpeed, Value: 0.6},
+		{Title: "Slow", Var: &TheGame.Settings.AniSpeed, Value: 0.9},
+	}
+
+	TheGame.UI.ShowAniSpeedDrawer(&AniSpeedSettings)
+}
@@ -0,0 +1,24 @@
+package game
+
+import "fmt"
+
+func ShowStatisticsDrawer() {
+	var strs []string
+	d := TheGame.Baize.Difficulty()
+	hints := TheGame.Baize.HintsUsed()
+	if d != nil || hints > 0 {
+		strs = append(strs, "THIS DEAL")
+		if d != nil {
+			strs = append(strs, "Difficulty: "+d.Rating())
+			strs = append(strs, fmt.Sprintf("Solver positions: %d", d.Nodes))
+			strs = append(strs, fmt.Sprintf("Moves in the win found: %d", d.WinMoves))
+			strs = append(strs, fmt.Sprintf("Forced moves: %d", d.Forced))
+		}
+		if hints > 0 {
+			strs = append(strs, fmt.Sprintf("Hints used: %d", hints))
+		}
+		strs = append(strs, " ")
+	}
+	strs = append(strs, TheGame.Statistics.Strings(TheGame.Baize.Variant())...)
+	TheGame.UI.ShowTextDrawer(strs)
+}
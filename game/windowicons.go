@@ -1,4 +1,4 @@
-package sol
+package game
 
 import (
 	"image"
@@ -17,6 +17,7 @@ import (
 	_ "image/png"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"oddstream.games/gosol/game"
 	sol "oddstream.games/gosol/sol"
 	"oddstream.games/gosol/util"
 )
@@ -27,12 +28,19 @@ func main() {
 
 	// pearl from the mudbank: don't have any flags that will overwrite ThePreferences
 	flag.BoolVar(&sol.DebugMode, "debug", false, "turn debug graphics on")
-	flag.BoolVar(&sol.NoGameLoad, "noload", false, "do not load saved game when starting")
-	flag.BoolVar(&sol.NoGameSave, "nosave", false, "do not save game before exit")
-	flag.BoolVar(&sol.NoScrunch, "noscrunch", false, "do not scrunch cards")
+	flag.BoolVar(&game.NoGameLoad, "noload", false, "do not load saved game when starting")
+	flag.BoolVar(&game.NoGameSave, "nosave", false, "do not save game before exit")
+	flag.BoolVar(&game.NoScrunch, "noscrunch", false, "do not scrunch cards")
+	flag.Uint64Var(&game.DealNumber, "deal", 0, "start deal number N of the current variant")
+	var selfTestMode bool
+	flag.BoolVar(&selfTestMode, "selftest", false, "play random moves in every variant, checking the engine after each one, then exit")
 
 	flag.Parse()
 
+	if selfTestMode {
+		os.Exit(selfTest())
+	}
+
 	if sol.DebugMode {
 		for i, a := range os.Args {
 			log.Println(i, a)
@@ -50,21 +58,21 @@ func main() {
 		n := util.Max(x, y)
 		ebiten.SetWindowSize(n/2, n/2)
 	}
-	ebiten.SetWindowIcon(sol.WindowIcons())
+	ebiten.SetWindowIcon(game.WindowIcons())
 	ebiten.SetWindowTitle("Go Solitaire")
 
-	sol.NewGame() // sets sol.TheGame
+	game.NewGame() // sets game.TheGame
 
-	if err := ebiten.RunGame(sol.TheGame); err != nil {
+	if err := ebiten.RunGame(game.TheGame); err != nil {
 		log.Fatal(err)
 	}
 
 	// we come here if the user closed the window with the x button
 	// println("main exit")
 
-	if !sol.NoGameSave {
-		sol.TheGame.Baize.Save()
+	if !game.NoGameSave {
+		game.TheGame.Baize.Save()
 	}
 
-	sol.TheGame.Settings.Save()
+	game.TheGame.Settings.Save()
 }
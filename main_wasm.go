@@ -12,21 +12,21 @@ import (
 	_ "image/png"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	sol "oddstream.games/gosol/sol"
+	"oddstream.games/gosol/game"
 )
 
 func main() {
-	sol.NewGame() // sets sol.TheGame
+	game.NewGame() // sets game.TheGame
 
 	defer func() {
 		log.Println("main defer cleanup")
-		if !sol.NoGameSave {
-			sol.TheGame.Baize.Save()
+		if !game.NoGameSave {
+			game.TheGame.Baize.Save()
 		}
-		sol.TheGame.Settings.Save()
+		game.TheGame.Settings.Save()
 	}()
 
-	if err := ebiten.RunGame(sol.TheGame); err != nil {
+	if err := ebiten.RunGame(game.TheGame); err != nil {
 		log.Fatal(err)
 	}
 }
@@ -0,0 +1,56 @@
+//go:build linux || windows
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"oddstream.games/gosol/game"
+	"oddstream.games/gosol/sol"
+)
+
+// selfTestDeals and selfTestMoves are how much of each variant -selftest plays
+const (
+	selfTestDeals = 10
+	selfTestMoves = 200
+)
+
+// selfTest plays random moves in deals of every variant, including those in the variants folder,
+// without any graphics, checking the engine after every one, and returns the exit status
+func selfTest() int {
+	var failed int
+	for _, err := range sol.LoadVariants() {
+		fmt.Println(err)
+		failed++
+	}
+	variants := sol.VariantNames("> All", nil)
+
+	seed := game.DealNumber
+	if seed == 0 {
+		seed = 1
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	for _, v := range variants {
+		errs := sol.SelfTest(ctx, []string{v}, selfTestDeals, seed, selfTestMoves)
+		if len(errs) == 0 {
+			fmt.Printf("%-24s ok\n", v)
+			continue
+		}
+		failed++
+		fmt.Printf("%-24s FAILED\n", v)
+		for _, err := range errs {
+			fmt.Printf("\t%s\n", err)
+		}
+	}
+	if failed > 0 {
+		fmt.Printf("%d variants failed\n", failed)
+		return 1
+	}
+	return 0
+}
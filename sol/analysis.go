@@ -0,0 +1,148 @@
+package sol
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"oddstream.games/gosol/util"
+)
+
+// AnalysisNodes is how many positions the solver may expand for each position of a game it analyses
+var AnalysisNodes = 20000
+
+// AnalysisTime is how long an analysis may take altogether
+var AnalysisTime = time.Minute
+
+// Analysis finds the last position in a game that could still be won,
+// by running the solver backwards over the move log.
+// Make one with Baize.NewAnalysis, then Analyse it on a goroutine of its own.
+type Analysis struct {
+	LastWinnable int  // number of moves made before the game was lost, or -1 if no winnable position was found
+	Undecided    int  // number of positions after LastWinnable that the solver couldn't decide about
+	Losing       Move // the move made from the last winnable position
+	LosingKnown  bool // false if the losing move couldn't be worked out (eg it was a Collect)
+	Better       Move // the first move of a win from the last winnable position
+	BetterKnown  bool
+	Nodes        int // positions the solver expanded altogether
+
+	// the moves put into words, as they looked at the last winnable position
+	LosingDescription, BetterDescription string
+
+	variant   string
+	settings  Settings
+	positions []*SavableBaize
+}
+
+// NewAnalysis copies the game on this baize, ready to be analysed.
+// Call it on the goroutine that owns the baize; Analyse can then be run on any other.
+func (b *Baize) NewAnalysis() *Analysis {
+	a := &Analysis{LastWinnable: -1, variant: b.variant, settings: *b.settings}
+	a.positions = b.positions()
+	return a
+}
+
+// Positions returns the number of positions in the game being analysed, including the deal
+func (a *Analysis) Positions() int {
+	return len(a.positions)
+}
+
+// StillWinnable returns true if the position at the end of the game can be won
+func (a *Analysis) StillWinnable() bool {
+	return a.LastWinnable == len(a.positions)-1
+}
+
+// Analyse solves each position of the game, starting with the last,
+// until it finds one that can be won, or runs out of positions, time, or ctx is cancelled
+func (a *Analysis) Analyse(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, AnalysisTime)
+	defer cancel()
+	for i := len(a.positions) - 1; i >= 0; i-- {
+		if ctx.Err() != nil {
+			return
+		}
+		s := NewSolver(a.variant, &a.settings, a.positions[i])
+		if s == nil {
+			return
+		}
+		s.MaxNodes = AnalysisNodes
+		r := s.Solve(ctx)
+		a.Nodes += r.Nodes
+		switch r.Outcome {
+		case SOLVED:
+			if DebugMode {
+				log.Printf("%s position %d of %d can be won", a.variant, i, len(a.positions)-1)
+			}
+			a.LastWinnable = i
+			if moves, _ := s.shorten(); len(moves) > 0 {
+				a.Better, a.BetterKnown = moves[0], true
+			}
+			if i < len(a.positions)-1 {
+				a.Losing, a.LosingKnown = s.findMove(a.positions[i], a.positions[i+1])
+			}
+			s.b.updateFromSavable(a.positions[i])
+			if a.LosingKnown {
+				a.LosingDescription = s.b.DescribeMove(a.Losing)
+			}
+			if a.BetterKnown {
+				a.BetterDescription = s.b.DescribeMove(a.Better)
+			}
+			return
+		case GAVE_UP:
+			a.Undecided++
+		}
+	}
+}
+
+// findMove works out which single move gets from one position to the next
+func (s *Solver) findMove(from, to *SavableBaize) (Move, bool) {
+	b := s.b
+	b.updateFromSavable(to)
+	want := b.Hash()
+	b.updateFromSavable(from)
+	b.restartLog()
+	for _, m := range b.LegalMoves() {
+		if !b.ApplyMove(m) {
+			continue
+		}
+		found := b.Hash() == want
+		b.rewindTo(0)
+		if found {
+			return m, true
+		}
+	}
+	return Move{}, false
+}
+
+// DescribeMove puts a move into words, eg "Jack of Hearts from Tableau to Foundation"
+func (b *Baize) DescribeMove(m Move) string {
+	src := b.piles[m.Src]
+	if m.Dst < 0 {
+		return fmt.Sprintf("Tap the %s", src.category)
+	}
+	if m.N < 1 || m.N > src.Len() {
+		return m.String()
+	}
+	c := src.cards[src.Len()-m.N]
+	what := fmt.Sprintf("%s of %ss", util.ShortOrdinalToLongOrdinal(util.OrdinalToShortString(c.Ordinal())), c.id.StringSuit())
+	if m.N > 1 {
+		what = fmt.Sprintf("%s and %d more", what, m.N-1)
+	}
+	return fmt.Sprintf("%s from %s to %s", what, src.category, b.piles[m.Dst].category)
+}
+
+// RewindTo goes back to the position after the given number of moves, as if they had been undone
+func (b *Baize) RewindTo(moves int) {
+	if b.Complete() {
+		b.toastError("Cannot undo a completed game") // otherwise the stats can be cooked
+		return
+	}
+	if moves < 0 || moves > len(b.steps) {
+		b.toastError("Cannot go back there")
+		return
+	}
+	b.takeBack(moves)
+	b.playSound("TakeOutPackage")
+	b.FindDestinations()
+}
@@ -0,0 +1,70 @@
+package sol
+
+import (
+	"context"
+	"testing"
+)
+
+func TestAnalyseWinnableGame(t *testing.T) {
+	b := NewBaize("Klondike", nil)
+	b.StartFreshGame()
+	b.NewDealFromSeed(1)
+	r := b.NewSolver().Solve(context.Background())
+	if r.Outcome != SOLVED {
+		t.Fatal("Klondike deal #1 should be won")
+	}
+	// follow the win for a few moves, so the game can still be won
+	for _, m := range r.Moves[:5] {
+		if !b.ApplyMove(m) {
+			t.Fatalf("move %s failed", m)
+		}
+	}
+	a := b.NewAnalysis()
+	a.Analyse(context.Background())
+	if !a.StillWinnable() || !a.BetterKnown || a.BetterDescription == "" {
+		t.Errorf("got last winnable position %d of %d, want the last", a.LastWinnable, a.Positions()-1)
+	}
+}
+
+func TestAnalyseLostGame(t *testing.T) {
+	// Simple Simon deal #1 can't be won from the start
+	b := NewBaize("Simple Simon", nil)
+	b.StartFreshGame()
+	b.NewDealFromSeed(1)
+	a := b.NewAnalysis()
+	a.Analyse(context.Background())
+	if a.LastWinnable != -1 {
+		t.Errorf("got last winnable position %d, want none", a.LastWinnable)
+	}
+}
+
+func TestFindMove(t *testing.T) {
+	b := NewBaize("Klondike", nil)
+	b.StartFreshGame()
+	b.NewDealFromSeed(1)
+	from := b.UndoPeek()
+	want := b.LegalMoves()[0]
+	if !b.ApplyMove(want) {
+		t.Fatalf("move %s failed", want)
+	}
+	s := NewSolver(b.Variant(), nil, from)
+	if got, ok := s.findMove(from, b.UndoPeek()); !ok || got != want {
+		t.Errorf("got move %s, want %s", got, want)
+	}
+}
+
+func TestRewindTo(t *testing.T) {
+	b := NewBaize("Klondike", nil)
+	b.StartFreshGame()
+	b.NewDealFromSeed(1)
+	before := b.Hash()
+	for i := 0; i < 2; i++ {
+		if !b.ApplyMove(b.LegalMoves()[0]) {
+			t.Fatal("move failed")
+		}
+	}
+	b.RewindTo(0)
+	if b.MovesMade() != 0 || b.Hash() != before {
+		t.Errorf("rewind to the deal left %d moves made", b.MovesMade())
+	}
+}
@@ -2,118 +2,138 @@ package sol
 
 import (
 	"fmt"
-	"hash/crc32"
 	"image"
 	"log"
+	"math/rand"
+	"time"
 
-	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
-	"oddstream.games/gosol/input"
-	"oddstream.games/gosol/sound"
-	"oddstream.games/gosol/ui"
 	"oddstream.games/gosol/util"
 )
 
-const (
-	dirtyWindowSize = 1 << iota
-	dirtyPilePositions
-	dirtyCardSizes
-	dirtyCardImages
-	dirtyPileBackgrounds
-	dirtyCardPositions
-)
-
 // Baize object describes the baize
 type Baize struct {
-	variant      string
-	piles        []*Pile
-	cardCount    int
-	recycles     int
-	bookmark     int
-	script       Scripter
-	undoStack    []*SavableBaize
-	dirtyFlags   uint32 // what needs doing when we Update
-	moves        int    // number of possible (not useless) moves
-	fmoves       int    // number of possible moves to a Foundation (for enabling Collect button)
-	stroke       *input.Stroke
-	dragStart    image.Point
-	dragOffset   image.Point
-	WindowWidth  int // the most recent window width given to Layout
-	WindowHeight int // the most recent window height given to Layout
-	// hotCard      *Card
+	variant    string
+	piles      []*Pile
+	cardCount  int
+	recycles   int
+	bookmarks  []Bookmark
+	seed       uint64      // the seed used to shuffle the cards for this deal
+	winnable   bool        // true if a solver has proven this deal can be won
+	difficulty *Difficulty // how hard the solver found this deal, if known
+	hints      int         // number of hints asked for in this game
+	script     Scripter
+	snapshots  []*SavableBaize // the move log: the deal, then every SnapshotInterval moves
+	steps      []*Step         // the move log: what each move did
+	position   *SavableBaize   // the position at the end of the move log
+	redo       []*Step         // moves undone that can be made again, the next one last
+	branches   []Branch        // other lines of play, left behind by making a different move after an undo
+	hashes     []uint64        // the Hash of each position in the move log
+	moving     int             // how deep in BeginMove/CommitMove we are
+	movingHash uint64          // the Hash of the baize when the outermost BeginMove was called
+	moves      int             // number of possible (not useless) moves
+	fmoves     int             // number of possible moves to a Foundation (for enabling Collect button)
+	rankOf     [16]int         // where each ordinal comes in the ranks of a suit, counting from 1; 0 if it isn't in the pack
+	topRank    int             // the rank of the highest card in a suit, eg 13 for a King in a full pack
+	settings   *Settings
+	observer   Observer
 }
 
 //--+----1----+----2----+----3----+----4----+----5----+----6----+----7----+----8
 
-// NewBaize is the factory func for the single Baize object
-func NewBaize(variant string) *Baize {
-	// let WindowWidth, WindowHeight be zero, so that the first Layout will
-	// trigger card scaling and pile placement
-	var script Scripter
+// NewBaize is the factory func for Baize objects.
+//
+// Each Baize gets its own copy of the variant's script,
+// so any number of them can be alive at once.
+// If settings is nil, the default settings are used.
+func NewBaize(variant string, settings *Settings) *Baize {
+	var proto Scripter
 	var ok bool
-	if script, ok = Variants[variant]; !ok {
+	if proto, ok = LookupVariant(variant); !ok {
 		log.Printf("do not know how to play " + variant)
 		return nil
 	}
-	return &Baize{variant: variant, script: script, dirtyFlags: 0xFFFF}
+	if settings == nil {
+		settings = DefaultSettings()
+	}
+	b := &Baize{variant: variant, settings: settings}
+	b.script = cloneScript(proto)
+	b.script.setBaize(b)
+	return b
 }
 
-func (b *Baize) flagSet(flag uint32) bool {
-	return b.dirtyFlags&flag == flag
+// func (b *Baize) Valid() bool {
+// 	return b != nil
+// }
+
+func (b *Baize) AddPile(pile *Pile) {
+	pile.index = len(b.piles)
+	b.piles = append(b.piles, pile)
 }
 
-func (b *Baize) setFlag(flag uint32) {
-	b.dirtyFlags |= flag
+// Variant returns the name of the variant being played on this Baize
+func (b *Baize) Variant() string {
+	return b.variant
 }
 
-// func (b *Baize) clearFlag(flag uint32) {
-// 	b.dirtyFlags &= ^flag
-// }
+// Piles returns all the piles on this Baize, in the order they were built
+func (b *Baize) Piles() []*Pile {
+	return b.piles
+}
 
-// func (b *Baize) Valid() bool {
-// 	return b != nil
-// }
+// Script returns the Scripter that implements the rules for this Baize
+func (b *Baize) Script() Scripter {
+	return b.script
+}
 
-func (b *Baize) CRC() uint32 {
-	/*
-		var crc uint = 0xFFFFFFFF
-		var mask uint
-		for _, p := range b.piles {
-			crc = crc ^ uint(p.Len())
-			for j := 7; j >= 0; j-- {
-				mask = -(crc & 1)
-				crc = (crc >> 1) ^ (0xEDB88320 & mask)
-			}
-		}
-		return ^crc // bitwise NOT
-	*/
-	var lens []byte
-	for _, p := range b.piles {
-		lens = append(lens, byte(p.Len()))
-	}
-	return crc32.ChecksumIEEE(lens)
+// Settings returns the Settings used by this Baize
+func (b *Baize) Settings() *Settings {
+	return b.settings
 }
 
-func (b *Baize) AddPile(pile *Pile) {
-	b.piles = append(b.piles, pile)
+// NewSeed returns a seed for a random deal.
+// Any uint64 is a valid seed, but these are kept small enough to be read and typed in.
+func NewSeed() uint64 {
+	return uint64(rand.New(rand.NewSource(time.Now().UnixNano())).Int63n(1000000000)) + 1
+}
+
+// Seed returns the seed used to shuffle the cards for this deal
+func (b *Baize) Seed() uint64 {
+	return b.seed
 }
 
-func (b *Baize) Refan() {
-	b.setFlag(dirtyCardPositions)
+// PySolDeals returns true if deals are being numbered (and laid out) as PySolFC does,
+// which only happens if the player wants it and this variant has a PySolFC twin
+func (b *Baize) PySolDeals() bool {
+	return b.settings.PySolDeals && b.script.PySol() != ""
+}
+
+// setRanks remembers the order of the ranks in each suit of the pack, nil meaning Ace to King
+func (b *Baize) setRanks(ranks []int) {
+	if ranks == nil {
+		ranks = standardRanks
+	}
+	b.rankOf = [16]int{}
+	for i, ord := range ranks {
+		b.rankOf[ord] = i + 1
+	}
+	b.topRank = len(ranks)
 }
 
 // NewDeal restarts current variant (ie no pile building) with a new seed
 func (b *Baize) NewDeal() {
+	b.NewDealFromSeed(NewSeed())
+}
 
-	// a virgin game has one state on the undo stack
-	if len(b.undoStack) > 1 && !b.Complete() {
-		percent := b.PercentComplete()
-		toastStr := TheGame.Statistics.RecordLostGame(b.variant, percent)
-		TheGame.UI.Toast("Fail", toastStr)
-	}
+// NewDealFromSeed restarts current variant (ie no pile building) with a given seed,
+// so the same seed always gives the same deal
+func (b *Baize) NewDealFromSeed(seed uint64) {
 
 	// for {
 	b.Reset()
+	b.seed = seed
+	b.winnable = false
+	b.difficulty = nil
+	b.hints = 0
 
 	for _, p := range b.piles {
 		p.Reset()
@@ -122,7 +142,9 @@ func (b *Baize) NewDeal() {
 	// Stock.Fill() needs parameters
 	packs := b.script.Packs()
 	suits := b.script.Suits()
-	b.cardCount = b.script.Stock().Fill(packs, suits)
+	ranks := b.script.Ranks()
+	b.setRanks(ranks)
+	b.cardCount = b.script.Stock().Fill(packs, suits, ranks, b.script.Jokers())
 	b.script.Stock().Shuffle()
 	b.script.StartGame()
 	b.UndoPush()
@@ -131,12 +153,10 @@ func (b *Baize) NewDeal() {
 	// 	if b.moves > 0 {
 	// 		break
 	// 	}
-	// 	TheGame.UI.Toast("Glass", "Found a deal with no moves")
+	// 	b.toast("Glass", "Found a deal with no moves")
 	// }
 
-	sound.Play("Fan")
-
-	b.setFlag(dirtyCardPositions)
+	b.playSound("Fan")
 }
 
 func (b *Baize) MirrorSlots() {
@@ -180,9 +200,8 @@ func (b *Baize) MirrorSlots() {
 }
 
 func (b *Baize) Reset() {
-	b.StopSpinning()
-	b.undoStack = []*SavableBaize{}
-	b.bookmark = 0
+	b.snapshots, b.steps, b.position, b.redo = nil, nil, nil, nil
+	b.bookmarks, b.branches, b.hashes = nil, nil, nil
 	b.recycles = 0
 	// leave script intact
 }
@@ -190,419 +209,162 @@ func (b *Baize) Reset() {
 // StartFreshGame resets Baize and starts a new game with a new seed
 func (b *Baize) StartFreshGame() {
 	b.Reset()
+	b.seed = NewSeed()
+	b.winnable = false
+	b.difficulty = nil
+	b.hints = 0
 	b.piles = []*Pile{}
 	b.script.BuildPiles()
-	if TheGame.Settings.MirrorBaize {
+	if b.settings.MirrorBaize {
 		b.MirrorSlots()
 	}
 	// b.FindBuddyPiles()
 
-	TheGame.UI.SetTitle(b.variant)
-	sound.Play("Fan")
-	b.dirtyFlags = 0xFFFF
+	b.playSound("Fan")
 
 	b.script.StartGame()
 	b.UndoPush()
 	b.FindDestinations()
 }
 
-func (b *Baize) ChangeVariant(newVariant string) {
-	// no longer record a lost game here because variants saved in separate .json files
-	var newScript Scripter
-	var ok bool
-	if newScript, ok = Variants[newVariant]; !ok {
-		TheGame.UI.Toast("Error", "Do not know how to play "+newVariant)
-		return
-	}
-	b.Save()
-	b.variant = newVariant
-	TheGame.Settings.Variant = b.variant
-	TheGame.Settings.Save()
-	b.script = newScript
-	b.StartFreshGame()
-	if !NoGameLoad {
-		TheGame.Baize.Load()
-	}
+// MovesMade returns the number of moves the player has made in this game
+func (b *Baize) MovesMade() int {
+	return len(b.steps)
 }
 
-func (b *Baize) SetUndoStack(undoStack []*SavableBaize) {
-	b.undoStack = undoStack
-	TheGame.UI.Toast("Glass", "Loaded a saved game of "+b.variant)
-	sav := b.UndoPeek()
-	b.updateFromSavable(sav)
-	b.FindDestinations()
-	TheGame.UI.HideFAB()
-	if b.Complete() {
-		TheGame.UI.Toast("Complete", "Complete")
-		TheGame.UI.AddButtonToFAB("star", ebiten.KeyN)
-		b.StartSpinning()
-	} else if b.Conformant() {
-		TheGame.UI.AddButtonToFAB("done_all", ebiten.KeyC)
-	} else if b.moves == 0 {
-		TheGame.UI.Toast("Error", "No movable cards")
-		TheGame.UI.AddButtonToFAB("star", ebiten.KeyN)
-		TheGame.UI.AddButtonToFAB("restore", ebiten.KeyR)
-		if b.bookmark > 0 {
-			TheGame.UI.AddButtonToFAB("bookmark", ebiten.KeyL)
-		}
-	}
+// Moves returns the number of possible (not useless) moves, as found by FindDestinations
+func (b *Baize) Moves() int {
+	return b.moves
 }
 
-// findPileAt finds the Pile under the mouse position
-func (b *Baize) FindPileAt(pt image.Point) *Pile {
-	for _, p := range b.piles {
-		if pt.In(p.ScreenRect()) {
-			return p
-		}
-	}
-	return nil
+// FoundationMoves returns the number of possible moves to a Foundation, as found by FindDestinations
+func (b *Baize) FoundationMoves() int {
+	return b.fmoves
 }
 
-// FindLowestCardAt finds the bottom-most Card under the mouse position
-func (b *Baize) FindLowestCardAt(pt image.Point) *Card {
-	for _, p := range b.piles {
-		for i := p.Len() - 1; i >= 0; i-- {
-			c := p.Get(i)
-			if pt.In(c.ScreenRect()) {
-				return c
-			}
-		}
-	}
-	return nil
-}
-
-// FindHighestCardAt finds the top-most Card under the mouse position
-func (b *Baize) FindHighestCardAt(pt image.Point) *Card {
-	for _, p := range b.piles {
-		for _, c := range p.cards {
-			if pt.In(c.ScreenRect()) {
-				return c
-			}
-		}
+// AfterUserMove runs the script's AfterMove, records the move for undo and finds destinations again,
+// unless the move is part of a bigger one, started by BeginMove, when CommitMove will do all that
+func (b *Baize) AfterUserMove() {
+	if b.moving > 0 {
+		return
 	}
-	return nil
-}
-
-func (b *Baize) LargestIntersection(c *Card) *Pile {
-	var largestArea int = 0
-	var pile *Pile = nil
-	cardRect := c.BaizeRect()
-	for _, p := range b.piles {
-		if p == c.Owner() {
-			continue
-		}
-		pileRect := p.FannedBaizeRect()
-		intersectRect := pileRect.Intersect(cardRect)
-		area := intersectRect.Dx() * intersectRect.Dy()
-		if area > largestArea {
-			largestArea = area
-			pile = p
+	b.script.AfterMove()
+	b.UndoPush()
+	b.FindDestinations()
+	if b.observer != nil {
+		if n := b.Repeated(); n > 0 {
+			b.toastInfo(fmt.Sprintf("Back where you were %d moves ago; going round in circles?", n))
 		}
+		b.observer.AfterUserMove()
 	}
-	return pile
-}
-
-// StartDrag return true if the Baize can be dragged
-func (b *Baize) StartDrag() bool {
-	b.dragStart = b.dragOffset
-	return true
 }
 
-// DragBy move ('scroll') the Baize by dragging it
-// dx, dy is the difference between where the drag started and where the cursor is now
-func (b *Baize) DragBy(dx, dy int) {
-	b.dragOffset.X = b.dragStart.X + dx
-	if b.dragOffset.X > 0 {
-		b.dragOffset.X = 0 // DragOffsetX should only ever be 0 or -ve
-	}
-	b.dragOffset.Y = b.dragStart.Y + dy
-	if b.dragOffset.Y > 0 {
-		b.dragOffset.Y = 0 // DragOffsetY should only ever be 0 or -ve
+// BeginMove starts a move made of any number of card moves (eg MoveCard, MoveTail)
+// that is recorded, undone and redone as one move. Calls can be nested.
+func (b *Baize) BeginMove() {
+	if b.moving == 0 {
+		b.movingHash = b.Hash()
 	}
+	b.moving++
 }
 
-// StopDrag stop dragging the Baize
-func (b *Baize) StopDrag() {
-	b.setFlag(dirtyCardPositions)
-}
-
-// StartSpinning tells all the cards to start spinning
-func (b *Baize) StartSpinning() {
-	for _, p := range b.piles {
-		// use a method expression, which yields a function value with a regular first parameter taking the place of the receiver
-		p.ApplyToCards((*Card).StartSpinning)
+// CommitMove ends a move started by BeginMove.
+// When the outermost move ends, and has changed the baize,
+// AfterUserMove is called once for the whole move.
+// Returns true if the baize has changed.
+func (b *Baize) CommitMove() bool {
+	if b.moving == 0 {
+		log.Panic("CommitMove without BeginMove")
 	}
-}
-
-// StopSpinning tells all the cards to stop spinning and return to their upright position
-// debug only
-func (b *Baize) StopSpinning() {
-	for _, p := range b.piles {
-		// use a method expression, which yields a function value with a regular first parameter taking the place of the receiver
-		p.ApplyToCards((*Card).StopSpinning)
-	}
-	b.setFlag(dirtyCardPositions)
-}
-
-func (b *Baize) AfterUserMove() {
-	b.script.AfterMove()
-	b.UndoPush()
-	b.FindDestinations()
-	TheGame.UI.HideFAB()
-	if b.Complete() {
-		TheGame.UI.AddButtonToFAB("star", ebiten.KeyN)
-		b.StartSpinning()
-		{
-			var toastStr = TheGame.Statistics.RecordWonGame(b.variant, len(b.undoStack)-1)
-			TheGame.UI.Toast("Complete", toastStr)
-		}
-		ShowStatisticsDrawer()
-	} else if b.Conformant() {
-		TheGame.UI.AddButtonToFAB("done_all", ebiten.KeyC)
-	} else if b.moves == 0 {
-		TheGame.UI.ToastError("No movable cards")
-		TheGame.UI.AddButtonToFAB("star", ebiten.KeyN)
-		TheGame.UI.AddButtonToFAB("restore", ebiten.KeyR)
-		if b.bookmark > 0 {
-			TheGame.UI.AddButtonToFAB("bookmark", ebiten.KeyL)
-		}
+	b.moving--
+	changed := b.Hash() != b.movingHash
+	if b.moving == 0 && changed {
+		b.AfterUserMove()
 	}
+	return changed
 }
 
 // AfterAfterMove checks for and executes an automatic collect.
 // Kept as separated-out function at the moment, in case this
 // creates a horrible recursive loop
 func (b *Baize) AfterAfterUserMove() {
-	if b.fmoves > 0 && TheGame.Settings.AutoCollect {
+	if b.fmoves > 0 && b.settings.AutoCollect {
 		b.Collect2()
 	}
 }
 
-/*
-InputStart finds out what object the user input is starting on
-(UI Container > Card > Pile > Baize, in that order)
-then tells that object.
-
-If the Input starts on a Card, then a tail of cards is formed.
-*/
-func (b *Baize) InputStart(v input.StrokeEvent) {
-	b.stroke = v.Stroke
-
-	if con := TheGame.UI.FindContainerAt(v.X, v.Y); con != nil {
-		if w := con.FindWidgetAt(v.X, v.Y); w != nil {
-			b.stroke.SetDraggedObject(w)
-		} else {
-			con.StartDrag()
-			b.stroke.SetDraggedObject(con)
-		}
-	} else {
-		pt := image.Pt(v.X, v.Y)
-		if card := b.FindLowestCardAt(pt); card != nil {
-			if card.Lerping() {
-				TheGame.UI.Toast("Glass", "Confusing to move a moving card")
-				v.Stroke.Cancel()
-			} else {
-				tail := card.Owner().MakeTail(card)
-				b.StartTailDrag(tail)
-				b.stroke.SetDraggedObject(tail)
-			}
-		} else {
-			if p := b.FindPileAt(pt); p != nil {
-				b.stroke.SetDraggedObject(p)
-			} else {
-				if b.StartDrag() {
-					b.stroke.SetDraggedObject(b)
-				} else {
-					v.Stroke.Cancel()
-				}
+// DropTail is called when the player has dragged a tail of cards onto a pile.
+// If the move is not allowed, false and an error explaining why are returned,
+// and nothing changes.
+func (b *Baize) DropTail(tail []*Card, dst *Pile) (bool, error) {
+	var card *Card = tail[0] // for readability
+	var src *Pile = card.Owner()
+	// generically speaking, can this tail be moved?
+	if ok, err := src.CanMoveTail(tail); !ok {
+		return false, err
+	}
+	if len(tail) == 1 {
+		// in games like Pyramid, dropping a card on the one it pairs with removes them both
+		if other, ok, err := b.pairMove(card, dst); ok {
+			b.BeginMove()
+			b.removeCards(card, other)
+			if b.CommitMove() {
+				b.AfterAfterUserMove()
 			}
+			return true, nil
+		} else if err != nil {
+			return false, err
 		}
 	}
-}
-
-func (b *Baize) InputMove(v input.StrokeEvent) {
-	if v.Stroke.DraggedObject() == nil {
-		return
-		// log.Panic("*** move stroke with nil dragged object ***")
+	if ok, err := dst.vtable.CanAcceptTail(tail); !ok {
+		return false, err
 	}
-	// for _, p := range b.piles {
-	// 	p.target = false
-	// }
-	switch obj := v.Stroke.DraggedObject().(type) {
-	case ui.Containery:
-		obj.DragBy(v.Stroke.PositionDiff())
-	case ui.Widgety:
-		obj.Parent().DragBy(v.Stroke.PositionDiff())
-	case []*Card:
-		dx, dy := v.Stroke.PositionDiff()
-		b.DragTailBy(obj, dx, dy)
-		// if c, ok := v.Stroke.DraggedObject().(*Card); ok {
-		// 	if p := b.LargestIntersection(c); p != nil {
-		// 		p.target = true
-		// 	}
-		// }
-	case *Pile:
-		// do nothing
-	case *Baize:
-		b.DragBy(v.Stroke.PositionDiff())
-	default:
-		log.Panic("*** unknown move dragging object ***")
+	// it's ok to move this tail
+	if src == dst {
+		return true, nil
 	}
-}
-
-func (b *Baize) InputStop(v input.StrokeEvent) {
-	if v.Stroke.DraggedObject() == nil {
-		return
-		// log.Panic("*** stop stroke with nil dragged object ***")
+	if ok, err := b.script.TailMoveError(tail); !ok {
+		return false, err
 	}
-	// for _, p := range b.piles {
-	// 	p.SetTarget(false)
-	// }
-	switch obj := v.Stroke.DraggedObject().(type) {
-	case ui.Containery:
-		obj.StopDrag()
-	case ui.Widgety:
-		obj.Parent().StopDrag()
-	case []*Card:
-		tail := obj     // alias for readability
-		card := tail[0] // for readability
-		if card.WasDragged() {
-			src := card.Owner()
-			// tap handled elsewhere
-			// tap is time-limited
-			if dst := b.LargestIntersection(card); dst == nil {
-				// println("no intersection for", c.String())
-				b.CancelTailDrag(tail)
-			} else {
-				var ok bool
-				var err error
-				// generically speaking, can this tail be moved?
-				if ok, err = src.CanMoveTail(tail); !ok {
-					TheGame.UI.ToastError(err.Error())
-					b.CancelTailDrag(tail)
-				} else {
-					if ok, err = dst.vtable.CanAcceptTail(tail); !ok {
-						TheGame.UI.ToastError(err.Error())
-						b.CancelTailDrag(tail)
-					} else {
-						// it's ok to move this tail
-						if src == dst {
-							b.CancelTailDrag(tail)
-						} else if ok, err = b.script.TailMoveError(tail); !ok {
-							TheGame.UI.ToastError(err.Error())
-							b.CancelTailDrag(tail)
-						} else {
-							crc := b.CRC()
-							if len(tail) == 1 {
-								MoveCard(src, dst)
-							} else {
-								MoveTail(card, dst)
-							}
-							b.StopTailDrag(tail) // do this before AfterUserMove
-							if crc != b.CRC() {
-								b.AfterUserMove()
-								b.AfterAfterUserMove()
-							}
-						}
-					}
-				}
-			}
-		}
-		if DebugMode && card.Dragging() {
-			log.Printf("Card %s is still dragging", card.String())
-		}
-	case *Pile:
-		// do nothing
-	case *Baize:
-		// println("stop dragging baize")
-		b.StopDrag()
-	default:
-		log.Panic("*** stop dragging unknown object ***")
-	}
-}
-
-func (b *Baize) InputCancel(v input.StrokeEvent) {
-	if v.Stroke.DraggedObject() == nil {
-		log.Print("*** cancel stroke with nil dragged object ***")
-		return
+	b.BeginMove()
+	if len(tail) == 1 {
+		MoveCard(src, dst)
+	} else {
+		MoveTail(card, dst)
 	}
-	switch obj := v.Stroke.DraggedObject().(type) { // type switch
-	case ui.Containery:
-		obj.CancelDrag()
-	case ui.Widgety:
-		obj.Parent().CancelDrag()
-	case []*Card:
-		b.CancelTailDrag(obj)
-	case *Pile:
-		// p := v.Stroke.DraggedObject().(*Pile)
-		// println("stop dragging pile", p.Class)
-		// do nothing
-	case *Baize:
-		// println("stop dragging baize")
-		b.StopDrag()
-	default:
-		log.Panic("*** cancel dragging unknown object ***")
+	if b.CommitMove() {
+		b.AfterAfterUserMove()
 	}
+	return true, nil
 }
 
-func (b *Baize) InputTap(v input.StrokeEvent) {
-	// stroke sends a tap event, and later sends a cancel event
-	// println("Baize.NotifyCallback() tap", v.X, v.Y)
-	switch obj := v.Stroke.DraggedObject().(type) {
-	case ui.Containery:
-		obj.Tapped()
-	case ui.Widgety:
-		obj.Tapped()
-	case []*Card:
-		// offer TailTapped to the script first
-		// to implement things like Stock.TailTapped
-		// if the script doesn't want to do anything, it can call pile.vtable.TailTapped
-		// which will either ignore it (eg Foundation, Discard)
-		// or use Pile.DefaultTailTapped
-		crc := b.CRC()
-		b.script.TailTapped(obj)
-		if crc != b.CRC() {
-			sound.Play("Slide")
-			b.AfterUserMove()
-			b.AfterAfterUserMove()
-		} else {
-			TheGame.UI.Toast("Error", "Attention!")
-		}
-	case *Pile:
-		crc := b.CRC()
-		b.script.PileTapped(obj)
-		if crc != b.CRC() {
-			sound.Play("Shove")
-			b.AfterUserMove()
-			b.AfterAfterUserMove()
-		}
-	case *Baize:
-		pt := image.Pt(v.X, v.Y)
-		// a tap outside any open ui drawer (ie on the baize) closes the drawer
-		if con := TheGame.UI.VisibleDrawer(); con != nil && !pt.In(image.Rect(con.Rect())) {
-			con.Hide()
-		}
-	default:
-		log.Panic("*** tap unknown object ***")
+// TailTapped is called when the player taps on a tail of cards.
+// Returns true if the tap changed the baize.
+func (b *Baize) TailTapped(tail []*Card) bool {
+	// offer TailTapped to the script first
+	// to implement things like Stock.TailTapped
+	// if the script doesn't want to do anything, it can call pile.vtable.TailTapped
+	// which will either ignore it (eg Foundation, Discard)
+	// or use Pile.DefaultTailTapped
+	b.BeginMove()
+	b.script.TailTapped(tail)
+	if !b.CommitMove() {
+		return false
 	}
+	b.AfterAfterUserMove()
+	return true
 }
 
-// NotifyCallback is called by the Subject (Input/Stroke) when something interesting happens
-func (b *Baize) NotifyCallback(v input.StrokeEvent) {
-	switch v.Event {
-	case input.Start:
-		b.InputStart(v)
-	case input.Move:
-		b.InputMove(v)
-	case input.Stop:
-		b.InputStop(v)
-	case input.Cancel:
-		b.InputCancel(v)
-	case input.Tap:
-		b.InputTap(v)
-	default:
-		log.Panic("*** unknown stroke event ***", v.Event)
+// PileTapped is called when the player taps on a pile (rather than a card in a pile).
+// Returns true if the tap changed the baize.
+func (b *Baize) PileTapped(pile *Pile) bool {
+	b.BeginMove()
+	b.script.PileTapped(pile)
+	if !b.CommitMove() {
+		return false
 	}
+	b.AfterAfterUserMove()
+	return true
 }
 
 // ForeachCard applys a function to each card
@@ -614,40 +376,6 @@ func (b *Baize) ForeachCard(fn func(*Card)) {
 	}
 }
 
-// ApplyToTail applies a method func to this card and all the others after it in the tail
-func (b *Baize) ApplyToTail(tail []*Card, fn func(*Card)) {
-	// https://golang.org/ref/spec#Method_expressions
-	// (*Card).CancelDrag yields a function with the signature func(*Card)
-	// fn passed as a method expression so add the receiver explicitly
-	for _, c := range tail {
-		fn(c)
-	}
-}
-
-// DragTailBy repositions all the cards in the tail: dx, dy is the position difference from the start of the drag
-func (b *Baize) DragTailBy(tail []*Card, dx, dy int) {
-	// println("Baize.DragTailBy(", dx, dy, ")")
-	for _, c := range tail {
-		c.DragBy(dx, dy)
-	}
-}
-
-func (b *Baize) StartTailDrag(tail []*Card) {
-	// hiding the mouse cursor creates flickering when tapping
-	// ebiten.SetCursorMode(ebiten.CursorModeHidden)
-	b.ApplyToTail(tail, (*Card).StartDrag)
-}
-
-func (b *Baize) StopTailDrag(tail []*Card) {
-	// ebiten.SetCursorMode(ebiten.CursorModeVisible)
-	b.ApplyToTail(tail, (*Card).StopDrag)
-}
-
-func (b *Baize) CancelTailDrag(tail []*Card) {
-	// ebiten.SetCursorMode(ebiten.CursorModeVisible)
-	b.ApplyToTail(tail, (*Card).CancelDrag)
-}
-
 func (b *Baize) powerMoves(pDraggingTo *Pile) int {
 	// (1 + number of empty freecells) * 2 ^ (number of empty columns)
 	// see http://ezinearticles.com/?Freecell-PowerMoves-Explained&id=104608
@@ -673,9 +401,9 @@ func (b *Baize) powerMoves(pDraggingTo *Pile) int {
 }
 
 // DoingSafeCollect return true if we are doing safe collect
-// and the safe ordinal to collect next
+// and the highest rank that is safe to collect next
 func (b *Baize) DoingSafeCollect() (bool, int) {
-	if !TheGame.Settings.SafeCollect {
+	if !b.settings.SafeCollect {
 		return false, 0
 	}
 	if !b.script.SafeCollect() {
@@ -694,13 +422,14 @@ func (b *Baize) DoingSafeCollect() (bool, int) {
 	}
 	var lowest int = 99
 	for _, f := range fs {
-		if f.Empty() {
+		var cards []*Card = NonJokers(f.cards) // a joker doesn't say how far a foundation has got
+		if len(cards) == 0 {
 			// it's okay to collect aces and twos to start with
 			return true, 2
 		}
-		var card *Card = f.Peek()
-		if card.Ordinal() < lowest {
-			lowest = card.Ordinal()
+		var card *Card = cards[len(cards)-1]
+		if card.rank() < lowest {
+			lowest = card.rank()
 		}
 	}
 	return true, lowest + 1
@@ -723,15 +452,13 @@ func (b *Baize) collectFromPile(pile *Pile) int {
 				break // done with this foundation, try another
 			}
 			if ok, safeOrd := b.DoingSafeCollect(); ok {
-				if card.Ordinal() > safeOrd {
+				if card.rank() > safeOrd {
 					// can't toast here, collect all will create a lot of toasts
-					// TheGame.UI.Toast("Glass", fmt.Sprintf("Unsafe to collect %s", card.String()))
+					// b.toast("Glass", fmt.Sprintf("Unsafe to collect %s", card.String()))
 					break // done with this foundation, try another
 				}
 			}
 			MoveCard(pile, fp)
-			b.AfterUserMove() // does an undoPush()
-			b.AfterAfterUserMove()
 			cardsMoved += 1
 		}
 	}
@@ -744,7 +471,12 @@ func (b *Baize) collectFromPile(pile *Pile) int {
 // cards in them does not signify a complete game.
 // It's called Collect2 because it's the third or fourth rewrite of a
 // basic and seemingly simple function.
+// All the cards collected are one move, so one undo puts them all back.
 func (b *Baize) Collect2() {
+	if !b.script.CanCollect() {
+		return
+	}
+	b.BeginMove()
 	for {
 		var cardsMoved int = b.collectFromPile(b.script.Waste())
 		for _, pile := range b.script.Cells() {
@@ -760,96 +492,17 @@ func (b *Baize) Collect2() {
 			break
 		}
 	}
-	// if ThePreferences.SafeCollect && b.script.SafeCollect() && b.fmoves > 0 {
-	// 	TheGame.UI.Toast("Glass", "Not safe to collect card(s)")
-	// }
-}
-
-func (b *Baize) MaxSlotX() int {
-	var maxX int
-	for _, p := range b.piles {
-		if p.Slot().X > maxX {
-			maxX = p.Slot().X
-		}
+	if b.CommitMove() && b.moving == 0 {
+		b.AfterAfterUserMove() // the script's AfterMove may have made more cards collectable
 	}
-	return maxX
-}
-
-// ScaleCards calculates new width/height of cards and margins
-// returns true if changes were made
-func (b *Baize) ScaleCards() bool {
-
-	// const (
-	// 	DefaultRatio = 1.444
-	// 	BridgeRatio  = 1.561
-	// 	PokerRatio   = 1.39
-	// 	OpsoleRatio  = 1.5556 // 3.5/2.25
-	// )
-
-	var OldWidth = CardWidth
-	var OldHeight = CardHeight
-
-	var maxX int = b.MaxSlotX()
-
-	/*
-		71 x 96 = 1:1.352 (Microsoft retro)
-		140 x 190 = 1:1.357 (kenney, large)
-		64 x 89 = 1:1.390 (official poker size)
-		90 x 130 = 1:1.444 (nice looking scalable)
-		89 x 137 = 1:1.539 (measured real card)
-		57 x 89 = 1:1.561 (official bridge size)
-	*/
-
-	// Card padding is 10% of card height/width
-
-	// if ThePreferences.FixedCards {
-	// 	CardWidth = ThePreferences.FixedCardWidth
-	// 	PilePaddingX = CardWidth / 10
-	// 	CardHeight = ThePreferences.FixedCardHeight
-	// 	PilePaddingY = CardHeight / 10
-	// 	cardsWidth := PilePaddingX + CardWidth*(maxX+2)
-	// 	LeftMargin = (b.WindowWidth - cardsWidth) / 2
-	// } else {
-
-	// "add" two extra piles and a LeftMargin to make a half-card-width border
-
-	var slotWidth, slotHeight float64
-	slotWidth = float64(b.WindowWidth) / float64(maxX+2)
-	slotHeight = slotWidth * TheGame.Settings.CardRatio
-
-	PilePaddingX = int(slotWidth / 10)
-	CardWidth = int(slotWidth) - PilePaddingX
-	PilePaddingY = int(slotHeight / 10)
-	CardHeight = int(slotHeight) - PilePaddingY
-
-	TopMargin = ui.ToolbarHeight + CardHeight/3
-	LeftMargin = (CardWidth / 2) + PilePaddingX
-
-	// CardDiagonal = math.Sqrt(math.Pow(float64(CardWidth), 2) + math.Pow(float64(CardHeight), 2))
-	// }
-	CardCornerRadius = float64(CardWidth) / 10.0 // same as lsol
-
-	// if DebugMode {
-	// 	if CardWidth != OldWidth || CardHeight != OldHeight {
-	// 		log.Println("ScaleCards did something")
-	// 	} else {
-	// 		log.Println("ScaleCards did nothing")
-	// 	}
+	// if ThePreferences.SafeCollect && b.script.SafeCollect() && b.fmoves > 0 {
+	// 	b.toast("Glass", "Not safe to collect card(s)")
 	// }
-	return CardWidth != OldWidth || CardHeight != OldHeight
 }
 
+// PercentComplete returns how near the game is to being won, as the script sees it
 func (b *Baize) PercentComplete() int {
-	var pairs, unsorted, percent int
-	for _, p := range b.piles {
-		if p.Len() > 1 {
-			pairs += p.Len() - 1
-		}
-		unsorted += p.vtable.UnsortedPairs()
-	}
-	// TheGame.UI.SetMiddle(fmt.Sprintf("%d/%d", pairs-unsorted, pairs))
-	percent = (int)(100.0 - util.MapValue(float64(unsorted), 0, float64(pairs), 0.0, 100.0))
-	return percent
+	return b.script.PercentComplete()
 }
 
 func (b *Baize) Recycles() int {
@@ -858,35 +511,7 @@ func (b *Baize) Recycles() int {
 
 func (b *Baize) SetRecycles(recycles int) {
 	b.recycles = recycles
-	b.setFlag(dirtyPileBackgrounds) // recreate Stock placeholder
-}
-
-func (b *Baize) UpdateToolbar() {
-	TheGame.UI.EnableWidget("toolbarUndo", len(b.undoStack) > 1)
-	TheGame.UI.EnableWidget("toolbarCollect", b.fmoves > 0)
-}
-
-func (b *Baize) UpdateStatusbar() {
-	if b.script.Stock().Hidden() {
-		TheGame.UI.SetStock(-1)
-	} else {
-		TheGame.UI.SetStock(b.script.Stock().Len())
-	}
-	if b.script.Waste() == nil {
-		TheGame.UI.SetWaste(-1) // previous variant may have had a waste, and this one does not
-	} else {
-		TheGame.UI.SetWaste(b.script.Waste().Len())
-	}
-	// if DebugMode {
-	// 	TheGame.UI.SetMiddle(fmt.Sprintf("MOVES: %d,%d", b.moves, b.fmoves))
-	// }
-	TheGame.UI.SetMiddle(fmt.Sprintf("MOVES: %d", len(b.undoStack)-1))
-	TheGame.UI.SetPercent(b.PercentComplete())
-}
-
-func (b *Baize) UpdateDrawers() {
-	TheGame.UI.EnableWidget("restartDeal", len(b.undoStack) > 1)
-	TheGame.UI.EnableWidget("gotoBookmark", b.bookmark > 0)
+	b.pilesChanged() // recreate Stock placeholder
 }
 
 func (b *Baize) Conformant() bool {
@@ -901,137 +526,3 @@ func (b *Baize) Conformant() bool {
 func (b *Baize) Complete() bool {
 	return b.script.Complete()
 }
-
-// Layout implements ebiten.Game's Layout.
-func (b *Baize) Layout(outsideWidth, outsideHeight int) (int, int) {
-
-	if outsideWidth == 0 || outsideHeight == 0 {
-		log.Println("Baize.Layout called with zero dimension")
-		return outsideWidth, outsideHeight
-	}
-
-	if DebugMode && (outsideWidth != b.WindowWidth || outsideHeight != b.WindowHeight) {
-		log.Println("Window resize to", outsideWidth, outsideHeight)
-	}
-
-	if outsideWidth != b.WindowWidth {
-		b.setFlag(dirtyWindowSize | dirtyCardSizes | dirtyPileBackgrounds | dirtyPilePositions | dirtyCardPositions)
-		b.WindowWidth = outsideWidth
-	}
-	if outsideHeight != b.WindowHeight {
-		b.setFlag(dirtyWindowSize | dirtyCardPositions)
-		b.WindowHeight = outsideHeight
-	}
-
-	if b.dirtyFlags != 0 {
-		if b.flagSet(dirtyCardSizes) {
-			if b.ScaleCards() {
-				if DebugMode {
-					log.Printf("ScaleCards %dx%d", CardWidth, CardHeight)
-				}
-				b.setFlag(dirtyCardImages | dirtyPilePositions | dirtyPileBackgrounds)
-			}
-			// b.clearFlag(dirtyCardSizes)
-		}
-		if b.flagSet(dirtyCardImages) {
-			CreateCardImages()
-			// b.clearFlag(dirtyCardImages)
-		}
-		if b.flagSet(dirtyPilePositions) {
-			for _, p := range b.piles {
-				p.SetBaizePos(image.Point{
-					X: LeftMargin + (p.Slot().X * (CardWidth + PilePaddingX)),
-					Y: TopMargin + (p.Slot().Y * (CardHeight + PilePaddingY)),
-				})
-			}
-			// b.clearFlag(dirtyPilePositions)
-		}
-		if b.flagSet(dirtyPileBackgrounds) {
-			if !(CardWidth == 0 || CardHeight == 0) {
-				for _, p := range b.piles {
-					if !p.Hidden() {
-						p.img = p.vtable.Placeholder()
-					}
-				}
-			}
-			// b.clearFlag(dirtyPileBackgrounds)
-		}
-		if b.flagSet(dirtyWindowSize) {
-			TheGame.UI.Layout(outsideWidth, outsideHeight)
-			// b.clearFlag(dirtyWindowSize)
-		}
-		if b.flagSet(dirtyCardPositions) {
-			for _, p := range b.piles {
-				p.Scrunch()
-			}
-			// b.clearFlag(dirtyCardPositions)
-		}
-		b.dirtyFlags = 0
-	}
-
-	return outsideWidth, outsideHeight
-}
-
-// Update the baize state (transitions, user input)
-func (b *Baize) Update() error {
-
-	if b.stroke == nil {
-		input.StartStroke(b) // this will set b.stroke when "start" received
-	} else {
-		b.stroke.Update()
-		if b.stroke.IsReleased() || b.stroke.IsCancelled() {
-			b.stroke = nil
-		}
-	}
-
-	// {
-	// 	var x, y int = ebiten.CursorPosition()
-	// 	// x, y will be 0,0 on mobiles, and before main loop starts
-	// 	if !(x == 0 && y == 0) {
-	// 		b.hotCard = b.FindLowestCardAt(image.Point{x, y})
-	// 	}
-	// }
-
-	for _, p := range b.piles {
-		p.Update()
-	}
-
-	for k := ebiten.Key(0); k <= ebiten.KeyMax; k++ {
-		if inpututil.IsKeyJustReleased(k) {
-			Execute(k)
-		}
-	}
-
-	return nil
-}
-
-// Draw renders the baize into the screen
-func (b *Baize) Draw(screen *ebiten.Image) {
-
-	screen.Fill(ExtendedColors[TheGame.Settings.BaizeColor])
-
-	for _, p := range b.piles {
-		p.Draw(screen)
-	}
-	for _, p := range b.piles {
-		p.DrawStaticCards(screen)
-	}
-	for _, p := range b.piles {
-		p.DrawAnimatingCards(screen)
-	}
-	for _, p := range b.piles {
-		p.DrawDraggingCards(screen)
-	}
-	// if b.hotCard != nil {
-	// 	b.hotCard.Draw(screen)
-	// }
-
-	// if DebugMode {
-	// var ms runtime.MemStats
-	// runtime.ReadMemStats(&ms)
-	// ebitenutil.DebugPrint(screen, fmt.Sprintf("FPS %v, Alloc %v, NumGC %v", ebiten.CurrentTPS(), ms.Alloc, ms.NumGC))
-	// ebitenutil.DebugPrint(screen, fmt.Sprintf("%v %v", b.bookmark, len(b.undoStack)))
-	// bounds := screen.Bounds()
-	// ebitenutil.DebugPrint(screen, bounds.String())
-	// }
-}
@@ -0,0 +1,184 @@
+package sol
+
+import (
+	"testing"
+)
+
+// checkCards makes sure that every card is on the baize exactly once, and knows where it is
+func checkCards(t *testing.T, b *Baize) {
+	var seen = make(map[*Card]bool)
+	var count int
+	for _, p := range b.piles {
+		for _, c := range p.cards {
+			if c.Owner() != p {
+				t.Errorf("%s: %s is in %s but thinks it is in %v", b.variant, c, p.category, c.Owner())
+			}
+			if seen[c] {
+				t.Errorf("%s: %s is on the baize twice", b.variant, c)
+			}
+			seen[c] = true
+			count++
+		}
+	}
+	if count != b.cardCount {
+		t.Errorf("%s: %d cards on the baize, expected %d", b.variant, count, b.cardCount)
+	}
+}
+
+func TestVariants(t *testing.T) {
+	for v := range Variants {
+		b := NewBaize(v, nil)
+		if b == nil {
+			t.Fatalf("%s: NewBaize returned nil", v)
+		}
+		b.StartFreshGame()
+		checkCards(t, b)
+		b.NewDeal()
+		checkCards(t, b)
+		if b.MovesMade() != 0 {
+			t.Errorf("%s: %d moves made in a new deal", v, b.MovesMade())
+		}
+
+		// play the best tap move a few times, as if the player were tapping cards
+		for i := 0; i < 20; i++ {
+			var tail []*Card
+			b.ForeachCard(func(c *Card) {
+				if tail == nil && c.tapDestination != nil {
+					tail = c.Owner().MakeTail(c)
+				}
+			})
+			if tail == nil {
+				break
+			}
+			b.TailTapped(tail)
+			checkCards(t, b)
+		}
+
+		for b.MovesMade() > 0 && !b.Complete() {
+			b.Undo()
+			checkCards(t, b)
+		}
+	}
+}
+
+func TestBaizesAreIndependent(t *testing.T) {
+	b1 := NewBaize("Klondike", nil)
+	b2 := NewBaize("Klondike", nil)
+	b1.StartFreshGame()
+	b2.StartFreshGame()
+	if b1.script == b2.script {
+		t.Fatal("two baizes share a script")
+	}
+	if b1.script.Stock() == b2.script.Stock() {
+		t.Fatal("two baizes share a stock")
+	}
+	checkCards(t, b1)
+	checkCards(t, b2)
+}
+
+func TestSeedsAreReproducible(t *testing.T) {
+	for v := range Variants {
+		b1 := NewBaize(v, nil)
+		b2 := NewBaize(v, nil)
+		b1.StartFreshGame()
+		b2.StartFreshGame()
+		b1.NewDealFromSeed(12345)
+		b2.NewDealFromSeed(12345)
+		s1, s2 := b1.UndoPeek(), b2.UndoPeek()
+		if s1.Seed != 12345 {
+			t.Errorf("%s: seed %d not saved", v, s1.Seed)
+		}
+		for i := range s1.Piles {
+			if len(s1.Piles[i].Cards) != len(s2.Piles[i].Cards) {
+				t.Fatalf("%s: same seed gave different deals", v)
+			}
+			for j := range s1.Piles[i].Cards {
+				if s1.Piles[i].Cards[j] != s2.Piles[i].Cards[j] {
+					t.Fatalf("%s: same seed gave different deals", v)
+				}
+			}
+		}
+	}
+}
+
+func TestKnownDeal(t *testing.T) {
+	// if this changes, every deal number anyone has ever shared has changed
+	b := NewBaize("Klondike", nil)
+	b.StartFreshGame()
+	b.NewDealFromSeed(1)
+	var want = []string{"0 Diamond 6", "0 Diamond 8", "0 Diamond 7", "0 Spade 13", "0 Club 4", "0 Spade 7", "0 Spade 6"}
+	for i, p := range b.script.Tableaux() {
+		if got := p.Peek().String(); got != want[i] {
+			t.Errorf("Klondike deal #1 tableau %d: got %s, want %s", i, got, want[i])
+		}
+	}
+}
+
+func TestCompoundMove(t *testing.T) {
+	settings := DefaultSettings()
+	settings.AutoCollect = false
+	b := NewBaize("Klondike", settings)
+	b.StartFreshGame()
+	b.NewDealFromSeed(7)
+	deal := b.Hash()
+
+	b.BeginMove()
+	for i := 0; i < 3; i++ {
+		MoveCard(b.script.Stock(), b.script.Waste())
+	}
+	b.BeginMove()
+	MoveCard(b.script.Stock(), b.script.Waste())
+	if !b.CommitMove() || b.MovesMade() != 0 {
+		t.Error("inner move was recorded on its own")
+	}
+	if !b.CommitMove() || b.MovesMade() != 1 {
+		t.Errorf("%d moves made, want 1", b.MovesMade())
+	}
+	b.Undo()
+	checkCards(t, b)
+	if b.Hash() != deal {
+		t.Error("undo didn't take back all of the move")
+	}
+
+	b.BeginMove()
+	if b.CommitMove() || b.MovesMade() != 0 {
+		t.Error("a move that changed nothing was recorded")
+	}
+}
+
+func TestCollectIsOneMove(t *testing.T) {
+	settings := DefaultSettings()
+	settings.AutoCollect = false
+	settings.SafeCollect = false
+	for seed := uint64(1); seed < 50; seed++ {
+		b := NewBaize("Klondike", settings)
+		b.StartFreshGame()
+		b.NewDealFromSeed(seed)
+		playRandomly(b, 100, seed)
+		if b.FoundationMoves() == 0 {
+			continue
+		}
+		var collected int
+		for _, f := range b.script.Foundations() {
+			collected -= f.Len()
+		}
+		moves, key := b.MovesMade(), b.Hash()
+		b.Collect2()
+		for _, f := range b.script.Foundations() {
+			collected += f.Len()
+		}
+		if collected < 2 || b.Complete() {
+			continue
+		}
+		if b.MovesMade() != moves+1 {
+			t.Fatalf("collecting %d cards made %d moves, want 1", collected, b.MovesMade()-moves)
+		}
+		b.Undo()
+		checkCards(t, b)
+		if b.Hash() != key {
+			t.Error("one undo didn't put back every card collected")
+		}
+		return
+	}
+	t.Fatal("no deal with cards to collect")
+}
@@ -1,9 +0,0 @@
-package sol
-
-import (
-	"syscall/js"
-)
-
-func (b *Baize) Wikipedia() {
-	js.Global().Get("window").Call("open", b.script.Wikipedia())
-}
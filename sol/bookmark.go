@@ -0,0 +1,114 @@
+package sol
+
+import "fmt"
+
+// Bookmark is a position in a game that the player wants to be able to go back to
+type Bookmark struct {
+	Name  string
+	Moves int // moves made when the position was bookmarked
+}
+
+// bookmarkName makes up a name for a bookmark the player didn't name
+func bookmarkName(moves int) string {
+	if moves == 0 {
+		return "The deal"
+	}
+	return fmt.Sprintf("Move %d", moves)
+}
+
+// Bookmarked returns true if the player has bookmarked a position
+func (b *Baize) Bookmarked() bool {
+	return len(b.bookmarks) > 0
+}
+
+// Bookmarks returns the bookmarks in this game, oldest first
+func (b *Baize) Bookmarks() []Bookmark {
+	return append([]Bookmark(nil), b.bookmarks...)
+}
+
+// AddBookmark bookmarks the current position.
+// If name is empty, the bookmark is named after the number of moves made;
+// a bookmark with the same name as an old one replaces it.
+func (b *Baize) AddBookmark(name string) {
+	if b.Complete() {
+		b.toastError("Cannot bookmark a completed game") // otherwise the stats can be cooked
+		return
+	}
+	if name == "" {
+		name = bookmarkName(len(b.steps))
+	}
+	b.deleteBookmark(name)
+	b.bookmarks = append(b.bookmarks, Bookmark{Name: name, Moves: len(b.steps)})
+	b.toastInfo(fmt.Sprintf("Position bookmarked as '%s'", name))
+}
+
+func (b *Baize) deleteBookmark(name string) {
+	var bookmarks []Bookmark
+	for _, bm := range b.bookmarks {
+		if bm.Name != name {
+			bookmarks = append(bookmarks, bm)
+		}
+	}
+	b.bookmarks = bookmarks
+}
+
+// removeBookmarksAfter removes the bookmarks more than n moves into the game, and returns them
+func (b *Baize) removeBookmarksAfter(n int) []Bookmark {
+	var bookmarks, removed []Bookmark
+	for _, bm := range b.bookmarks {
+		if bm.Moves <= n {
+			bookmarks = append(bookmarks, bm)
+		} else {
+			removed = append(removed, bm)
+		}
+	}
+	b.bookmarks = bookmarks
+	return removed
+}
+
+// BookmarkPosition returns a copy of the position a bookmark was made at.
+// The copy must not be changed.
+func (b *Baize) BookmarkPosition(i int) *SavableBaize {
+	if i < 0 || i >= len(b.bookmarks) {
+		return nil
+	}
+	moves := b.bookmarks[i].Moves
+	if moves <= len(b.steps) {
+		return b.positionAt(moves)
+	}
+	// the bookmark is in moves that have been undone
+	sb := b.position
+	for j := len(b.redo) - 1; j >= len(b.redo)-(moves-len(b.steps)); j-- {
+		sb, _ = b.redo[j].applyTo(sb)
+	}
+	return sb
+}
+
+// GotoBookmark goes back (or, if moves have been undone since, forward) to a bookmarked position
+func (b *Baize) GotoBookmark(i int) {
+	if i < 0 || i >= len(b.bookmarks) {
+		b.toastError("No bookmark")
+		return
+	}
+	if b.Complete() {
+		b.toastError("Cannot undo a completed game") // otherwise the stats can be cooked
+		return
+	}
+	moves := b.bookmarks[i].Moves
+	for len(b.steps) < moves {
+		b.redoOne()
+	}
+	b.takeBack(moves)
+	b.playSound("TakeOutPackage")
+	b.FindDestinations()
+}
+
+// SavePosition bookmarks the current Baize state, naming the bookmark after the number of moves made
+func (b *Baize) SavePosition() {
+	b.AddBookmark("")
+}
+
+// LoadPosition goes to the most recently made bookmark
+func (b *Baize) LoadPosition() {
+	b.GotoBookmark(len(b.bookmarks) - 1)
+}
@@ -0,0 +1,79 @@
+package sol
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestBookmarks(t *testing.T) {
+	settings := DefaultSettings()
+	settings.AutoCollect = false
+	b := NewBaize("Klondike", settings)
+	b.StartFreshGame()
+	b.NewDealFromSeed(4)
+	keys := playRandomly(b, 30, 4)
+	if len(keys) < 21 {
+		t.Fatal("random player gave up too soon")
+	}
+	b.RewindTo(5)
+	b.AddBookmark("cells full")
+	b.RewindTo(0)
+	b.AddBookmark("")
+	for i := 0; i < 15; i++ {
+		b.Redo()
+	}
+	b.AddBookmark("before emptying column 3")
+	b.AddBookmark("cells full") // replaces the old one
+	b.RewindTo(10)
+
+	want := []Bookmark{{"The deal", 0}, {"before emptying column 3", 15}, {"cells full", 15}}
+	if got := b.Bookmarks(); len(got) != len(want) {
+		t.Fatalf("got bookmarks %v, want %v", got, want)
+	}
+	for i, bm := range b.Bookmarks() {
+		if bm != want[i] {
+			t.Errorf("bookmark %d is %v, want %v", i, bm, want[i])
+		}
+		other := NewBaize("Klondike", settings)
+		other.StartFreshGame()
+		other.updateFromSavable(b.BookmarkPosition(i))
+		if other.Hash() != keys[bm.Moves] {
+			t.Errorf("bookmark '%s' has the wrong position", bm.Name)
+		}
+	}
+
+	// bookmarks are saved with the game
+	bytes, err := json.Marshal(b.MoveLog())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ml, err := unmarshalMoveLog(bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.StartFreshGame()
+	b.SetMoveLog(ml)
+	if len(b.Bookmarks()) != len(want) {
+		t.Fatalf("%d bookmarks loaded, want %d", len(b.Bookmarks()), len(want))
+	}
+
+	// going to a bookmark ahead of the position redoes moves
+	b.GotoBookmark(1)
+	if b.MovesMade() != 15 || b.Hash() != keys[15] {
+		t.Errorf("went to move %d, want 15", b.MovesMade())
+	}
+	b.LoadPosition()
+	if b.MovesMade() != 15 {
+		t.Errorf("went to move %d, want 15", b.MovesMade())
+	}
+	b.GotoBookmark(0)
+	if b.MovesMade() != 0 || b.Hash() != keys[0] {
+		t.Errorf("went to move %d, want the deal", b.MovesMade())
+	}
+
+	// a different move leaves the bookmarks after the deal behind with the moves that were undone
+	makeDifferentMove(b, keys[1])
+	if len(b.Bookmarks()) != 1 {
+		t.Errorf("got bookmarks %v after a new move, want just the deal", b.Bookmarks())
+	}
+}
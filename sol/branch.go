@@ -0,0 +1,153 @@
+package sol
+
+//lint:file-ignore ST1006 Receiver name will be anything I like, thank you
+
+import "fmt"
+
+// Branch is a line of play that was left behind when a different move was made after an undo.
+// Together with the current line, the branches make a tree of every line played from the deal.
+type Branch struct {
+	steps     []*Step    // every move from the deal to the end of the line
+	bookmarks []Bookmark // bookmarks made after the line left the current one
+}
+
+// SavedBranch is a branch in a move log, kept as the moves made after it leaves an earlier line
+type SavedBranch struct {
+	From      int        // the line it leaves: 0 is the current line, i+1 is Branches[i]
+	Fork      int        // moves along that line before the branch leaves it
+	Steps     []*Step    `json:",omitempty"`
+	Bookmarks []Bookmark `json:",omitempty"`
+}
+
+// lineOf returns every move in a line, from the moves made and the moves undone
+func lineOf(steps, redo []*Step) []*Step {
+	line := append([]*Step(nil), steps...)
+	for i := len(redo) - 1; i >= 0; i-- {
+		line = append(line, redo[i])
+	}
+	return line
+}
+
+// sharedMoves returns how many moves two lines have in common before they part
+func sharedMoves(a, b []*Step) int {
+	var n int
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
+
+// samePosition returns true if two positions have the same cards, face up or down, in the same places
+func samePosition(a, b *SavableBaize) bool {
+	if a.Recycles != b.Recycles || len(a.Piles) != len(b.Piles) {
+		return false
+	}
+	for i := range a.Piles {
+		if a.Piles[i].Label != b.Piles[i].Label || len(a.Piles[i].Cards) != len(b.Piles[i].Cards) {
+			return false
+		}
+		for j, cid := range a.Piles[i].Cards {
+			if cid != b.Piles[i].Cards[j] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// branchOff keeps the current line, as far as the moves that were undone go, as a branch
+func (b *Baize) branchOff() {
+	b.branches = append(b.branches, Branch{
+		steps:     lineOf(b.steps, b.redo),
+		bookmarks: b.removeBookmarksAfter(len(b.steps)),
+	})
+	b.redo = nil
+}
+
+// Branches returns how many other lines of play there are in this game
+func (b *Baize) Branches() int {
+	return len(b.branches)
+}
+
+// BranchMoves returns how many moves a branch has in common with the current line, and how many it has altogether
+func (b *Baize) BranchMoves(i int) (fork, moves int) {
+	if i < 0 || i >= len(b.branches) {
+		return 0, 0
+	}
+	steps := b.branches[i].steps
+	return sharedMoves(lineOf(b.steps, b.redo), steps), len(steps)
+}
+
+// BranchPosition returns a copy of the position at the end of a branch.
+// The copy must not be changed.
+func (b *Baize) BranchPosition(i int) *SavableBaize {
+	if i < 0 || i >= len(b.branches) {
+		return nil
+	}
+	steps := b.branches[i].steps
+	n := sharedMoves(b.steps, steps)
+	sb := b.positionAt(n)
+	for _, st := range steps[n:] {
+		sb, _ = st.applyTo(sb)
+	}
+	return sb
+}
+
+// GotoBranch goes to the end of another line of play, leaving the current line behind as a branch
+func (b *Baize) GotoBranch(i int) {
+	if i < 0 || i >= len(b.branches) {
+		b.toastError("No such line of play")
+		return
+	}
+	if b.Complete() {
+		b.toastError("Cannot change a completed game") // otherwise the stats can be cooked
+		return
+	}
+	line, br := lineOf(b.steps, b.redo), b.branches[i]
+	fork := sharedMoves(line, br.steps)
+	b.branches[i] = Branch{steps: line, bookmarks: b.removeBookmarksAfter(fork)}
+	b.rewindTo(fork)
+	b.redo = lineOf(nil, br.steps[len(b.steps):]) // the branch from here, the next move last
+	for len(b.redo) > 0 {
+		b.redoOne()
+	}
+	for _, bm := range br.bookmarks {
+		b.deleteBookmark(bm.Name)
+		b.bookmarks = append(b.bookmarks, bm)
+	}
+	b.toastInfo(fmt.Sprintf("Went to a line of play that leaves this one after %d moves", fork))
+	b.playSound("Slide")
+	b.FindDestinations()
+}
+
+// savedBranches returns the branches as they are kept in a move log
+func (b *Baize) savedBranches() []SavedBranch {
+	var saved []SavedBranch
+	lines := [][]*Step{lineOf(b.steps, b.redo)}
+	for _, br := range b.branches {
+		var from, fork int
+		for j, line := range lines {
+			if n := sharedMoves(line, br.steps); n > fork {
+				from, fork = j, n
+			}
+		}
+		saved = append(saved, SavedBranch{From: from, Fork: fork, Steps: br.steps[fork:], Bookmarks: br.bookmarks})
+		lines = append(lines, br.steps)
+	}
+	return saved
+}
+
+// branches rebuilds the branches in a move log, or returns false if they don't fit together
+func (ml *MoveLog) branches() ([]Branch, bool) {
+	var branches []Branch
+	lines := [][]*Step{lineOf(ml.Steps, ml.Redo)}
+	for i, sbr := range ml.Branches {
+		if sbr.From < 0 || sbr.From > i || sbr.Fork < 0 || sbr.Fork > len(lines[sbr.From]) {
+			return nil, false
+		}
+		steps := append(append([]*Step(nil), lines[sbr.From][:sbr.Fork]...), sbr.Steps...)
+		branches = append(branches, Branch{steps: steps, bookmarks: sbr.Bookmarks})
+		lines = append(lines, steps)
+	}
+	return branches, true
+}
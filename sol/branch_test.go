@@ -0,0 +1,88 @@
+package sol
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestBranches(t *testing.T) {
+	settings := DefaultSettings()
+	settings.AutoCollect = false
+	b := NewBaize("Klondike", settings)
+	b.StartFreshGame()
+	b.NewDealFromSeed(5)
+	first := playRandomly(b, 30, 5)
+	if len(first) < 21 {
+		t.Fatal("random player gave up too soon")
+	}
+	b.RewindTo(15)
+	b.AddBookmark("first line")
+
+	// making the move that was undone again is a redo, not a new line
+	b.RewindTo(10)
+	other := NewBaize("Klondike", settings)
+	other.StartFreshGame()
+	other.updateFromSavable(b.UndoPeek())
+	other.restartLog()
+	for _, m := range other.LegalMoves() {
+		if other.ApplyMove(m) && other.Hash() == first[11] {
+			b.ApplyMove(m)
+			break
+		}
+		other.rewindTo(0)
+	}
+	if b.MovesMade() != 11 || !b.CanRedo() || b.Branches() != 0 {
+		t.Fatal("making an undone move again started a new line")
+	}
+
+	// a different move starts a new line, and keeps the old one as a branch
+	if !makeDifferentMove(b, first[12]) {
+		t.Fatal("no different move to make")
+	}
+	second := append(append([]uint64(nil), first[:12]...), playRandomly(b, 10, 6)...)
+	if b.Branches() != 1 || b.CanRedo() {
+		t.Fatalf("%d branches after a different move, want 1", b.Branches())
+	}
+	if fork, moves := b.BranchMoves(0); fork != 11 || moves != len(first)-1 {
+		t.Errorf("branch leaves after %d moves and has %d, want 11 and %d", fork, moves, len(first)-1)
+	}
+	if b.Bookmarked() {
+		t.Error("bookmark on the first line is still on the second")
+	}
+
+	// going to the branch swaps the lines over, and brings back the bookmark
+	b.GotoBranch(0)
+	if b.MovesMade() != len(first)-1 || b.Hash() != first[len(first)-1] {
+		t.Fatalf("went to move %d, want the end of the first line", b.MovesMade())
+	}
+	if len(b.Bookmarks()) != 1 || b.Bookmarks()[0].Name != "first line" {
+		t.Errorf("got bookmarks %v, want the one on the first line", b.Bookmarks())
+	}
+	other.updateFromSavable(b.BranchPosition(0))
+	if other.Hash() != second[len(second)-1] {
+		t.Error("branch position is not the end of the second line")
+	}
+
+	// branches are saved with the game
+	b.RewindTo(5)
+	bytes, err := json.Marshal(b.MoveLog())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ml, err := unmarshalMoveLog(bytes)
+	if err != nil || !other.isMoveLogOk(ml) {
+		t.Fatalf("saved move log is not ok: %v", err)
+	}
+	other.SetMoveLog(ml)
+	if other.Branches() != 1 || other.MovesMade() != 5 {
+		t.Fatalf("loaded game has %d branches at move %d, want 1 at move 5", other.Branches(), other.MovesMade())
+	}
+	other.GotoBranch(0)
+	if other.MovesMade() != len(second)-1 || other.Hash() != second[len(second)-1] {
+		t.Errorf("went to move %d, want the end of the second line", other.MovesMade())
+	}
+	other.GotoBranch(0)
+	if other.Hash() != first[len(first)-1] {
+		t.Error("didn't go back to the end of the first line")
+	}
+}
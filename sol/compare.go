@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 
+	"oddstream.games/gosol/cardid"
 	"oddstream.games/gosol/util"
 )
 
@@ -17,9 +18,21 @@ type CardPairs []CardPair
 
 type CardPairCompareFunc func(CardPair) (bool, error)
 
+// TailConformant returns true if each card in the tail goes on the one before it.
+// A joker stands for one card, which must go on the card before it and take the card after it.
 func TailConformant(tail []*Card, fn CardPairCompareFunc) (bool, error) {
-	for _, pair := range NewCardPairs(tail) {
-		if ok, err := fn(pair); !ok {
+	if !anyJokers(tail) {
+		for _, pair := range NewCardPairs(tail) {
+			if ok, err := fn(pair); !ok {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+	var could []*Card = couldBe(tail[0])
+	for _, c := range tail[1:] {
+		var err error
+		if could, err = follow(could, c, fn); len(could) == 0 {
 			return false, err
 		}
 	}
@@ -35,18 +48,78 @@ func UnsortedPairs(pile *Pile, fn CardPairCompareFunc) int {
 		return 0
 	}
 	var unsorted int
-	for _, pair := range NewCardPairs(pile.cards) {
-		if pair.c1.Prone() || pair.c2.Prone() {
-			unsorted++
-		} else {
-			if ok, _ := fn(pair); !ok {
+	if !anyJokers(pile.cards) {
+		for _, pair := range NewCardPairs(pile.cards) {
+			if pair.c1.Prone() || pair.c2.Prone() {
 				unsorted++
+			} else {
+				if ok, _ := fn(pair); !ok {
+					unsorted++
+				}
 			}
 		}
+		return unsorted
+	}
+	var prev *Card = pile.cards[0]
+	var could []*Card = couldBe(prev)
+	for _, c := range pile.cards[1:] {
+		var next []*Card
+		if !prev.Prone() && !c.Prone() {
+			next, _ = follow(could, c, fn)
+		}
+		if len(next) == 0 {
+			unsorted++
+			next = couldBe(c)
+		}
+		prev, could = c, next
 	}
 	return unsorted
 }
 
+func anyJokers(cards []*Card) bool {
+	for _, c := range cards {
+		if c.Joker() {
+			return true
+		}
+	}
+	return false
+}
+
+// couldBe returns the cards that a card could be: itself, or for a joker, any card in the pack
+func couldBe(c *Card) []*Card {
+	if !c.Joker() {
+		return []*Card{c}
+	}
+	var ranks []int = standardRanks
+	if c.owningPile != nil {
+		ranks = c.owningPile.baize.script.Ranks()
+	}
+	var cards []*Card
+	for suit := cardid.CLUB; suit <= cardid.SPADE; suit++ {
+		for _, ord := range ranks {
+			// owned by the joker's pile, so it ranks the way the pack does
+			cards = append(cards, &Card{id: cardid.NewCardID(0, suit, ord), owningPile: c.owningPile})
+		}
+	}
+	return cards
+}
+
+// follow returns the cards that c could be, given the cards that the card before it could be
+func follow(before []*Card, c *Card, fn CardPairCompareFunc) ([]*Card, error) {
+	var after []*Card
+	var err error
+	for _, x := range couldBe(c) {
+		for _, b := range before {
+			var ok bool
+			if ok, err = fn(CardPair{b, x}); ok {
+				after = append(after, x)
+				break
+			}
+		}
+	}
+	return after, err
+}
+
 func NewCardPairs(cards []*Card) CardPairs {
 	if len(cards) < 2 {
 		return []CardPair{} // always return a list, not nil
@@ -72,6 +145,9 @@ func Compare_Empty(p *Pile, c *Card) (bool, error) {
 		if p.Label() == "x" || p.Label() == "X" {
 			return false, errors.New("Cannot move cards to that empty pile")
 		}
+		if c.Joker() {
+			return true, nil
+		}
 		ord := util.OrdinalToShortString(c.Ordinal())
 		if ord != p.Label() {
 			return false, fmt.Errorf("Can only accept %s, not %s", util.ShortOrdinalToLongOrdinal(p.Label()), util.ShortOrdinalToLongOrdinal(ord))
@@ -80,55 +156,81 @@ func Compare_Empty(p *Pile, c *Card) (bool, error) {
 	return true, nil
 }
 
-// little library of simple compares
+// little library of simple compares;
+// a joker is wild, so it can go on any card, and any card can go on it
+// (though in a tail, TailConformant makes it stand for one card that fits both its neighbours);
+// sequences follow the ranks of the pack, so in a stripped pack without Twos to Sixes, a Seven follows an Ace
+
+// wild returns true if either card is a joker
+func (cp CardPair) wild() bool {
+	return cp.c1.Joker() || cp.c2.Joker()
+}
 
 func (cp CardPair) Compare_Up() (bool, error) {
-	if cp.c1.Ordinal() == cp.c2.Ordinal()-1 {
+	if cp.wild() {
+		return true, nil
+	}
+	if cp.c1.rank() == cp.c2.rank()-1 {
 		return true, nil
 	}
 	return false, errors.New("Cards must be in ascending sequence")
 }
 
 func (cp CardPair) Compare_UpWrap() (bool, error) {
-	if cp.c1.Ordinal() == cp.c2.Ordinal()-1 {
+	if cp.wild() {
+		return true, nil
+	}
+	if cp.c1.rank() == cp.c2.rank()-1 {
 		return true, nil
 	}
-	if cp.c1.Ordinal() == 13 && cp.c2.Ordinal() == 1 {
+	if cp.c1.rank() == cp.c1.topRank() && cp.c2.rank() == 1 {
 		return true, nil // Ace on King
 	}
 	return false, errors.New("Cards must go up in rank (Aces on Kings allowed)")
 }
 
 func (cp CardPair) Compare_Down() (bool, error) {
-	if cp.c1.Ordinal() == cp.c2.Ordinal()+1 {
+	if cp.wild() {
+		return true, nil
+	}
+	if cp.c1.rank() == cp.c2.rank()+1 {
 		return true, nil
 	}
 	return false, errors.New("Cards must be in descending sequence")
 }
 
 func (cp CardPair) Compare_DownWrap() (bool, error) {
-	if cp.c1.Ordinal() == cp.c2.Ordinal()+1 {
+	if cp.wild() {
 		return true, nil
 	}
-	if cp.c1.Ordinal() == 1 && cp.c2.Ordinal() == 13 {
+	if cp.c1.rank() == cp.c2.rank()+1 {
+		return true, nil
+	}
+	if cp.c1.rank() == 1 && cp.c2.rank() == cp.c2.topRank() {
 		return true, nil // King on Ace
 	}
 	return false, errors.New("Cards must be in descending sequence (Kings on Aces allowed)")
 }
 
 func (cp CardPair) Compare_UpOrDown() (bool, error) {
-	if !(cp.c1.Ordinal()+1 == cp.c2.Ordinal() || cp.c1.Ordinal() == cp.c2.Ordinal()+1) {
+	if cp.wild() {
+		return true, nil
+	}
+	if !(cp.c1.rank()+1 == cp.c2.rank() || cp.c1.rank() == cp.c2.rank()+1) {
 		return false, errors.New("Cards must be in ascending or descending sequence")
 	}
 	return true, nil
 }
 
 func (cp CardPair) Compare_UpOrDownWrap() (bool, error) {
-	if (cp.c1.Ordinal()+1 == cp.c2.Ordinal()) || (cp.c1.Ordinal() == cp.c2.Ordinal()+1) {
+	if cp.wild() {
+		return true, nil
+	}
+	if (cp.c1.rank()+1 == cp.c2.rank()) || (cp.c1.rank() == cp.c2.rank()+1) {
 		return true, nil
-	} else if cp.c1.Ordinal() == 13 && cp.c2.Ordinal() == 1 {
+	} else if cp.c1.rank() == cp.c1.topRank() && cp.c2.rank() == 1 {
 		return true, nil // Ace On King
-	} else if cp.c1.Ordinal() == 1 && cp.c2.Ordinal() == 13 {
+	} else if cp.c1.rank() == 1 && cp.c2.rank() == cp.c2.topRank() {
 		return true, nil // King on Ace
 	} else {
 		return false, errors.New("Cards must be in ascending or descending sequence")
@@ -136,6 +238,9 @@ func (cp CardPair) Compare_UpOrDownWrap() (bool, error) {
 }
 
 func (cp CardPair) Compare_Color() (bool, error) {
+	if cp.wild() {
+		return true, nil
+	}
 	if cp.c1.Black() != cp.c2.Black() {
 		return false, errors.New("Cards must be the same color")
 	}
@@ -143,6 +248,9 @@ func (cp CardPair) Compare_Color() (bool, error) {
 }
 
 func (cp CardPair) Compare_AltColor() (bool, error) {
+	if cp.wild() {
+		return true, nil
+	}
 	if cp.c1.Black() == cp.c2.Black() {
 		return false, errors.New("Cards must be in alternating colors")
 	}
@@ -150,6 +258,9 @@ func (cp CardPair) Compare_AltColor() (bool, error) {
 }
 
 func (cp CardPair) Compare_Suit() (bool, error) {
+	if cp.wild() {
+		return true, nil
+	}
 	if cp.c1.Suit() != cp.c2.Suit() {
 		return false, errors.New("Cards must be the same suit")
 	}
@@ -157,6 +268,9 @@ func (cp CardPair) Compare_Suit() (bool, error) {
 }
 
 func (cp CardPair) Compare_OtherSuit() (bool, error) {
+	if cp.wild() {
+		return true, nil
+	}
 	if cp.c1.Suit() == cp.c2.Suit() {
 		return false, errors.New("Cards must not be the same suit")
 	}
@@ -90,7 +90,7 @@ func (b *Baize) FindDestinations() {
 		dst := mc.dst
 		// moving an full tail from one pile to another empty pile is pointless
 		if dst.Len() == 0 && len(mc.tail) == len(src.cards) {
-			if src.label == dst.label && src.category == dst.category {
+			if src.interchangeable(dst) {
 				movable = false
 			}
 		}
@@ -129,13 +129,16 @@ func (b *Baize) FindDestinations() {
 		}
 	}
 
-	b.UpdateToolbar()
-	b.UpdateDrawers()
-	b.UpdateStatusbar()
-
-	if !TheGame.Settings.AlwaysShowMovableCards {
-		TheGame.Settings.ShowMovableCards = false
+	// in games like Pyramid, tapping a card removes it, with the first card it pairs with
+	for _, m := range b.pairMoves() {
+		b.moves++
+		card := b.piles[m.Src].Peek()
+		if card.tapWeight < 4 {
+			card.tapDestination = b.piles[m.Dst]
+			card.tapWeight = 4
+		}
 	}
+
 }
 
 /*
@@ -0,0 +1,69 @@
+package sol
+
+import (
+	"context"
+	"fmt"
+	"log"
+)
+
+// Deal ratings, from the number of positions the solver had to look at to win
+const (
+	EASY   = "easy"
+	MEDIUM = "medium"
+	HARD   = "hard"
+)
+
+// Difficulty describes how hard a deal was for the solver to win
+type Difficulty struct {
+	Nodes    int `json:",omitempty"`       // positions the solver expanded before finding a win
+	WinMoves int `json:"Length,omitempty"` // moves in the win found, less its detours; not always the shortest win
+	Forced   int `json:",omitempty"`       // positions on that win that had only one legal move
+}
+
+// Rating turns a Difficulty into EASY, MEDIUM or HARD
+func (d *Difficulty) Rating() string {
+	switch {
+	case d.Nodes <= 500:
+		return EASY
+	case d.Nodes <= 5000:
+		return MEDIUM
+	default:
+		return HARD
+	}
+}
+
+func (d *Difficulty) String() string {
+	return fmt.Sprintf("%s (%d nodes, %d moves, %d forced)", d.Rating(), d.Nodes, d.WinMoves, d.Forced)
+}
+
+// RateDeal deals a variant on a baize of its own, and asks the solver how hard it is.
+// The Difficulty is only returned if the solver found a win.
+func RateDeal(ctx context.Context, variant string, settings *Settings, seed uint64) (*Difficulty, SolverOutcome) {
+	b := NewBaize(variant, settings)
+	if b == nil {
+		return nil, GAVE_UP
+	}
+	b.StartFreshGame()
+	b.NewDealFromSeed(seed)
+	s := b.NewSolver()
+	s.MaxNodes = WinnableDealNodes
+	r := s.Solve(ctx)
+	if DebugMode {
+		log.Printf("%s deal #%d %s after %d nodes", variant, seed, r.Outcome, r.Nodes)
+	}
+	if r.Outcome != SOLVED {
+		return nil, r.Outcome
+	}
+	moves, forced := s.shorten()
+	return &Difficulty{Nodes: r.Nodes, WinMoves: len(moves), Forced: forced}, SOLVED
+}
+
+// Difficulty returns how hard the solver found this deal, or nil if that isn't known
+func (b *Baize) Difficulty() *Difficulty {
+	return b.difficulty
+}
+
+// SetDifficulty records how hard the solver found this deal
+func (b *Baize) SetDifficulty(d *Difficulty) {
+	b.difficulty = d
+}
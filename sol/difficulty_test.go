@@ -0,0 +1,47 @@
+package sol
+
+import (
+	"context"
+	"testing"
+)
+
+func TestShortenedSolutionStillWins(t *testing.T) {
+	b := NewBaize("Klondike", nil)
+	b.StartFreshGame()
+	b.NewDealFromSeed(1)
+	s := b.NewSolver()
+	r := s.Solve(context.Background())
+	if r.Outcome != SOLVED {
+		t.Fatalf("Klondike deal #1: %s", r.Outcome)
+	}
+	moves, forced := s.shorten()
+	if len(moves) > len(r.Moves) || forced > len(moves) {
+		t.Errorf("%d moves shortened to %d, with %d forced", len(r.Moves), len(moves), forced)
+	}
+	for i, m := range moves {
+		if !b.ApplyMove(m) {
+			t.Fatalf("move %d (%s) did nothing", i, m)
+		}
+	}
+	if !b.Complete() {
+		t.Error("shortened solution did not complete the game")
+	}
+}
+
+func TestRateDeal(t *testing.T) {
+	d, outcome := RateDeal(context.Background(), "Klondike", nil, 1)
+	if outcome != SOLVED || d == nil {
+		t.Fatalf("Klondike deal #1: %s", outcome)
+	}
+	if d.Nodes == 0 || d.WinMoves == 0 {
+		t.Errorf("Klondike deal #1: %s", d)
+	}
+	for _, tc := range []struct {
+		nodes int
+		want  string
+	}{{1, EASY}, {500, EASY}, {501, MEDIUM}, {5001, HARD}} {
+		if got := (&Difficulty{Nodes: tc.nodes}).Rating(); got != tc.want {
+			t.Errorf("%d nodes rated %s, want %s", tc.nodes, got, tc.want)
+		}
+	}
+}
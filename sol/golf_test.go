@@ -0,0 +1,170 @@
+package sol
+
+import (
+	"testing"
+
+	"oddstream.games/gosol/cardid"
+)
+
+func TestGolfDeal(t *testing.T) {
+	for _, tc := range []struct {
+		variant                      string
+		layout, faceDown, stock, fnd int
+	}{
+		{"Golf", 35, 0, 16, 1},
+		{"TriPeaks", 28, 18, 23, 1},
+		{"Black Hole", 51, 0, 0, 1},
+	} {
+		b := NewBaize(tc.variant, nil)
+		b.StartFreshGame()
+		golf := b.script.(*Golf)
+		var faceDown int
+		b.ForeachCard(func(c *Card) {
+			if c.Prone() && c.Owner() != b.script.Stock() {
+				faceDown++
+			}
+		})
+		if n := golf.layoutCards(); n != tc.layout {
+			t.Errorf("%s dealt %d cards to the layout, want %d", tc.variant, n, tc.layout)
+		}
+		if faceDown != tc.faceDown {
+			t.Errorf("%s dealt %d cards face down, want %d", tc.variant, faceDown, tc.faceDown)
+		}
+		if n := b.script.Stock().Len(); n != tc.stock {
+			t.Errorf("%s has %d cards in the stock, want %d", tc.variant, n, tc.stock)
+		}
+		if n := b.script.Foundations()[0].Len(); n != tc.fnd {
+			t.Errorf("%s has %d cards on the foundation, want %d", tc.variant, n, tc.fnd)
+		}
+		if pc := b.PercentComplete(); pc != 0 {
+			t.Errorf("%s is %d%% complete when dealt", tc.variant, pc)
+		}
+		if err := b.Validate(); err != nil {
+			t.Errorf("%s: %s", tc.variant, err)
+		}
+	}
+
+	b := NewBaize("Black Hole", nil)
+	b.StartFreshGame()
+	if c := b.script.Foundations()[0].Peek(); c.Suit() != cardid.SPADE || c.Ordinal() != 1 {
+		t.Errorf("Black Hole starts with %s on the foundation, want the Ace of Spades", c)
+	}
+}
+
+func TestGolfFoundation(t *testing.T) {
+	b := NewBaize("Golf", nil)
+	b.StartFreshGame()
+	f := b.script.Foundations()[0]
+	tabs := b.script.Tableaux()
+	putCard(b, f, cardid.CLUB, 7)
+	six := putCard(b, tabs[0], cardid.HEART, 6)
+	nine := putCard(b, tabs[1], cardid.SPADE, 9)
+	eight := putCard(b, tabs[2], cardid.DIAMOND, 8)
+	b.UndoPush()
+
+	if ok, _ := b.DropTail([]*Card{nine}, f); ok {
+		t.Error("a nine went on a seven")
+	}
+	if ok, _ := b.DropTail([]*Card{six}, tabs[2]); ok {
+		t.Error("a card was moved to another tableau")
+	}
+	if ok, err := b.DropTail([]*Card{six}, f); !ok {
+		t.Errorf("a six could not go on a seven: %s", err)
+	}
+	b.Undo()
+
+	// tapping a card sends it to the foundation
+	b.FindDestinations()
+	if !b.TailTapped([]*Card{eight}) || eight.Owner() != f {
+		t.Error("tapping an eight did not send it to the foundation, onto a seven")
+	}
+	// and the foundation isn't collected to
+	b.Collect2()
+	if nine.Owner() != tabs[1] {
+		t.Error("the nine was collected to the foundation")
+	}
+
+	// tapping the stock deals a card to the foundation
+	stock := b.script.Stock()
+	top := stock.Peek()
+	if !b.TailTapped([]*Card{top}) || f.Peek() != top || top.Prone() {
+		t.Error("tapping the stock did not deal a card to the foundation")
+	}
+
+	king := &Card{id: cardid.NewCardID(0, cardid.HEART, 13)}
+	ace := &Card{id: cardid.NewCardID(0, cardid.CLUB, 1)}
+	f.cards = append(f.cards, ace)
+	if ok, _ := b.script.TailAppendError(f, []*Card{king}); ok {
+		t.Error("a king went on an ace in Golf")
+	}
+	b = NewBaize("TriPeaks", nil)
+	b.StartFreshGame()
+	f = b.script.Foundations()[0]
+	f.cards = append(f.cards, ace)
+	if ok, err := b.script.TailAppendError(f, []*Card{king}); !ok {
+		t.Errorf("a king could not go on an ace in TriPeaks: %s", err)
+	}
+}
+
+func TestFoundationCapacity(t *testing.T) {
+	b := NewBaize("Golf", nil)
+	b.StartFreshGame()
+	if n := b.script.Foundations()[0].vtable.(*Foundation).capacity(); n != 52 {
+		t.Errorf("the Golf foundation holds %d cards, want the whole pack", n)
+	}
+
+	// any other variant with one foundation only gets a suit's worth
+	def, err := ParseVariantDef([]byte(`{"Name": "One Foundation", "Piles": [{"Category": "Stock"},
+		{"Category": "Foundation", "Slot": [1, 0], "Label": "A", "Build": "Up"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := NewBaize(addTestVariantDef(t, def), nil)
+	other.StartFreshGame()
+	if n := other.script.Foundations()[0].vtable.(*Foundation).capacity(); n != 13 {
+		t.Errorf("a lone foundation in a variant file holds %d cards, want 13", n)
+	}
+}
+
+func TestTriPeaksUncover(t *testing.T) {
+	b := NewBaize("TriPeaks", nil)
+	b.StartFreshGame()
+	f := b.script.Foundations()[0]
+	peaks := b.script.Reserves()
+	// the first card in the third row is overlapped by the first two cards in the bottom row
+	covered := peaks[9]
+	putCard(b, f, cardid.CLUB, 5)
+	four := putCard(b, peaks[18], cardid.HEART, 4)
+	three := putCard(b, peaks[19], cardid.SPADE, 3)
+	b.UndoPush()
+
+	if ok, _ := b.DropTail(covered.Cards(), f); ok {
+		t.Error("a covered card was moved")
+	}
+	if ok, err := b.DropTail([]*Card{four}, f); !ok {
+		t.Fatalf("a four could not go on a five: %s", err)
+	}
+	if !covered.Peek().Prone() {
+		t.Error("a card was turned up while it was still half covered")
+	}
+	if ok, err := b.DropTail([]*Card{three}, f); !ok {
+		t.Fatalf("a three could not go on a four: %s", err)
+	}
+	if covered.Peek().Prone() {
+		t.Error("a card was not turned up when it was uncovered")
+	}
+	if pc := b.PercentComplete(); pc != 7 {
+		t.Errorf("TriPeaks is %d%% complete with two cards gone, want 7", pc)
+	}
+	b.Undo()
+	if !covered.Peek().Prone() || three.Owner() != peaks[19] {
+		t.Error("undo did not cover the card again")
+	}
+
+	for _, p := range peaks {
+		MoveCard(p, f)
+	}
+	if !b.Complete() {
+		t.Error("TriPeaks not complete with the peaks cleared")
+	}
+}
@@ -0,0 +1,92 @@
+package sol
+
+//lint:file-ignore ST1006 Receiver name will be anything I like, thank you
+
+import "oddstream.games/gosol/cardid"
+
+// Positions are hashed Zobrist-style: each card, face up or face down, in each place it can be
+// (which pile, and how far up the pile) has a key that looks random, and the hash of a position
+// is the keys of all its cards xored together. So when a card moves or is flipped, the hash is
+// kept up to date by xoring out the card's old key and xoring in its new one.
+// The keys are made by mixing the card and place together, rather than looked up in a table,
+// so there is no limit to the number of piles or cards.
+
+// mix64 scrambles the bits of x (this is the finalizer of splitmix64)
+func mix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+// cardKey returns the key of a card (including which way up it is) at index in a pile
+func cardKey(cid cardid.CardID, pile, index int) uint64 {
+	return mix64(uint64(cid)<<40 ^ uint64(pile)<<20 ^ uint64(index))
+}
+
+// recyclesKey returns the key of the number of times the stock has been recycled
+func recyclesKey(recycles int) uint64 {
+	if recycles == 0 {
+		return 0
+	}
+	return mix64(1<<63 ^ uint64(recycles))
+}
+
+// hashCards works out the hash of the cards in this pile from scratch
+func (self *Pile) hashCards() uint64 {
+	var h uint64
+	for i, c := range self.cards {
+		h ^= cardKey(c.id, self.index, i)
+	}
+	return h
+}
+
+// rehash works out the hash of this pile again, after its cards have been changed other than by Push or Pop
+func (self *Pile) rehash() {
+	self.hash = self.hashCards()
+}
+
+// cardFlipped updates the hash of this pile after one of its cards has been turned over
+func (self *Pile) cardFlipped(c *Card, old cardid.CardID) {
+	for i := len(self.cards) - 1; i >= 0; i-- {
+		if self.cards[i] == c {
+			self.hash ^= cardKey(old, self.index, i) ^ cardKey(c.id, self.index, i)
+			return
+		}
+	}
+}
+
+// Hash identifies the position on the baize; the same cards, face up or down, in the same places,
+// with the stock recycled the same number of times, give the same hash.
+// Use it to see if a move has changed anything, to spot a position that has been seen before,
+// or as the key of a position in a search.
+func (b *Baize) Hash() uint64 {
+	h := recyclesKey(b.recycles)
+	for _, p := range b.piles {
+		h ^= p.hash
+	}
+	return h
+}
+
+// hash returns the Hash the baize would have in this position
+func (sb *SavableBaize) hash() uint64 {
+	h := recyclesKey(sb.Recycles)
+	for i, sp := range sb.Piles {
+		for j, cid := range sp.Cards {
+			h ^= cardKey(cid, i, j)
+		}
+	}
+	return h
+}
+
+// Repeated returns how many moves ago the current position was last seen in this line of play,
+// or 0 if it hasn't been seen before
+func (b *Baize) Repeated() int {
+	h := b.Hash()
+	for i := len(b.hashes) - 2; i >= 0; i-- {
+		if b.hashes[i] == h {
+			return len(b.hashes) - 1 - i
+		}
+	}
+	return 0
+}
@@ -0,0 +1,67 @@
+package sol
+
+import "testing"
+
+func TestHash(t *testing.T) {
+	for v := range Variants {
+		settings := DefaultSettings()
+		settings.AutoCollect = false
+		b := NewBaize(v, settings)
+		b.StartFreshGame()
+		b.NewDealFromSeed(8)
+		// the hash kept up to date as cards move is the hash worked out from scratch
+		check := func(when string) {
+			if b.Hash() != b.newSavableBaize().hash() {
+				t.Fatalf("%s: hash is wrong %s", v, when)
+			}
+		}
+		check("after the deal")
+		keys := playRandomly(b, 40, 8)
+		check("after playing")
+		for b.MovesMade() > 0 && !b.Complete() {
+			b.Undo()
+			check("after undo")
+		}
+		if b.MovesMade() == 0 && b.Hash() != keys[0] {
+			t.Errorf("%s: undoing every move didn't give the hash of the deal", v)
+		}
+	}
+}
+
+func TestHashSeesFlips(t *testing.T) {
+	b := NewBaize("Klondike", nil)
+	b.StartFreshGame()
+	b.NewDealFromSeed(1)
+	before := b.Hash()
+	c := b.script.Tableaux()[1].Peek()
+	c.FlipDown()
+	if b.Hash() == before {
+		t.Error("flipping a card didn't change the hash")
+	}
+	c.FlipUp()
+	if b.Hash() != before {
+		t.Error("flipping a card back didn't restore the hash")
+	}
+}
+
+func TestRepeated(t *testing.T) {
+	settings := DefaultSettings()
+	settings.AutoCollect = false
+	b := NewBaize("Freecell", settings)
+	b.StartFreshGame()
+	b.NewDealFromSeed(1)
+	cells := b.script.Cells()
+	tail := []*Card{b.script.Tableaux()[0].Peek()}
+	// move a card to a cell, then to the next cell, and back again
+	for _, dst := range []*Pile{cells[0], cells[1], cells[0]} {
+		if b.Repeated() != 0 {
+			t.Fatalf("position after %d moves seen before", b.MovesMade())
+		}
+		if ok, err := b.DropTail(tail, dst); !ok {
+			t.Fatal(err)
+		}
+	}
+	if b.Repeated() != 2 {
+		t.Errorf("position seen %d moves ago, want 2", b.Repeated())
+	}
+}
@@ -0,0 +1,125 @@
+package sol
+
+import (
+	"math"
+	"sort"
+)
+
+// HintPlies is how many moves ahead Hints looks
+var HintPlies = 3
+
+// HintNodes stops Hints looking further ahead when that would mean looking at more than this many positions,
+// so it stays quick enough to run between frames in variants with lots of moves, like Freecell
+var HintNodes = 5000
+
+// Hints returns the moves that can be made now, best first,
+// judged by looking up to HintPlies moves ahead for the best position each could lead to.
+// Ties are broken by preferring moves that turn over a card or empty a pile.
+func (b *Baize) Hints() []Move {
+	return b.rankedMoves(HintPlies)
+}
+
+// rankedMoves is Hints, looking no more than maxPlies moves ahead
+func (b *Baize) rankedMoves(maxPlies int) []Move {
+	s := b.NewSolver()
+	if s == nil {
+		return nil
+	}
+	var moves []Move
+	for plies := 1; plies <= maxPlies; plies++ {
+		s.nodes = 0
+		moves = s.rankMoves(plies)
+		// each extra ply multiplies the work by about the number of moves
+		if s.nodes*len(moves) > HintNodes {
+			break
+		}
+	}
+	return moves
+}
+
+// HintsUsed returns the number of hints the player has asked for in this game
+func (b *Baize) HintsUsed() int {
+	return b.hints
+}
+
+// UseHint counts a hint against this game
+func (b *Baize) UseHint() {
+	b.hints++
+}
+
+// evaluate scores a position: the more cards collected, turned face up, and piles emptied, the better
+func (b *Baize) evaluate() int {
+	var score int
+	for _, p := range b.piles {
+		switch p.vtable.(type) {
+		case *Foundation, *Discard:
+			score += 10 * p.Len()
+		case *Tableau:
+			if p.Empty() {
+				score += 5
+			}
+			for _, c := range p.cards {
+				if c.Prone() {
+					score -= 5
+				}
+			}
+		case *Cell:
+			if p.Empty() {
+				score += 2
+			}
+		}
+	}
+	return score
+}
+
+// rankMoves sorts the moves from the solver's position, best first
+func (s *Solver) rankMoves(plies int) []Move {
+	b := s.b
+	depth := b.MovesMade()
+	var moves []Move
+	var scores = make(map[Move]int)
+	for _, m := range b.LegalMoves() {
+		tieBreak := b.moveScore(m)
+		if !b.ApplyMove(m) {
+			continue
+		}
+		s.visited = make(map[uint64]bool)
+		scores[m] = s.lookahead(plies-1)*10 + tieBreak/10
+		moves = append(moves, m)
+		b.rewindTo(depth)
+	}
+	sort.SliceStable(moves, func(i, j int) bool { return scores[moves[i]] > scores[moves[j]] })
+	return moves
+}
+
+// lookahead returns the score of the best position within plies moves of this one
+func (s *Solver) lookahead(plies int) int {
+	b := s.b
+	s.nodes++
+	if b.Complete() {
+		return math.MaxInt32
+	}
+	best := b.evaluate()
+	if plies <= 0 {
+		return best
+	}
+	key := b.Hash()
+	if s.visited[key] {
+		return best
+	}
+	s.visited[key] = true
+	depth := b.MovesMade()
+	for _, m := range b.LegalMoves() {
+		if m.Dst < 0 {
+			continue // don't peek at what the stock would deal
+		}
+		if !b.ApplyMove(m) {
+			continue
+		}
+		if v := s.lookahead(plies - 1); v > best {
+			best = v
+		}
+		b.rewindTo(depth)
+	}
+	return best
+}
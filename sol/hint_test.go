@@ -0,0 +1,41 @@
+package sol
+
+import "testing"
+
+func TestHints(t *testing.T) {
+	for _, v := range []string{"Klondike", "Freecell", "Spider One Suit", "Yukon"} {
+		b := NewBaize(v, nil)
+		b.StartFreshGame()
+		b.NewDealFromSeed(1)
+		before := b.Hash()
+		hints := b.Hints()
+		if len(hints) == 0 {
+			t.Fatalf("%s: no hints", v)
+		}
+		if b.Hash() != before {
+			t.Errorf("%s: looking for hints changed the baize", v)
+		}
+		if !b.ApplyMove(hints[0]) {
+			t.Errorf("%s: hint %s can't be made", v, hints[0])
+		}
+	}
+}
+
+func TestHintsSurviveUndo(t *testing.T) {
+	b := NewBaize("Klondike", nil)
+	b.StartFreshGame()
+	b.NewDealFromSeed(1)
+	b.UseHint()
+	if !b.ApplyMove(b.Hints()[0]) {
+		t.Fatal("hint can't be made")
+	}
+	b.UseHint()
+	b.Undo()
+	if b.HintsUsed() != 2 {
+		t.Errorf("got %d hints used after undo, want 2", b.HintsUsed())
+	}
+	b.NewDeal()
+	if b.HintsUsed() != 0 {
+		t.Errorf("got %d hints used in a new deal, want 0", b.HintsUsed())
+	}
+}
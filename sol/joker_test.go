@@ -0,0 +1,191 @@
+package sol
+
+import (
+	"encoding/json"
+	"testing"
+
+	"oddstream.games/gosol/cardid"
+)
+
+func jokersOnBaize(b *Baize) []*Card {
+	var jokers []*Card
+	b.ForeachCard(func(c *Card) {
+		if c.Joker() {
+			jokers = append(jokers, c)
+		}
+	})
+	return jokers
+}
+
+func TestJokerDecks(t *testing.T) {
+	for _, tc := range []struct {
+		variant       string
+		cards, jokers int
+	}{
+		{"Klondike", 52, 0},
+		{"Klondike with Jokers", 54, 2},
+		{"Spider with Jokers", 106, 2},
+	} {
+		b := NewBaize(tc.variant, nil)
+		b.StartFreshGame()
+		if b.cardCount != tc.cards {
+			t.Errorf("%s has %d cards, want %d", tc.variant, b.cardCount, tc.cards)
+		}
+		jokers := jokersOnBaize(b)
+		if len(jokers) != tc.jokers {
+			t.Errorf("%s has %d jokers, want %d", tc.variant, len(jokers), tc.jokers)
+		}
+		for _, c := range jokers {
+			if c.Suit() != cardid.NOSUIT || c.Ordinal() != 0 {
+				t.Errorf("%s joker %s has a suit or ordinal", tc.variant, c)
+			}
+		}
+		if err := b.Validate(); err != nil {
+			t.Errorf("%s: %s", tc.variant, err)
+		}
+	}
+}
+
+func TestJokersAreWild(t *testing.T) {
+	joker := &Card{id: cardid.NewJokerID(0, 0)}
+	sevenClubs := &Card{id: cardid.NewCardID(0, cardid.CLUB, 7)}
+	kingHearts := &Card{id: cardid.NewCardID(0, cardid.HEART, 13)}
+
+	for _, fn := range []CardPairCompareFunc{
+		CardPair.Compare_Up, CardPair.Compare_Down, CardPair.Compare_UpOrDownWrap,
+		CardPair.Compare_Color, CardPair.Compare_AltColor, CardPair.Compare_Suit, CardPair.Compare_OtherSuit,
+		CardPair.Compare_DownAltColor, CardPair.Compare_UpSuit, CardPair.Compare_DownSuitWrap,
+	} {
+		if ok, err := fn(CardPair{sevenClubs, joker}); !ok {
+			t.Errorf("a joker could not go on a card: %s", err)
+		}
+		if ok, err := fn(CardPair{joker, kingHearts}); !ok {
+			t.Errorf("a card could not go on a joker: %s", err)
+		}
+	}
+	if ok, _ := (CardPair{sevenClubs, kingHearts}).Compare_DownAltColor(); ok {
+		t.Error("a king went on a seven")
+	}
+
+	b := NewBaize("Klondike with Jokers", nil)
+	b.StartFreshGame()
+	f := b.script.Foundations()[0]
+	if ok, err := Compare_Empty(f, joker); !ok {
+		t.Errorf("a joker could not go on an empty foundation labelled A: %s", err)
+	}
+	if ok, _ := Compare_Empty(f, sevenClubs); ok {
+		t.Error("a seven went on an empty foundation labelled A")
+	}
+}
+
+func TestJokersOnFoundations(t *testing.T) {
+	b := NewBaize("Klondike with Jokers", nil)
+	b.StartFreshGame()
+	f := b.script.Foundations()[0]
+	joker := &Card{id: cardid.NewJokerID(0, 0)}
+	ace := &Card{id: cardid.NewCardID(0, cardid.SPADE, 1)}
+	two := &Card{id: cardid.NewCardID(0, cardid.SPADE, 2)}
+	three := &Card{id: cardid.NewCardID(0, cardid.HEART, 3)}
+
+	// a joker doesn't take the place of a card on a foundation, so the next card must follow the card beneath it
+	f.cards = []*Card{ace, joker}
+	if ok, err := b.script.TailAppendError(f, []*Card{two}); !ok {
+		t.Errorf("the two of spades could not follow the ace, past a joker: %s", err)
+	}
+	if ok, _ := b.script.TailAppendError(f, []*Card{three}); ok {
+		t.Error("the three of hearts followed the ace, past a joker")
+	}
+	f.cards = []*Card{joker}
+	if ok, err := b.script.TailAppendError(f, []*Card{ace}); !ok {
+		t.Errorf("an ace could not go on a joker on an empty foundation: %s", err)
+	}
+	f.cards = nil
+}
+
+func TestJokersAreSaved(t *testing.T) {
+	b := NewBaize("Klondike with Jokers", nil)
+	b.StartFreshGame()
+	playRandomly(b, 100, 1)
+	want := make(map[cardid.CardID]string)
+	for _, c := range jokersOnBaize(b) {
+		want[c.id] = pileName(c.Owner())
+	}
+
+	bytes, err := json.Marshal(b.MoveLog())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ml, err := unmarshalMoveLog(bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := NewBaize("Klondike with Jokers", nil)
+	other.StartFreshGame()
+	other.SetMoveLog(ml)
+	if err := other.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	got := jokersOnBaize(other)
+	if len(got) != len(want) {
+		t.Fatalf("%d jokers after loading, want %d", len(got), len(want))
+	}
+	for _, c := range got {
+		if pileName(c.Owner()) != want[c.id] {
+			t.Errorf("joker %s is in %s after loading, want %s", c, pileName(c.Owner()), want[c.id])
+		}
+	}
+}
+
+func TestJokerStandsForOneCard(t *testing.T) {
+	b := NewBaize("Klondike with Jokers", nil)
+	b.StartFreshGame()
+	tab := b.script.Tableaux()[0]
+	card := func(suit, ord int) *Card {
+		return &Card{id: cardid.NewCardID(0, suit, ord), owningPile: tab}
+	}
+	joker := func() *Card {
+		return &Card{id: cardid.NewJokerID(0, 0), owningPile: tab}
+	}
+	for _, tc := range []struct {
+		name     string
+		tail     []*Card
+		ok       bool
+		unsorted int
+	}{
+		{"9♠ Joker 3♥", []*Card{card(cardid.SPADE, 9), joker(), card(cardid.HEART, 3)}, false, 1},
+		{"9♠ Joker 7♠", []*Card{card(cardid.SPADE, 9), joker(), card(cardid.SPADE, 7)}, true, 0},
+		{"9♠ Joker 7♥", []*Card{card(cardid.SPADE, 9), joker(), card(cardid.HEART, 7)}, false, 1},
+		{"9♠ Joker Joker 6♥", []*Card{card(cardid.SPADE, 9), joker(), joker(), card(cardid.HEART, 6)}, true, 0},
+		{"9♠ Joker Joker 7♥", []*Card{card(cardid.SPADE, 9), joker(), joker(), card(cardid.HEART, 7)}, false, 1},
+		{"2♠ Joker", []*Card{card(cardid.SPADE, 2), joker()}, true, 0},
+		{"A♠ Joker", []*Card{card(cardid.SPADE, 1), joker()}, false, 1},
+	} {
+		ok, err := TailConformant(tc.tail, CardPair.Compare_DownAltColor)
+		if ok != tc.ok {
+			t.Errorf("%s: got %v (%v), want %v", tc.name, ok, err, tc.ok)
+		}
+		tab.cards = tc.tail
+		if n := UnsortedPairs(tab, CardPair.Compare_DownAltColor); n != tc.unsorted {
+			t.Errorf("%s: got %d unsorted pairs, want %d", tc.name, n, tc.unsorted)
+		}
+	}
+	tab.cards = nil
+}
+
+func TestSpiderDiscardsOnlyWholeSuits(t *testing.T) {
+	b := NewBaize("Spider with Jokers", nil)
+	b.StartFreshGame()
+	tab, discard := b.script.Tableaux()[0], b.script.Discards()[0]
+	var tail []*Card
+	for ord := 13; ord >= 1; ord-- {
+		if ord == 7 {
+			tail = append(tail, &Card{id: cardid.NewJokerID(0, 0), owningPile: tab})
+			continue
+		}
+		tail = append(tail, &Card{id: cardid.NewCardID(0, cardid.SPADE, ord), owningPile: tab})
+	}
+	// the joker can stand for the Seven, but then the run is short of a card
+	if ok, _ := discard.vtable.CanAcceptTail(tail); ok {
+		t.Error("the discard took twelve cards and a joker")
+	}
+}
@@ -4,7 +4,10 @@ package sol
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
+	"path"
+	"time"
 
 	"oddstream.games/gosol/util"
 )
@@ -67,34 +70,101 @@ func (s *Statistics) Save() {
 	util.SaveBytesToFile(bytes, "statistics.json")
 }
 
-// Load an undo stack saved to json
-func (b *Baize) Load() {
+// scriptModTimes remembers when each variant script was loaded, so changed scripts can be reloaded
+var scriptModTimes = make(map[string]time.Time)
+
+// LoadVariants adds the variants defined in .json files and .lua scripts in the variants directory
+// within the config directory, and returns what was wrong with any that couldn't be added
+func LoadVariants() []error {
+	var errs []error
+	for _, name := range util.ConfigDirFiles("variants") {
+		var err error
+		switch path.Ext(name) {
+		case ".json":
+			var bytes []byte
+			var count int
+			bytes, count, err = util.LoadBytesFromFile(path.Join("variants", name), false)
+			if err == nil {
+				var def *VariantDef
+				// golang gotcha reslice buffer to number of bytes actually read
+				if def, err = ParseVariantDef(bytes[:count]); err == nil {
+					err = AddVariant(def)
+				}
+			}
+		case ".lua":
+			_, err = loadScript(name)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	return errs
+}
+
+// loadScript adds (or replaces) the variant played by a script in the variants directory, and returns its name
+func loadScript(name string) (string, error) {
+	fname := path.Join("variants", name)
+	scriptModTimes[name] = util.ConfigFileModTime(fname)
+	bytes, count, err := util.LoadBytesFromFile(fname, false)
+	if err != nil {
+		return "", err
+	}
+	s, err := ParseVariantScript(name, bytes[:count])
+	if err != nil {
+		return "", err
+	}
+	return s.name, AddVariantScript(s)
+}
+
+// ReloadScripts reloads the variant scripts that have changed since they were loaded,
+// and returns the names of the variants that were reloaded
+func ReloadScripts() ([]string, []error) {
+	var reloaded []string
+	var errs []error
+	for name, modTime := range scriptModTimes {
+		if !util.ConfigFileModTime(path.Join("variants", name)).After(modTime) {
+			continue
+		}
+		if variant, err := loadScript(name); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		} else {
+			reloaded = append(reloaded, variant)
+		}
+	}
+	return reloaded, errs
+}
+
+// Load a move log saved to json, returns true if a saved game was loaded
+func (b *Baize) Load() bool {
 	bytes, count, err := util.LoadBytesFromFile("saved."+b.variant+".json", true)
 	if err != nil || count == 0 || bytes == nil {
-		return
+		return false
 	}
-	var undoStack []*SavableBaize
 	// golang gotcha reslice buffer to number of bytes actually read
-	err = json.Unmarshal(bytes[:count], &undoStack)
+	// the file has already been removed, so a bad one won't be loaded again
+	ml, err := unmarshalMoveLog(bytes[:count])
 	if err != nil {
-		log.Fatal(err)
+		log.Printf("%s.Load().Unmarshal() error %s", b.variant, err)
+		return false
 	}
-	if !b.isSavableStackOk(undoStack) {
-		log.Fatal("saved undo stack is not ok")
+	if !b.isMoveLogOk(ml) {
+		log.Println("saved move log is not ok")
+		return false
 	}
-	b.SetUndoStack(undoStack)
+	b.SetMoveLog(ml)
+	return true
 }
 
-// Save the entire undo stack to file
+// Save the move log to file
 func (b *Baize) Save() {
 	// defer util.Duration(time.Now(), "Baize.Save")
 
 	// do not bother to save virgin or completed games
-	// if len(b.undoStack) < 2 || b.Complete() {
+	// if b.MovesMade() == 0 || b.Complete() {
 	// 	return
 	// }
 
-	bytes, err := json.MarshalIndent(b.undoStack, "", "\t")
+	bytes, err := json.Marshal(b.MoveLog())
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -91,32 +91,43 @@ func (s *Statistics) Save() {
 
 }
 
-// Load the entire undo stack from storage
-func (b *Baize) Load() {
+// LoadVariants does nothing in a browser, where there is no config directory to put variant files in
+func LoadVariants() []error {
+	return nil
+}
+
+// ReloadScripts does nothing in a browser, where there are no variant scripts
+func ReloadScripts() ([]string, []error) {
+	return nil, nil
+}
+
+// Load the move log from storage, returns true if a saved game was loaded
+func (b *Baize) Load() bool {
 	bytes, err := loadBytesFromLocalStorage("saved."+b.variant, true)
 	if err != nil {
 		log.Println(err)
-		return
+		return false
 	}
-	var undoStack []*SavableBaize
-	err = json.Unmarshal(bytes, undoStack)
+	ml, err := unmarshalMoveLog(bytes)
 	if err != nil {
-		log.Println("%s.Load().Unmarshal() error", b.variant, err)
-		return
+		log.Printf("%s.Load().Unmarshal() error %s", b.variant, err)
+		return false
 	}
-	if !b.IsSavableStackOk(undoStack) {
-		log.Println("saved undo stack is not ok")
+	if !b.isMoveLogOk(ml) {
+		log.Println("saved move log is not ok")
+		return false
 	}
-	b.SetUndoStack(undoStack)
+	b.SetMoveLog(ml)
+	return true
 }
 
-// Save the entire undo stack to storage
+// Save the move log to storage
 func (b *Baize) Save() {
 	// // do not bother to save virgin or completed games
-	// if len(b.undoStack) < 2 || b.Complete() {
+	// if b.MovesMade() == 0 || b.Complete() {
 	// 	return
 	// }
-	bytes, err := json.Marshal(b.undoStack)
+	bytes, err := json.Marshal(b.MoveLog())
 	if err != nil {
 		log.Println("Baize.Save().Marshal() error", err)
 	} else {
@@ -0,0 +1,424 @@
+package sol
+
+//lint:file-ignore ST1005 Error messages are toasted, so need to be capitalized
+//lint:file-ignore ST1006 Receiver name will be anything I like, thank you
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"log"
+	"strings"
+
+	lua "github.com/yuin/gopher-lua"
+	"github.com/yuin/gopher-lua/parse"
+	"oddstream.games/gosol/cardid"
+)
+
+// the Lua type names of piles and cards
+const (
+	luaPile = "Pile"
+	luaCard = "Card"
+)
+
+// scriptHooks are the Scripter functions a script can define; the first two it must
+var scriptHooks = []string{"BuildPiles", "StartGame", "AfterMove", "TailMoveError", "TailAppendError", "TailTapped", "PileTapped"}
+
+// ParseVariantScript compiles a Lua script that plays a variant, and runs it
+// to find out the variant's name, packs and suits, and which hooks it has
+func ParseVariantScript(fname string, data []byte) (*Scripted, error) {
+	chunk, err := parse.Parse(bytes.NewReader(data), fname)
+	if err != nil {
+		return nil, err // eg klondike.lua line:3(column:9) near 'end': syntax error
+	}
+	proto, err := lua.Compile(chunk, fname)
+	if err != nil {
+		return nil, err
+	}
+	self := &Scripted{fname: fname, proto: proto}
+	L := self.newState()
+	defer L.Close()
+	if err := self.run(L, L.NewFunctionFromProto(proto), 0); err != nil {
+		return nil, err
+	}
+
+	e := &VariantDefError{Name: fname}
+	problem := func(format string, a ...any) {
+		e.Problems = append(e.Problems, fmt.Sprintf(format, a...))
+	}
+	number := func(name string, allowed ...int) int {
+		switch v := L.GetGlobal(name).(type) {
+		case *lua.LNilType:
+		case lua.LNumber:
+			for _, n := range allowed {
+				if int(v) == n {
+					return n
+				}
+			}
+			problem("%s must be one of %v, not %v", name, allowed, v)
+		default:
+			problem("%s must be a number", name)
+		}
+		return 0
+	}
+	if name, ok := L.GetGlobal("Name").(lua.LString); ok && name != "" {
+		self.name, e.Name = string(name), string(name)
+		if !fileSafeName(self.name) {
+			problem("Name can't contain %s", unsafeNameParts)
+		}
+	} else {
+		problem("Name is missing")
+	}
+	if wikipedia, ok := L.GetGlobal("Wikipedia").(lua.LString); ok {
+		self.wikipedia = string(wikipedia)
+	}
+	self.packs = number("Packs", 1, 2, 3, 4)
+	self.suits = number("Suits", 1, 2, 4)
+	self.cardColors = number("CardColors", 1, 2, 4)
+	self.jokers = number("Jokers", 0, 1, 2, 3, 4)
+	for i, hook := range scriptHooks {
+		switch L.GetGlobal(hook).(type) {
+		case *lua.LFunction:
+		case *lua.LNilType:
+			if i < 2 {
+				problem("There must be a %s function", hook)
+			}
+		default:
+			problem("%s must be a function", hook)
+		}
+	}
+	if len(e.Problems) > 0 {
+		return nil, e
+	}
+	return self, nil
+}
+
+// AddVariantScript makes a scripted variant playable, and puts it in the variant picker.
+// A script can replace the variant it made before, so scripts can be reloaded.
+func AddVariantScript(self *Scripted) error {
+	variantsLock.Lock()
+	defer variantsLock.Unlock()
+	if old, ok := Variants[self.name]; ok {
+		if old, ok := old.(*Scripted); !ok || old.fname != self.fname {
+			return &VariantDefError{Name: self.name, Problems: []string{"There is already a variant called " + self.name}}
+		}
+		Variants[self.name] = self
+		return nil
+	}
+	Variants[self.name] = self
+	for _, group := range []string{"> All", "> All by Played", "> Your Own"} {
+		VariantGroups[group] = append(VariantGroups[group][:len(VariantGroups[group]):len(VariantGroups[group])], self.name)
+	}
+	return nil
+}
+
+// newState makes a sandboxed interpreter for the script, that can see the pile and card
+// helpers, but not the file system or anything else outside the game
+func (self *Scripted) newState() *lua.LState {
+	L := lua.NewState(lua.Options{SkipOpenLibs: true})
+	self.luaPiles = make(map[*Pile]*lua.LUserData)
+	self.luaCards = make(map[*Card]*lua.LUserData)
+	for _, lib := range []struct {
+		name string
+		open lua.LGFunction
+	}{
+		{lua.BaseLibName, lua.OpenBase},
+		{lua.TabLibName, lua.OpenTable},
+		{lua.StringLibName, lua.OpenString},
+		{lua.MathLibName, lua.OpenMath},
+	} {
+		L.Push(L.NewFunction(lib.open))
+		L.Push(lua.LString(lib.name))
+		L.Call(1, 0)
+	}
+	for _, name := range []string{"collectgarbage", "dofile", "getfenv", "load", "loadfile", "loadstring", "module", "newproxy", "require", "setfenv", "_printregs"} {
+		L.SetGlobal(name, lua.LNil)
+	}
+
+	mt := L.NewTypeMetatable(luaPile)
+	L.SetField(mt, "__index", L.SetFuncs(L.NewTable(), map[string]lua.LGFunction{
+		"Len":      func(L *lua.LState) int { L.Push(lua.LNumber(self.checkPile(L, 1).Len())); return 1 },
+		"Empty":    func(L *lua.LState) int { L.Push(lua.LBool(self.checkPile(L, 1).Empty())); return 1 },
+		"Peek":     func(L *lua.LState) int { L.Push(self.cardValue(L, self.checkPile(L, 1).Peek())); return 1 },
+		"Category": func(L *lua.LState) int { L.Push(lua.LString(self.checkPile(L, 1).Category())); return 1 },
+		"Label":    func(L *lua.LState) int { L.Push(lua.LString(self.checkPile(L, 1).Label())); return 1 },
+		"Get": func(L *lua.LState) int {
+			pile, i := self.checkPile(L, 1), L.CheckInt(2)
+			if i < 1 || i > pile.Len() {
+				L.Push(lua.LNil)
+			} else {
+				L.Push(self.cardValue(L, pile.Get(i-1)))
+			}
+			return 1
+		},
+		"Cards": func(L *lua.LState) int { L.Push(self.tailValue(L, self.checkPile(L, 1).cards)); return 1 },
+		"Slot": func(L *lua.LState) int {
+			slot := self.checkPile(L, 1).Slot()
+			L.Push(lua.LNumber(slot.X))
+			L.Push(lua.LNumber(slot.Y))
+			return 2
+		},
+		"SetLabel": func(L *lua.LState) int {
+			pile, label := self.checkPile(L, 1), L.CheckString(2)
+			if !contains(pileLabels, label) {
+				L.ArgError(2, "label must be one of "+strings.Join(pileLabels[1:], ", "))
+			}
+			pile.SetLabel(label)
+			return 0
+		},
+		"ReverseCards": func(L *lua.LState) int { self.checkPile(L, 1).ReverseCards(); return 0 },
+	}))
+	L.SetField(mt, "__tostring", L.NewFunction(func(L *lua.LState) int {
+		pile := self.checkPile(L, 1)
+		L.Push(lua.LString(fmt.Sprintf("%s %d,%d", pile.Category(), pile.Slot().X, pile.Slot().Y)))
+		return 1
+	}))
+
+	mt = L.NewTypeMetatable(luaCard)
+	L.SetField(mt, "__index", L.SetFuncs(L.NewTable(), map[string]lua.LGFunction{
+		"Ordinal":  func(L *lua.LState) int { L.Push(lua.LNumber(self.checkCard(L, 1).Ordinal())); return 1 },
+		"Suit":     func(L *lua.LState) int { L.Push(lua.LNumber(self.checkCard(L, 1).Suit())); return 1 },
+		"Black":    func(L *lua.LState) int { L.Push(lua.LBool(self.checkCard(L, 1).Black())); return 1 },
+		"Joker":    func(L *lua.LState) int { L.Push(lua.LBool(self.checkCard(L, 1).Joker())); return 1 },
+		"Prone":    func(L *lua.LState) int { L.Push(lua.LBool(self.checkCard(L, 1).Prone())); return 1 },
+		"Owner":    func(L *lua.LState) int { L.Push(self.pileValue(L, self.checkCard(L, 1).Owner())); return 1 },
+		"FlipUp":   func(L *lua.LState) int { self.checkCard(L, 1).FlipUp(); return 0 },
+		"FlipDown": func(L *lua.LState) int { self.checkCard(L, 1).FlipDown(); return 0 },
+	}))
+	L.SetField(mt, "__tostring", L.NewFunction(func(L *lua.LState) int {
+		L.Push(lua.LString(self.checkCard(L, 1).String()))
+		return 1
+	}))
+
+	globals := map[string]lua.LGFunction{
+		"print": func(L *lua.LState) int {
+			var a []string
+			for i := 1; i <= L.GetTop(); i++ {
+				a = append(a, L.ToStringMeta(L.Get(i)).String())
+			}
+			log.Println(self.fname+":", strings.Join(a, " "))
+			return 0
+		},
+		"NewStock": func(L *lua.LState) int {
+			self.stock = NewStock(self.checkBaize(L), self.checkSlot(L), FAN_NONE, self.Packs(), self.Suits(), nil, self.Jokers())
+			return self.pushPile(L, self.stock)
+		},
+		"NewWaste": func(L *lua.LState) int {
+			self.waste = NewWaste(self.checkBaize(L), self.checkSlot(L), self.optFan(L, 3))
+			return self.pushPile(L, self.waste)
+		},
+		"NewFoundation": func(L *lua.LState) int {
+			pile := NewFoundation(self.checkBaize(L), self.checkSlot(L))
+			self.foundations = append(self.foundations, pile)
+			return self.pushPile(L, pile)
+		},
+		"NewTableau": func(L *lua.LState) int {
+			pile := NewTableau(self.checkBaize(L), self.checkSlot(L), self.optFan(L, 3), self.optMove(L, 4))
+			self.tableaux = append(self.tableaux, pile)
+			return self.pushPile(L, pile)
+		},
+		"NewCell": func(L *lua.LState) int {
+			pile := NewCell(self.checkBaize(L), self.checkSlot(L))
+			self.cells = append(self.cells, pile)
+			return self.pushPile(L, pile)
+		},
+		"NewReserve": func(L *lua.LState) int {
+			pile := NewReserve(self.checkBaize(L), self.checkSlot(L), self.optFan(L, 3))
+			self.reserves = append(self.reserves, pile)
+			return self.pushPile(L, pile)
+		},
+		"NewDiscard": func(L *lua.LState) int {
+			pile := NewDiscard(self.checkBaize(L), self.checkSlot(L), self.optFan(L, 3))
+			self.discards = append(self.discards, pile)
+			return self.pushPile(L, pile)
+		},
+		"Stock":       func(L *lua.LState) int { return self.pushPile(L, self.stock) },
+		"Waste":       func(L *lua.LState) int { return self.pushPile(L, self.waste) },
+		"Cells":       func(L *lua.LState) int { return self.pushPiles(L, self.cells) },
+		"Discards":    func(L *lua.LState) int { return self.pushPiles(L, self.discards) },
+		"Foundations": func(L *lua.LState) int { return self.pushPiles(L, self.foundations) },
+		"Reserves":    func(L *lua.LState) int { return self.pushPiles(L, self.reserves) },
+		"Tableaux":    func(L *lua.LState) int { return self.pushPiles(L, self.tableaux) },
+		"MoveCard": func(L *lua.LState) int {
+			L.Push(self.cardValue(L, MoveCard(self.checkPile(L, 1), self.checkPile(L, 2))))
+			return 1
+		},
+		"MoveTail": func(L *lua.LState) int {
+			card, dst := self.checkCard(L, 1), self.checkPile(L, 2)
+			if card.Owner() == nil {
+				L.ArgError(1, "card is not in a pile")
+			}
+			MoveTail(card, dst)
+			return 0
+		},
+		"RecycleWasteToStock": func(L *lua.LState) int {
+			RecycleWasteToStock(self.checkPile(L, 1), self.checkPile(L, 2))
+			return 0
+		},
+		"DefaultTailTapped": func(L *lua.LState) int {
+			tail := self.checkTail(L, 1)
+			tail[0].Owner().vtable.TailTapped(tail)
+			return 0
+		},
+		"Recycles":    func(L *lua.LState) int { L.Push(lua.LNumber(self.checkBaize(L).Recycles())); return 1 },
+		"SetRecycles": func(L *lua.LState) int { self.checkBaize(L).SetRecycles(L.CheckInt(1)); return 0 },
+		"Toast":       func(L *lua.LState) int { self.checkBaize(L).toastInfo(L.CheckString(1)); return 0 },
+		"Compare_Empty": func(L *lua.LState) int {
+			return pushResult(L)(Compare_Empty(self.checkPile(L, 1), self.checkCard(L, 2)))
+		},
+		"TailConformant": func(L *lua.LState) int {
+			tail, name := self.checkTail(L, 1), L.CheckString(2)
+			fn, ok := compareFunc(name)
+			if !ok {
+				L.ArgError(2, "must be one of "+strings.Join(compareFuncNames(), ", "))
+			}
+			return pushResult(L)(TailConformant(tail, fn))
+		},
+	}
+	for _, name := range compareFuncNames() {
+		fn, _ := compareFunc(name)
+		globals["Compare_"+name] = func(L *lua.LState) int {
+			return pushResult(L)(fn(CardPair{self.checkCard(L, 1), self.checkCard(L, 2)}))
+		}
+	}
+	for name, fn := range globals {
+		L.SetGlobal(name, L.NewFunction(fn))
+	}
+	for name, suit := range map[string]int{"CLUB": cardid.CLUB, "DIAMOND": cardid.DIAMOND, "HEART": cardid.HEART, "SPADE": cardid.SPADE} {
+		L.SetGlobal(name, lua.LNumber(suit))
+	}
+	return L
+}
+
+// pushResult returns a function that gives a script the result of a rule:
+// nil if something is allowed, or the reason why not
+func pushResult(L *lua.LState) func(bool, error) int {
+	return func(ok bool, err error) int {
+		switch {
+		case ok:
+			L.Push(lua.LNil)
+		case err != nil:
+			L.Push(lua.LString(err.Error()))
+		default:
+			L.Push(lua.LString("That is not allowed"))
+		}
+		return 1
+	}
+}
+
+// checkBaize stops a script that calls a helper before there is a baize, ie outside a hook
+func (self *Scripted) checkBaize(L *lua.LState) *Baize {
+	if self.baize == nil {
+		L.RaiseError("piles and cards can only be used inside a function like BuildPiles or StartGame")
+	}
+	return self.baize
+}
+
+func (self *Scripted) checkSlot(L *lua.LState) image.Point {
+	return image.Point{L.CheckInt(1), L.CheckInt(2)}
+}
+
+func (self *Scripted) optFan(L *lua.LState, n int) FanType {
+	fan, ok := fanTypes[L.OptString(n, "")]
+	if !ok {
+		L.ArgError(n, "fan must be one of "+strings.Join(mapKeys(fanTypes), ", "))
+	}
+	return fan
+}
+
+func (self *Scripted) optMove(L *lua.LState, n int) MoveType {
+	move, ok := moveTypes[L.OptString(n, "")]
+	if !ok {
+		L.ArgError(n, "move type must be one of "+strings.Join(mapKeys(moveTypes), ", "))
+	}
+	return move
+}
+
+func (self *Scripted) checkPile(L *lua.LState, n int) *Pile {
+	if pile, ok := L.CheckUserData(n).Value.(*Pile); ok {
+		return pile
+	}
+	L.ArgError(n, "pile expected")
+	return nil
+}
+
+func (self *Scripted) checkCard(L *lua.LState, n int) *Card {
+	if card, ok := L.CheckUserData(n).Value.(*Card); ok {
+		return card
+	}
+	L.ArgError(n, "card expected")
+	return nil
+}
+
+// checkTail gets a table of cards from a script, as passed to TailTapped
+func (self *Scripted) checkTail(L *lua.LState, n int) []*Card {
+	t := L.CheckTable(n)
+	var tail []*Card
+	for i := 1; i <= t.Len(); i++ {
+		ud, ok := t.RawGetInt(i).(*lua.LUserData)
+		if !ok {
+			L.ArgError(n, "table of cards expected")
+		}
+		card, ok := ud.Value.(*Card)
+		if !ok || card.Owner() == nil {
+			L.ArgError(n, "table of cards expected")
+		}
+		tail = append(tail, card)
+	}
+	if len(tail) == 0 {
+		L.ArgError(n, "no cards")
+	}
+	return tail
+}
+
+// pileValue wraps a pile for a script; the same pile is always the same value, so scripts can compare piles with ==
+func (self *Scripted) pileValue(L *lua.LState, pile *Pile) lua.LValue {
+	if pile == nil {
+		return lua.LNil
+	}
+	ud, ok := self.luaPiles[pile]
+	if !ok {
+		ud = L.NewUserData()
+		ud.Value = pile
+		L.SetMetatable(ud, L.GetTypeMetatable(luaPile))
+		self.luaPiles[pile] = ud
+	}
+	return ud
+}
+
+// cardValue wraps a card for a script; the same card is always the same value
+func (self *Scripted) cardValue(L *lua.LState, card *Card) lua.LValue {
+	if card == nil {
+		return lua.LNil
+	}
+	ud, ok := self.luaCards[card]
+	if !ok {
+		ud = L.NewUserData()
+		ud.Value = card
+		L.SetMetatable(ud, L.GetTypeMetatable(luaCard))
+		self.luaCards[card] = ud
+	}
+	return ud
+}
+
+func (self *Scripted) tailValue(L *lua.LState, tail []*Card) *lua.LTable {
+	t := L.CreateTable(len(tail), 0)
+	for _, card := range tail {
+		t.Append(self.cardValue(L, card))
+	}
+	return t
+}
+
+func (self *Scripted) pushPile(L *lua.LState, pile *Pile) int {
+	L.Push(self.pileValue(L, pile))
+	return 1
+}
+
+func (self *Scripted) pushPiles(L *lua.LState, piles []*Pile) int {
+	t := L.CreateTable(len(piles), 0)
+	for _, pile := range piles {
+		t.Append(self.pileValue(L, pile))
+	}
+	L.Push(t)
+	return 1
+}
@@ -0,0 +1,136 @@
+package sol
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// addTestScript adds a variant from a script in testdata/variants, for the length of a test
+func addTestScript(t *testing.T, fname string, data []byte) *Scripted {
+	t.Helper()
+	if data == nil {
+		var err error
+		if data, err = os.ReadFile(filepath.Join("testdata", "variants", fname)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s, err := ParseVariantScript(fname, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	groups := make(map[string][]string)
+	for k, v := range VariantGroups {
+		groups[k] = v
+	}
+	if err := AddVariantScript(s); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		delete(Variants, s.name)
+		VariantGroups = groups
+	})
+	return s
+}
+
+// TestScriptedVariants checks that the example scripts deal and play just like the variants they copy
+func TestScriptedVariants(t *testing.T) {
+	for _, tc := range []struct{ fname, variant string }{
+		{"antares.lua", "Antares"},
+		{"usk.lua", "Usk"},
+	} {
+		name := addTestScript(t, tc.fname, nil).name
+		settings := DefaultSettings()
+		settings.AutoCollect = false
+		for seed := uint64(1); seed <= 3; seed++ {
+			scripted, compiled := NewBaize(name, settings), NewBaize(tc.variant, settings)
+			scripted.StartFreshGame()
+			compiled.StartFreshGame()
+			scripted.NewDealFromSeed(seed)
+			compiled.NewDealFromSeed(seed)
+			got, want := playRandomly(scripted, 50, seed), playRandomly(compiled, 50, seed)
+			if len(got) != len(want) {
+				t.Errorf("%s deal %d made %d moves, %s made %d", name, seed, len(got)-1, tc.variant, len(want)-1)
+				continue
+			}
+			for i := range got {
+				if got[i] != want[i] {
+					t.Errorf("%s deal %d went a different way to %s at move %d", name, seed, tc.variant, i)
+					break
+				}
+			}
+			// the Usk redeal
+			scripted.PileTapped(scripted.script.Stock())
+			compiled.PileTapped(compiled.script.Stock())
+			if scripted.Hash() != compiled.Hash() || scripted.Recycles() != compiled.Recycles() {
+				t.Errorf("%s deal %d is different to %s after tapping the stock", name, seed, tc.variant)
+			}
+			if errs := scripted.script.(*Scripted).reported; len(errs) > 0 {
+				t.Errorf("%s reported %v", name, errs)
+			}
+		}
+	}
+}
+
+func TestBadScripts(t *testing.T) {
+	for _, tc := range []struct{ script, want string }{
+		{`Name = "Bad" function BuildPiles( end`, "bad.lua line:1"},
+		{`Name = "Bad"`, "There must be a BuildPiles function"},
+		{`function BuildPiles() end function StartGame() end`, "Name is missing"},
+		{`Name = "../evil" function BuildPiles() end function StartGame() end`, "Name can't contain"},
+		{`Name = "Bad" Suits = 3 function BuildPiles() end function StartGame() end`, "Suits must be one of"},
+		{`Name = "Bad" Jokers = 5 function BuildPiles() end function StartGame() end`, "Jokers must be one of"},
+		{`Name = "Bad" TailTapped = 1 function BuildPiles() end function StartGame() end`, "TailTapped must be a function"},
+		{`Name = "Bad" NewStock(0, 0)`, "can only be used inside a function"},
+		// the sandbox
+		{`Name = "Bad" dofile("/etc/passwd")`, "attempt to call a non-function object"},
+		{`Name = "Bad" io.open("/etc/passwd")`, "attempt to index a non-table object(nil)"},
+		{`Name = "Bad" os.exit(1)`, "attempt to index a non-table object(nil)"},
+	} {
+		_, err := ParseVariantScript("bad.lua", []byte(tc.script))
+		if err == nil {
+			t.Errorf("%s was accepted, want error containing '%s'", tc.script, tc.want)
+		} else if !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s gave error '%s', want '%s'", tc.script, err, tc.want)
+		}
+	}
+}
+
+// TestScriptErrors checks that a script that goes wrong while being played is reported, rather than stopping the game
+func TestScriptErrors(t *testing.T) {
+	defer func(d time.Duration) { scriptTimeout = d }(scriptTimeout)
+	scriptTimeout = 50 * time.Millisecond
+
+	s := addTestScript(t, "broken.lua", []byte(`
+Name = "Broken"
+function BuildPiles()
+	NewFoundation(0, 0)
+	NewTableau(0, 1, "Sideways")
+end
+function StartGame()
+	MoveCard(Stock(), Foundations()[1])
+end
+function TailMoveError(tail)
+	while true do end
+end
+`))
+	b := NewBaize(s.name, nil)
+	b.StartFreshGame()
+	if b.script.Stock() == nil || b.script.Stock().Len() != 51 {
+		t.Fatal("no stock was made for a script that didn't make one, or the game didn't start")
+	}
+	if ok, err := b.script.TailMoveError(b.script.Stock().cards[:1]); ok || err == nil || !strings.Contains(err.Error(), "took too long") {
+		t.Errorf("a script stuck in a loop gave %v, %v", ok, err)
+	}
+	var reported []string
+	for msg := range b.script.(*Scripted).reported {
+		reported = append(reported, msg)
+	}
+	for _, want := range []string{"fan must be one of", "there must be a Stock", "took too long"} {
+		if !strings.Contains(strings.Join(reported, "\n"), want) {
+			t.Errorf("'%s' was not reported in %v", want, reported)
+		}
+	}
+}
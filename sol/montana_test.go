@@ -0,0 +1,193 @@
+package sol
+
+import (
+	"testing"
+
+	"oddstream.games/gosol/cardid"
+)
+
+// makeGap moves the card in a space to one of the other gaps (but not one to keep), so that the space becomes a gap
+func makeGap(b *Baize, p *Pile, keep ...*Pile) {
+	for _, g := range b.script.Tableaux() {
+		if g == p || !g.Empty() {
+			continue
+		}
+		kept := false
+		for _, k := range keep {
+			kept = kept || k == g
+		}
+		if !kept {
+			MoveCard(p, g)
+			return
+		}
+	}
+}
+
+func TestMontanaDeal(t *testing.T) {
+	for _, tc := range []struct {
+		variant      string
+		spaces, grid int
+	}{
+		{"Montana", 52, 48},
+		{"Gaps", 52, 48},
+		{"Blue Moon", 56, 52},
+	} {
+		b := NewBaize(tc.variant, nil)
+		b.StartFreshGame()
+		m := b.script.(*Montana)
+		var cards, gaps int
+		for _, p := range m.tableaux {
+			cards += p.Len()
+			if p.Empty() {
+				gaps++
+				if p.Left() == nil && m.keepAces {
+					t.Errorf("%s dealt a gap at the start of a row", tc.variant)
+				}
+			}
+		}
+		if len(m.tableaux) != tc.spaces || cards != tc.grid || gaps != 4 {
+			t.Errorf("%s has %d spaces, %d cards and %d gaps, want %d, %d and 4", tc.variant, len(m.tableaux), cards, gaps, tc.spaces, tc.grid)
+		}
+		if b.Recycles() != 2 {
+			t.Errorf("%s has %d redeals, want 2", tc.variant, b.Recycles())
+		}
+		if err := b.Validate(); err != nil {
+			t.Errorf("%s: %s", tc.variant, err)
+		}
+	}
+}
+
+func TestMontanaGaps(t *testing.T) {
+	b := NewBaize("Montana", nil)
+	b.StartFreshGame()
+	m := b.script.(*Montana)
+	row0, row1, row2 := m.row(0), m.row(1), m.row(2)
+	putCard(b, row0[3], cardid.SPADE, 5)
+	makeGap(b, row0[4])
+	putCard(b, row2[5], cardid.CLUB, 13)
+	makeGap(b, row2[6], row0[4])
+	makeGap(b, row1[0], row0[4], row2[6])
+	b.UndoPush()
+	before := b.Hash()
+
+	if ok, _ := b.DropTail([]*Card{findCard(b, cardid.HEART, 6)}, row0[4]); ok {
+		t.Error("a Six of Hearts went next to a Five of Spades")
+	}
+	if ok, _ := b.DropTail([]*Card{findCard(b, cardid.SPADE, 7)}, row0[4]); ok {
+		t.Error("a Seven of Spades went next to a Five of Spades")
+	}
+	if ok, _ := b.DropTail([]*Card{findCard(b, cardid.HEART, 3)}, row1[0]); ok {
+		t.Error("a Three started a row")
+	}
+	if ok, _ := b.DropTail([]*Card{findCard(b, cardid.CLUB, 2)}, row2[6]); ok {
+		t.Error("a card went next to a King")
+	}
+	if ok, _ := b.DropTail([]*Card{findCard(b, cardid.SPADE, 6)}, row0[3]); ok {
+		t.Error("a card was moved to a space that wasn't a gap")
+	}
+	if b.Hash() != before {
+		t.Fatal("a move that wasn't allowed changed the baize")
+	}
+
+	six := findCard(b, cardid.SPADE, 6)
+	if ok, err := b.DropTail([]*Card{six}, row0[4]); !ok {
+		t.Errorf("a Six of Spades could not go next to a Five of Spades: %s", err)
+	}
+	b.Undo()
+	if b.Hash() != before {
+		t.Error("undo did not put the Six of Spades back")
+	}
+
+	// a Two that isn't already at the start of a row
+	var two *Card
+	for suit := cardid.CLUB; suit <= cardid.SPADE && two == nil; suit++ {
+		if c := findCard(b, suit, 2); c.Owner().Left() != nil {
+			two = c
+		}
+	}
+	b.FindDestinations()
+	if two != nil && (!b.TailTapped([]*Card{two}) || two.Owner().Left() != nil) {
+		t.Error("tapping a Two did not move it to the start of a row")
+	}
+}
+
+func TestMontanaRedeal(t *testing.T) {
+	for _, variant := range []string{"Montana", "Gaps", "Blue Moon"} {
+		b := NewBaize(variant, nil)
+		b.StartFreshGame()
+		m := b.script.(*Montana)
+		first := m.firstOrdinal()
+		row := m.row(0)
+		putCard(b, row[0], cardid.SPADE, first)
+		putCard(b, row[1], cardid.SPADE, first+1)
+		b.UndoPush()
+		before := b.Hash()
+
+		if !b.PileTapped(b.script.Stock()) {
+			t.Fatalf("%s: tapping the stock did not redeal", variant)
+		}
+		if b.Recycles() != 1 {
+			t.Errorf("%s has %d redeals left after a redeal, want 1", variant, b.Recycles())
+		}
+		if c := row[1].Peek(); c == nil || c.Suit() != cardid.SPADE || c.Ordinal() != first+1 {
+			t.Errorf("%s: a card in order was redealt", variant)
+		}
+		var cards, gaps int
+		for r := 0; r < 4; r++ {
+			for _, p := range m.row(r) {
+				cards += p.Len()
+				if p.Empty() {
+					gaps++
+				}
+			}
+			if !m.randomGaps && !m.row(r)[m.sortedCards(m.row(r))].Empty() {
+				t.Errorf("%s: a gap was not left after the cards in order in row %d", variant, r)
+			}
+		}
+		if gaps != 4 || b.script.Stock().Len() != 0 {
+			t.Errorf("%s: redeal left %d gaps and %d cards in the stock", variant, gaps, b.script.Stock().Len())
+		}
+		if err := b.Validate(); err != nil {
+			t.Errorf("%s: %s", variant, err)
+		}
+		after := b.Hash()
+		b.Undo()
+		if b.Hash() != before || b.Recycles() != 2 {
+			t.Errorf("%s: undo did not take back the redeal", variant)
+		}
+		b.PileTapped(b.script.Stock())
+		if b.Hash() != after {
+			t.Errorf("%s: redealing the same cards again dealt them differently", variant)
+		}
+	}
+}
+
+func TestMontanaComplete(t *testing.T) {
+	b := NewBaize("Montana", nil)
+	b.StartFreshGame()
+	m := b.script.(*Montana)
+	var cards []*Card
+	for _, p := range m.tableaux {
+		cards = append(cards, p.cards...)
+		p.cards = nil
+	}
+	for _, c := range cards {
+		// the row for each suit, in order of rank, leaving a gap at the end
+		p := m.row(c.Suit() - cardid.CLUB)[c.Ordinal()-2]
+		p.cards = []*Card{c}
+		c.SetOwner(p)
+	}
+	for _, p := range m.tableaux {
+		p.rehash()
+	}
+	if !b.Complete() || b.PercentComplete() != 100 {
+		t.Errorf("Montana with every row in order is not complete, %d%%", b.PercentComplete())
+	}
+	p5, p6 := m.row(3)[5], m.row(3)[6]
+	p5.cards, p6.cards = p6.cards, p5.cards
+	p5.cards[0].SetOwner(p5)
+	p6.cards[0].SetOwner(p6)
+	if b.Complete() {
+		t.Error("Montana with two cards swapped is complete")
+	}
+}
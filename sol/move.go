@@ -0,0 +1,88 @@
+package sol
+
+//lint:file-ignore ST1006 Receiver name will be anything I like, thank you
+
+import "fmt"
+
+// Move is something a player can do to a Baize:
+// drag the top N cards of pile Src onto pile Dst,
+// tap the top N cards of pile Src (Dst < 0),
+// or tap pile Src itself (Dst < 0 and N == 0).
+//
+// Piles are referred to by their index in Baize.Piles(), rather than by pointer,
+// so a Move still means the same thing after the Baize has been rebuilt
+// from a SavableBaize, or on a different Baize holding the same position.
+type Move struct {
+	Src, N, Dst int
+}
+
+func (m Move) String() string {
+	switch {
+	case m.Dst >= 0:
+		return fmt.Sprintf("%d cards from %d to %d", m.N, m.Src, m.Dst)
+	case m.N > 0:
+		return fmt.Sprintf("tap %d cards of %d", m.N, m.Src)
+	default:
+		return fmt.Sprintf("tap %d", m.Src)
+	}
+}
+
+// LegalMoves returns every move that would change the baize:
+// all the tails that can be dragged somewhere, the cards that can be removed (see pairMoves), and a tap on the stock.
+// Pointless moves, like shifting a whole pile onto an identical empty pile, are left out.
+func (b *Baize) LegalMoves() []Move {
+	var moves []Move
+	for _, mt := range b.findAllMovableTails() {
+		src := mt.tail[0].Owner()
+		if src.IsStock() {
+			// cards are dealt from the Stock by tapping it, not by dragging them
+			continue
+		}
+		if mt.dst.Len() == 0 && len(mt.tail) == src.Len() {
+			if src.interchangeable(mt.dst) {
+				continue
+			}
+		}
+		moves = append(moves, Move{Src: src.index, N: len(mt.tail), Dst: mt.dst.index})
+	}
+	moves = append(moves, b.pairMoves()...)
+	if stock := b.script.Stock(); stock != nil && !stock.Hidden() {
+		if stock.Empty() {
+			if b.recycles > 0 {
+				moves = append(moves, Move{Src: stock.index, Dst: -1})
+			}
+		} else {
+			moves = append(moves, Move{Src: stock.index, N: 1, Dst: -1})
+		}
+	}
+	return moves
+}
+
+// ApplyMove makes a move using the same path as a player's drag or tap,
+// so the script's rules, AfterMove, undo and (if enabled) auto collect all happen.
+// Returns true if the baize changed.
+func (b *Baize) ApplyMove(m Move) bool {
+	if m.Src < 0 || m.Src >= len(b.piles) || m.Dst >= len(b.piles) {
+		return false
+	}
+	src := b.piles[m.Src]
+	if m.N > src.Len() {
+		return false
+	}
+	if m.N == 0 {
+		return b.PileTapped(src)
+	}
+	tail := src.cards[src.Len()-m.N:]
+	if m.Dst < 0 {
+		return b.TailTapped(tail)
+	}
+	dst := b.piles[m.Dst]
+	if dst == src {
+		return false
+	}
+	hash := b.Hash()
+	if ok, _ := b.DropTail(tail, dst); !ok {
+		return false
+	}
+	return hash != b.Hash()
+}
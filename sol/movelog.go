@@ -0,0 +1,227 @@
+package sol
+
+//lint:file-ignore ST1006 Receiver name will be anything I like, thank you
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+
+	"oddstream.games/gosol/cardid"
+)
+
+// SnapshotInterval is how many moves apart the move log keeps a copy of the whole baize,
+// so going back a long way (eg restarting a deal) doesn't mean taking back every move
+const SnapshotInterval = 50
+
+// MoveLog is a game: the baize as it was dealt, followed by what each move did to it.
+// A copy of the whole baize is also kept every Interval moves.
+type MoveLog struct {
+	Interval   int
+	Seed       uint64          `json:",omitempty"`
+	Winnable   bool            `json:",omitempty"`
+	Difficulty *Difficulty     `json:",omitempty"`
+	Hints      int             `json:",omitempty"`
+	Bookmark   int             `json:",omitempty"` // only in games saved before bookmarks had names
+	Bookmarks  []Bookmark      `json:",omitempty"`
+	Snapshots  []*SavableBaize // Snapshots[i] is the baize after i*Interval moves
+	Steps      []*Step         `json:",omitempty"`
+	Redo       []*Step         `json:",omitempty"` // moves undone that can be made again, the next one last
+	Branches   []SavedBranch   `json:",omitempty"`
+}
+
+// Step is what one move did to the baize, in enough detail to do it again, or take it back.
+// Most moves shift a tail of cards from one pile to another, and perhaps turn a card over;
+// anything else a script does (eg deal a card onto every pile) is recorded
+// as the cards that were replaced at the top of each pile.
+type Step struct {
+	Moves    []Move      `json:",omitempty"` // tails moved, in order
+	Cards    []StepCards `json:",omitempty"` // then the cards replaced
+	Flips    []StepFlip  `json:",omitempty"` // then the cards turned over
+	Labels   []StepLabel `json:",omitempty"`
+	Recycles int         `json:",omitempty"` // change in the number of recycles
+}
+
+// StepCards records the cards above the bottom Keep cards of a pile being replaced
+type StepCards struct {
+	Pile, Keep int
+	Old, New   []cardid.CardID `json:",omitempty"`
+}
+
+// StepFlip records a card being turned over
+type StepFlip struct {
+	Pile, Index int
+}
+
+// StepLabel records the label of a pile changing
+type StepLabel struct {
+	Pile     int
+	Old, New string `json:",omitempty"`
+}
+
+// kept returns how many cards at the bottom of two piles are the same, face up or face down
+func kept(from, to []cardid.CardID) int {
+	var n int
+	for n < len(from) && n < len(to) && cardid.SameCardAndPack(from[n], to[n]) {
+		n++
+	}
+	return n
+}
+
+func cloneIDs(ids []cardid.CardID) []cardid.CardID {
+	return append([]cardid.CardID(nil), ids...)
+}
+
+func moveIDs(piles [][]cardid.CardID, src, n, dst int) {
+	tail := piles[src][len(piles[src])-n:]
+	piles[dst] = append(piles[dst], tail...)
+	piles[src] = piles[src][:len(piles[src])-n]
+}
+
+// diffPositions works out the Step that turns one position into the next
+func diffPositions(from, to *SavableBaize) *Step {
+	st := &Step{Recycles: to.Recycles - from.Recycles}
+	work := make([][]cardid.CardID, len(from.Piles))
+	for i, sp := range from.Piles {
+		work[i] = cloneIDs(sp.Cards)
+	}
+
+	// look for piles that have only gained cards, which another pile had on top
+	for dst := range work {
+		want := to.Piles[dst].Cards
+		k := kept(work[dst], want)
+		if k != len(work[dst]) || k == len(want) {
+			continue
+		}
+		added := want[k:]
+		for src := range work {
+			n := len(added)
+			if src == dst || len(work[src]) < n {
+				continue
+			}
+			if kept(work[src][len(work[src])-n:], added) == n && kept(work[src], to.Piles[src].Cards) <= len(work[src])-n {
+				st.Moves = append(st.Moves, Move{Src: src, N: n, Dst: dst})
+				moveIDs(work, src, n, dst)
+				break
+			}
+		}
+	}
+
+	// whatever else has changed
+	for i := range work {
+		want := to.Piles[i].Cards
+		if k := kept(work[i], want); k < len(work[i]) || k < len(want) {
+			st.Cards = append(st.Cards, StepCards{Pile: i, Keep: k, Old: cloneIDs(work[i][k:]), New: cloneIDs(want[k:])})
+			work[i] = append(work[i][:k], want[k:]...)
+		}
+		for j := range want {
+			if work[i][j] != want[j] {
+				st.Flips = append(st.Flips, StepFlip{Pile: i, Index: j})
+			}
+		}
+		if from.Piles[i].Label != to.Piles[i].Label {
+			st.Labels = append(st.Labels, StepLabel{Pile: i, Old: from.Piles[i].Label, New: to.Piles[i].Label})
+		}
+	}
+	return st
+}
+
+// applyTo returns the position this step leads to from sb, leaving sb alone,
+// or false if the step doesn't fit sb
+func (st *Step) applyTo(sb *SavableBaize) (*SavableBaize, bool) {
+	work := make([][]cardid.CardID, len(sb.Piles))
+	for i, sp := range sb.Piles {
+		work[i] = cloneIDs(sp.Cards)
+	}
+	var changed = make(map[int]bool)
+	inRange := func(pile int) bool { return pile >= 0 && pile < len(work) }
+
+	for _, m := range st.Moves {
+		if !inRange(m.Src) || !inRange(m.Dst) || m.N < 1 || m.N > len(work[m.Src]) {
+			return nil, false
+		}
+		moveIDs(work, m.Src, m.N, m.Dst)
+		changed[m.Src], changed[m.Dst] = true, true
+	}
+	for _, sc := range st.Cards {
+		if !inRange(sc.Pile) || sc.Keep < 0 || sc.Keep > len(work[sc.Pile]) {
+			return nil, false
+		}
+		if old := work[sc.Pile][sc.Keep:]; len(old) != len(sc.Old) || kept(old, sc.Old) != len(old) {
+			return nil, false
+		}
+		work[sc.Pile] = append(work[sc.Pile][:sc.Keep], sc.New...)
+		changed[sc.Pile] = true
+	}
+	for _, f := range st.Flips {
+		if !inRange(f.Pile) || f.Index < 0 || f.Index >= len(work[f.Pile]) {
+			return nil, false
+		}
+		cid := work[f.Pile][f.Index]
+		work[f.Pile][f.Index] = cid.SetProne(!cid.Prone())
+		changed[f.Pile] = true
+	}
+	var labels = make(map[int]string)
+	for _, l := range st.Labels {
+		if !inRange(l.Pile) {
+			return nil, false
+		}
+		labels[l.Pile] = l.New
+	}
+
+	next := &SavableBaize{Recycles: sb.Recycles + st.Recycles, Seed: sb.Seed}
+	for i, sp := range sb.Piles {
+		label, relabelled := labels[i]
+		if !changed[i] && !relabelled {
+			next.Piles = append(next.Piles, sp) // positions are never changed, so can share piles
+			continue
+		}
+		if !relabelled {
+			label = sp.Label
+		}
+		next.Piles = append(next.Piles, &SavablePile{Category: sp.Category, Label: label, Cards: work[i]})
+	}
+	return next, true
+}
+
+// moveLogFromUndoStack converts a game saved before there was a move log,
+// when a copy of the whole baize was saved for every move
+func moveLogFromUndoStack(undoStack []*SavableBaize) *MoveLog {
+	top := undoStack[len(undoStack)-1]
+	ml := &MoveLog{
+		Interval:   SnapshotInterval,
+		Seed:       top.Seed,
+		Winnable:   top.Winnable,
+		Difficulty: top.Difficulty,
+		Hints:      top.Hints,
+		Bookmark:   top.Bookmark,
+		Snapshots:  []*SavableBaize{undoStack[0]},
+	}
+	for i := 1; i < len(undoStack); i++ {
+		ml.Steps = append(ml.Steps, diffPositions(undoStack[i-1], undoStack[i]))
+		if i%SnapshotInterval == 0 {
+			ml.Snapshots = append(ml.Snapshots, undoStack[i])
+		}
+	}
+	return ml
+}
+
+// unmarshalMoveLog reads a saved game, which may be a move log,
+// or an undo stack saved before there was a move log
+func unmarshalMoveLog(data []byte) (*MoveLog, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		var undoStack []*SavableBaize
+		if err := json.Unmarshal(data, &undoStack); err != nil {
+			return nil, err
+		}
+		if len(undoStack) == 0 {
+			return nil, errors.New("empty undo stack")
+		}
+		return moveLogFromUndoStack(undoStack), nil
+	}
+	var ml MoveLog
+	if err := json.Unmarshal(data, &ml); err != nil {
+		return nil, err
+	}
+	return &ml, nil
+}
@@ -0,0 +1,154 @@
+package sol
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// playRandomly makes up to n random moves, and returns the Hash of each position, starting with the deal
+func playRandomly(b *Baize, n int, seed uint64) []uint64 {
+	p := NewRandomPlayer(seed)
+	keys := []uint64{b.Hash()}
+	for i := 0; i < n && !b.Complete(); i++ {
+		m, ok := p.ChooseMove(b)
+		if !ok || !b.ApplyMove(m) {
+			break
+		}
+		keys = append(keys, b.Hash())
+	}
+	return keys
+}
+
+// makeDifferentMove makes the first legal move that doesn't lead to the position with Hash key
+func makeDifferentMove(b *Baize, key uint64) bool {
+	for _, m := range b.LegalMoves() {
+		if !b.ApplyMove(m) {
+			continue
+		}
+		if b.Hash() != key {
+			return true
+		}
+		b.Undo()
+	}
+	return false
+}
+
+func TestMoveLog(t *testing.T) {
+	for v := range Variants {
+		settings := DefaultSettings()
+		settings.AutoCollect = false
+		b := NewBaize(v, settings)
+		b.StartFreshGame()
+		b.NewDealFromSeed(1)
+		keys := playRandomly(b, 3*SnapshotInterval, 1)
+		if b.MovesMade() != len(keys)-1 {
+			t.Fatalf("%s: %d moves made, %d in the log", v, len(keys)-1, b.MovesMade())
+		}
+
+		// every position can be rebuilt from the log
+		other := NewBaize(v, settings)
+		other.StartFreshGame()
+		for i, sav := range b.positions() {
+			other.updateFromSavable(sav)
+			if other.Hash() != keys[i] {
+				t.Fatalf("%s: position %d rebuilt wrongly", v, i)
+			}
+		}
+
+		// the log survives being saved and loaded
+		bytes, err := json.Marshal(b.MoveLog())
+		if err != nil {
+			t.Fatal(err)
+		}
+		ml, err := unmarshalMoveLog(bytes)
+		if err != nil || !other.isMoveLogOk(ml) {
+			t.Fatalf("%s: saved move log is not ok: %v", v, err)
+		}
+		other.SetMoveLog(ml)
+		if other.Hash() != keys[len(keys)-1] || other.MovesMade() != b.MovesMade() {
+			t.Errorf("%s: loaded game is not the saved game", v)
+		}
+
+		// and every move can be taken back
+		for i := len(keys) - 2; i >= 0 && !b.Complete(); i-- {
+			b.Undo()
+			checkCards(t, b)
+			if b.Hash() != keys[i] {
+				t.Fatalf("%s: undo to position %d went wrong", v, i)
+			}
+		}
+	}
+}
+
+func TestOldSavedGame(t *testing.T) {
+	b := NewBaize("Spider Two Suits", nil)
+	b.StartFreshGame()
+	b.NewDealFromSeed(2)
+	keys := playRandomly(b, 2*SnapshotInterval, 2)
+	b.SavePosition()
+
+	// a game saved before the move log was a copy of the baize for every move
+	undoStack := b.positions()
+	undoStack[len(undoStack)-1].Bookmark = len(undoStack)
+	bytes, err := json.Marshal(undoStack)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ml, err := unmarshalMoveLog(bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := NewBaize("Spider Two Suits", nil)
+	other.StartFreshGame()
+	if !other.isMoveLogOk(ml) {
+		t.Fatal("converted move log is not ok")
+	}
+	other.SetMoveLog(ml)
+	if other.Hash() != keys[len(keys)-1] || other.MovesMade() != len(keys)-1 || !other.Bookmarked() {
+		t.Error("converted game is not the saved game")
+	}
+	other.RestartDeal()
+	if other.Hash() != keys[0] {
+		t.Error("restart didn't go back to the deal")
+	}
+}
+
+func TestRedo(t *testing.T) {
+	settings := DefaultSettings()
+	settings.AutoCollect = false
+	b := NewBaize("Klondike", settings)
+	b.StartFreshGame()
+	b.NewDealFromSeed(3)
+	keys := playRandomly(b, 2*SnapshotInterval, 3)
+	moves := len(keys) - 1
+	b.RewindTo(moves / 2)
+	b.SavePosition()
+	b.RestartDeal()
+	if b.MovesMade() != 0 || b.Hash() != keys[0] {
+		t.Fatal("restart didn't go back to the deal")
+	}
+
+	// redo as far as the bookmark, then the rest of the way
+	b.LoadPosition()
+	if b.MovesMade() != moves/2 || b.Hash() != keys[moves/2] {
+		t.Fatalf("bookmark went to move %d, want %d", b.MovesMade(), moves/2)
+	}
+	for i := moves/2 + 1; i <= moves; i++ {
+		b.Redo()
+		checkCards(t, b)
+		if b.Hash() != keys[i] {
+			t.Fatalf("redo to position %d went wrong", i)
+		}
+	}
+	if b.CanRedo() {
+		t.Error("more to redo after the last move")
+	}
+
+	// a different move means nothing can be redone, and a bookmark in the moves taken back goes with them
+	b.Undo()
+	b.RewindTo(moves/2 - 1)
+	makeDifferentMove(b, keys[moves/2])
+	if b.CanRedo() || b.Bookmarked() {
+		t.Error("redo or bookmark survived a new move")
+	}
+}
@@ -0,0 +1,84 @@
+package sol
+
+//lint:file-ignore ST1006 Receiver name will be anything I like, thank you
+
+import (
+	"log"
+
+	"oddstream.games/gosol/cardid"
+)
+
+// Microsoft FreeCell deals are numbered, and players swap numbers (e.g. #11982 can't be won),
+// so Freecell-like variants can reproduce them exactly instead of using Pile.Shuffle.
+// The shuffle is the C runtime's rand() linear congruential generator,
+// seeded with the deal number, as extended by FreeCell Pro for numbers from 2^31
+// (and copied by PySolFC's LCRandom31).
+// https://rosettacode.org/wiki/Deal_cards_for_FreeCell
+
+// msRand is the Microsoft C runtime's rand()
+type msRand struct {
+	seed uint64
+	deal uint64
+}
+
+func newMsRand(deal uint64) *msRand {
+	if deal >= 0x100000000 {
+		return &msRand{seed: deal - 0x100000000, deal: deal}
+	}
+	return &msRand{seed: deal, deal: deal}
+}
+
+func (r *msRand) next() uint64 {
+	r.seed = (r.seed*214013 + 2531011) & 0x3ffffffff
+	switch {
+	case r.deal >= 0x100000000:
+		return ((r.seed >> 16) & 0xffff) + 1
+	case r.deal >= 0x80000000:
+		return ((r.seed >> 16) & 0x7fff) | 0x8000
+	default:
+		return (r.seed >> 16) & 0x7fff
+	}
+}
+
+// msDealOrder returns the order in which Microsoft FreeCell deals cards for a deal number,
+// with each card given as ordinal*4 + suit, counting from zero, and suits in the order clubs, diamonds, hearts, spades.
+// Cards are dealt left to right, a row at a time.
+func msDealOrder(deal uint64) []int {
+	const n = 52
+	var cards [n]int
+	for i := 0; i < n; i++ {
+		cards[i] = n - 1 - i
+	}
+	r := newMsRand(deal)
+	for i := 0; i < n; i++ {
+		j := n - 1 - int(r.next()%uint64(n-i))
+		cards[i], cards[j] = cards[j], cards[i]
+	}
+	return cards[:]
+}
+
+// ShuffleMicrosoft puts a pack of 52 cards into the order that Microsoft FreeCell would deal them,
+// so that popping cards off this pile one at a time gives the same deal.
+func (self *Pile) ShuffleMicrosoft(deal uint64) {
+	if self.Len() != 52 {
+		log.Printf("Cannot make Microsoft deal #%d from %d cards", deal, self.Len())
+		return
+	}
+	var msIndex = func(c *Card) int {
+		// cardid suits are CLUB=1, DIAMOND=2, HEART=3, SPADE=4, same order as Microsoft's
+		return (c.Ordinal()-1)*4 + c.Suit() - cardid.CLUB
+	}
+	var byIndex [52]*Card
+	for _, c := range self.cards {
+		byIndex[msIndex(c)] = c
+	}
+	order := msDealOrder(deal)
+	for i, idx := range order {
+		// the first card dealt must be on top of the pile
+		self.cards[len(order)-1-i] = byIndex[idx]
+	}
+	self.rehash()
+	if DebugMode {
+		log.Printf("Shuffled %d cards as Microsoft deal #%d", self.Len(), deal)
+	}
+}
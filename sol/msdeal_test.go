@@ -0,0 +1,77 @@
+package sol
+
+import (
+	"strings"
+	"testing"
+)
+
+// Microsoft FreeCell layouts, rows of cards dealt left to right. Deals 1 and 617 are the ones published
+// on Rosetta Code; 11982 is the well known deal that can't be won. The deals above 2^31 and 2^32,
+// which FreeCell Pro numbers differently, were made by a transcription into Python of the
+// LCRandom31 class in pysol_cards' random.py, which gives the same layouts for 1, 617 and 11982.
+var msKnownDeals = []struct {
+	deal   uint64
+	layout string
+}{
+	{1, `JD 2D 9H JC 5D 7H 7C 5H
+		KD KC 9S 5S AD QC KH 3H
+		2S KS 9D QD JS AS AH 3C
+		4C 5C TS QH 4H AC 4D 7S
+		3S TD 4S TH 8H 2C JH 7D
+		6D 8S 8D QS 6C 3D 8C TC
+		6S 9C 2H 6H`},
+	{617, `7D AD 5C 3S 5S 8C 2D AH
+		TD 7S QD AC 6D 8H AS KH
+		TH QC 3H 9D 6S 8D 3D TC
+		KD 5H 9S 3C 8S 7H 4D JS
+		4C QS 9C 9H 7C 6H 2C 2S
+		4S TS 2H 5D JC 6C JH QH
+		JD KS KC 4H`},
+	{11982, `AH AS 4H AC 2D 6S TS JS
+		3D 3H QS QC 8S 7H AD KS
+		KD 6H 5S 4D 9H JH 9S 3C
+		JC 5D 5C 8C 9D TD KH 7C
+		6C 2C TH QH 6D TC 4S 7S
+		JD 7D 8H 9C 2H QD 4C 5H
+		KC 8D 2S 3S`},
+	{3000000000, `8D 4D 9H 9D 6H 9C 6C 8C
+		TS QS KH 5D 2S 7C 3H AH
+		JS TH QH 8S 7H QC 8H 2H
+		TD AD 4C 4H 3D 7S AC 5H
+		JH 4S 5C KS KC QD 6D 2D
+		JD TC KD 6S 2C 7D 3S 5S
+		JC 3C AS 9S`},
+	{6000000000, `2D 3D 4D KH TD QH 5C 6D
+		2C AH JS 3H 7C 9H 5H QC
+		QS 2H AD KS 9C 9D 2S 8S
+		8D 4H 6S AS 7H 5S KC TH
+		KD TS JH TC 3C 7S 9S 7D
+		8C 6H JC 5D 3S 6C 4S 8H
+		4C QD JD AC`},
+}
+
+func msCardName(c *Card) string {
+	return string("A23456789TJQK"[c.Ordinal()-1]) + string("CDHS"[c.Suit()-1])
+}
+
+func TestMicrosoftDeals(t *testing.T) {
+	for _, v := range []string{"Freecell", "Baker's Game"} {
+		b := NewBaize(v, nil)
+		b.StartFreshGame()
+		for _, kd := range msKnownDeals {
+			b.NewDealFromSeed(kd.deal)
+			checkCards(t, b)
+			tabs := b.script.Tableaux()
+			for i, name := range strings.Fields(kd.layout) {
+				row, col := i/len(tabs), i%len(tabs)
+				cards := tabs[col].Cards()
+				if row >= len(cards) {
+					t.Fatalf("%s deal #%d: column %d has only %d cards", v, kd.deal, col, len(cards))
+				}
+				if got := msCardName(cards[row]); got != name {
+					t.Errorf("%s deal #%d row %d column %d: got %s, want %s", v, kd.deal, row, col, got, name)
+				}
+			}
+		}
+	}
+}
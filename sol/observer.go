@@ -0,0 +1,57 @@
+package sol
+
+// Observer is implemented by a front end that wants to know what is happening
+// on a Baize, so that it can draw the cards, play sounds and show toasts.
+//
+// A Baize without an Observer is headless; that's how tests and solvers use it.
+type Observer interface {
+	// Toast shows a short message to the player, with a sound effect
+	Toast(soundEffect string, message string)
+	// PlaySound plays a sound effect
+	PlaySound(name string)
+	// CardsChanged is called when cards have been moved between piles, or flipped
+	CardsChanged()
+	// PilesChanged is called when a pile label, or the number of recycles, has changed
+	PilesChanged()
+	// AfterUserMove is called after the Baize has processed a move made by the player
+	AfterUserMove()
+}
+
+// SetObserver attaches an Observer to this Baize; nil makes the Baize headless
+func (b *Baize) SetObserver(observer Observer) {
+	b.observer = observer
+}
+
+// helper functions, so the rest of the package doesn't have to check for a nil observer
+
+func (b *Baize) toast(soundEffect string, message string) {
+	if b.observer != nil {
+		b.observer.Toast(soundEffect, message)
+	}
+}
+
+func (b *Baize) toastError(message string) {
+	b.toast("Error", message)
+}
+
+func (b *Baize) toastInfo(message string) {
+	b.toast("Glass", message)
+}
+
+func (b *Baize) playSound(name string) {
+	if b.observer != nil {
+		b.observer.PlaySound(name)
+	}
+}
+
+func (b *Baize) cardsChanged() {
+	if b.observer != nil {
+		b.observer.CardsChanged()
+	}
+}
+
+func (b *Baize) pilesChanged() {
+	if b.observer != nil {
+		b.observer.PilesChanged()
+	}
+}
@@ -6,18 +6,14 @@ package sol
 import (
 	"errors"
 	"image"
-	"image/color"
-
-	"github.com/fogleman/gg"
-	"github.com/hajimehoshi/ebiten/v2"
 )
 
 type Discard struct {
 	pile *Pile
 }
 
-func NewDiscard(slot image.Point, fanType FanType) *Pile {
-	pile := NewPile("Discard", slot, FAN_NONE, MOVE_NONE)
+func NewDiscard(baize *Baize, slot image.Point, fanType FanType) *Pile {
+	pile := NewPile(baize, "Discard", slot, FAN_NONE, MOVE_NONE)
 	pile.vtable = &Discard{pile: pile}
 	return pile
 }
@@ -29,7 +25,10 @@ func (self *Discard) CanAcceptTail(tail []*Card) (bool, error) {
 	if AnyCardsProne(tail) {
 		return false, errors.New("Cannot move a face down card to a Discard")
 	}
-	if len(tail) != TheGame.Baize.cardCount/len(TheGame.Baize.script.Discards()) {
+	// only whole suits are discarded; a joker stands for a card, so can't be discarded with one
+	var script Scripter = self.pile.baize.script
+	var jokers int = script.Packs() * script.Jokers()
+	if len(NonJokers(tail)) != (self.pile.baize.cardCount-jokers)/len(script.Discards()) {
 		return false, errors.New("Can only move a full set of cards to a Discard")
 	}
 	if ok, err := TailConformant(tail, CardPair.Compare_DownSuit); !ok {
@@ -37,7 +36,7 @@ func (self *Discard) CanAcceptTail(tail []*Card) (bool, error) {
 	}
 	// Scorpion tails can always be moved, but Mrs Mop/Simple Simon tails
 	// must be conformant, so ...
-	return TheGame.Baize.script.TailMoveError(tail)
+	return self.pile.baize.script.TailMoveError(tail)
 }
 
 func (*Discard) TailTapped([]*Card) {
@@ -60,14 +59,3 @@ func (*Discard) UnsortedPairs() int {
 func (*Discard) MovableTails() []*MovableTail {
 	return nil
 }
-
-func (*Discard) Placeholder() *ebiten.Image {
-	dc := gg.NewContext(CardWidth, CardHeight)
-	dc.SetColor(color.NRGBA{255, 255, 255, 31})
-	dc.SetLineWidth(2)
-	// draw the RoundedRect entirely INSIDE the context
-	dc.DrawRoundedRectangle(1, 1, float64(CardWidth-2), float64(CardHeight-2), CardCornerRadius)
-	dc.Fill() // difference for this subpile
-	dc.Stroke()
-	return ebiten.NewImageFromImage(dc.Image())
-}
@@ -5,20 +5,16 @@ package sol
 
 import (
 	"errors"
+	"fmt"
 	"image"
-	"image/color"
-
-	"github.com/fogleman/gg"
-	"github.com/hajimehoshi/ebiten/v2"
-	"oddstream.games/gosol/schriftbank"
 )
 
 type Foundation struct {
 	pile *Pile
 }
 
-func NewFoundation(slot image.Point) *Pile {
-	pile := NewPile("Foundation", slot, FAN_NONE, MOVE_NONE)
+func NewFoundation(baize *Baize, slot image.Point) *Pile {
+	pile := NewPile(baize, "Foundation", slot, FAN_NONE, MOVE_NONE)
 	pile.vtable = &Foundation{pile: pile}
 	return pile
 }
@@ -28,13 +24,22 @@ func (self *Foundation) CanAcceptTail(tail []*Card) (bool, error) {
 	if len(tail) > 1 {
 		return false, errors.New("Cannot move more than one card to a Foundation")
 	}
-	if self.pile.Len() == 13 {
-		return false, errors.New("That Foundation already contains 13 cards")
+	if n := self.capacity(); len(NonJokers(self.pile.cards)) == n {
+		return false, fmt.Errorf("That Foundation already contains %d cards", n)
 	}
 	if AnyCardsProne(tail) {
 		return false, errors.New("Cannot add a face down card to a Foundation")
 	}
-	return TheGame.Baize.script.TailAppendError(self.pile, tail)
+	return self.pile.baize.script.TailAppendError(self.pile, tail)
+}
+
+// capacity is the number of cards (not counting jokers) a Foundation can hold: a suit,
+// or every card, if the script asked for that with SetWholePack
+func (self *Foundation) capacity() int {
+	if self.pile.wholePack {
+		return self.pile.baize.cardCount - self.pile.baize.script.Packs()*self.pile.baize.script.Jokers()
+	}
+	return len(self.pile.baize.script.Ranks())
 }
 
 func (*Foundation) TailTapped([]*Card) {}
@@ -51,17 +56,3 @@ func (*Foundation) UnsortedPairs() int {
 func (*Foundation) MovableTails() []*MovableTail {
 	return nil
 }
-
-func (self *Foundation) Placeholder() *ebiten.Image {
-	dc := gg.NewContext(CardWidth, CardHeight)
-	dc.SetColor(color.NRGBA{255, 255, 255, 31})
-	dc.SetLineWidth(2)
-	// draw the RoundedRect entirely INSIDE the context
-	dc.DrawRoundedRectangle(1, 1, float64(CardWidth-2), float64(CardHeight-2), CardCornerRadius)
-	if self.pile.label != "" {
-		dc.SetFontFace(schriftbank.CardOrdinalLarge)
-		dc.DrawStringAnchored(self.pile.label, float64(CardWidth)*0.5, float64(CardHeight)*0.4, 0.5, 0.5)
-	}
-	dc.Stroke()
-	return ebiten.NewImageFromImage(dc.Image())
-}
@@ -6,16 +6,14 @@ package sol
 import (
 	"errors"
 	"image"
-
-	"github.com/hajimehoshi/ebiten/v2"
 )
 
 type Reserve struct {
 	pile *Pile
 }
 
-func NewReserve(slot image.Point, fanType FanType) *Pile {
-	pile := NewPile("Reserve", slot, fanType, MOVE_ONE)
+func NewReserve(baize *Baize, slot image.Point, fanType FanType) *Pile {
+	pile := NewPile(baize, "Reserve", slot, fanType, MOVE_ONE)
 	pile.vtable = &Reserve{pile: pile}
 	return pile
 }
@@ -47,14 +45,10 @@ func (self *Reserve) MovableTails() []*MovableTail {
 	if self.pile.Len() > 0 {
 		var card *Card = self.pile.Peek()
 		var tail []*Card = []*Card{card}
-		var homes []*Pile = TheGame.Baize.FindHomesForTail(tail)
+		var homes []*Pile = self.pile.baize.FindHomesForTail(tail)
 		for _, home := range homes {
 			tails = append(tails, &MovableTail{dst: home, tail: tail})
 		}
 	}
 	return tails
 }
-
-func (*Reserve) Placeholder() *ebiten.Image {
-	return nil
-}
@@ -0,0 +1,92 @@
+package sol
+
+import (
+	"testing"
+
+	"oddstream.games/gosol/cardid"
+)
+
+// findCard returns a card on the baize, or nil
+func findCard(b *Baize, suit, ord int) *Card {
+	var found *Card
+	b.ForeachCard(func(c *Card) {
+		if found == nil && c.Suit() == suit && c.Ordinal() == ord {
+			found = c
+		}
+	})
+	return found
+}
+
+func TestStrippedPacks(t *testing.T) {
+	for _, tc := range []struct {
+		variant string
+		cards   int
+		ranks   []int
+	}{
+		{"Klondike", 52, standardRanks},
+		{"Piquet Klondike", 32, piquetRanks},
+		{"Tarot Spider", 112, tarotRanks},
+	} {
+		b := NewBaize(tc.variant, nil)
+		b.StartFreshGame()
+		if b.cardCount != tc.cards {
+			t.Errorf("%s has %d cards, want %d", tc.variant, b.cardCount, tc.cards)
+		}
+		var ords [16]int
+		b.ForeachCard(func(c *Card) { ords[c.Ordinal()]++ })
+		for _, ord := range tc.ranks {
+			if ords[ord] == 0 {
+				t.Errorf("%s has no %s", tc.variant, cardid.NewCardID(0, cardid.SPADE, ord))
+			}
+			ords[ord] = 0
+		}
+		for ord, n := range ords {
+			if n != 0 {
+				t.Errorf("%s has %d cards of ordinal %d, which isn't in the pack", tc.variant, n, ord)
+			}
+		}
+		if err := b.Validate(); err != nil {
+			t.Errorf("%s: %s", tc.variant, err)
+		}
+	}
+}
+
+func TestSequencesFollowThePack(t *testing.T) {
+	b := NewBaize("Piquet Klondike", nil)
+	b.StartFreshGame()
+	ace, seven, king := findCard(b, cardid.HEART, 1), findCard(b, cardid.CLUB, 7), findCard(b, cardid.SPADE, 13)
+	if ok, err := (CardPair{seven, ace}).Compare_DownAltColor(); !ok {
+		t.Errorf("an Ace could not go on a Seven in a Piquet pack: %s", err)
+	}
+	if ok, err := (CardPair{ace, seven}).Compare_Up(); !ok {
+		t.Errorf("a Seven could not follow an Ace in a Piquet pack: %s", err)
+	}
+	if ok, err := (CardPair{king, ace}).Compare_UpWrap(); !ok {
+		t.Errorf("an Ace could not wrap onto a King in a Piquet pack: %s", err)
+	}
+
+	f := b.script.Foundations()[0]
+	f.cards = nil
+	b.ForeachCard(func(c *Card) {
+		if c.Suit() == cardid.SPADE {
+			f.cards = append(f.cards, c)
+		}
+	})
+	if ok, _ := f.vtable.CanAcceptTail([]*Card{ace}); ok {
+		t.Error("a Foundation with a whole Piquet suit accepted another card")
+	}
+	f.cards = nil
+
+	b = NewBaize("Tarot Spider", nil)
+	b.StartFreshGame()
+	jack, knight, queen := findCard(b, cardid.SPADE, 11), findCard(b, cardid.SPADE, 14), findCard(b, cardid.SPADE, 12)
+	if ok, err := (CardPair{queen, knight}).Compare_DownSuit(); !ok {
+		t.Errorf("a Knight could not go on a Queen in a Tarot pack: %s", err)
+	}
+	if ok, err := (CardPair{knight, jack}).Compare_DownSuit(); !ok {
+		t.Errorf("a Jack could not go on a Knight in a Tarot pack: %s", err)
+	}
+	if ok, _ := (CardPair{queen, jack}).Compare_DownSuit(); ok {
+		t.Error("a Jack went on a Queen in a Tarot pack")
+	}
+}
@@ -0,0 +1,60 @@
+package sol
+
+// In games like Pyramid, cards aren't built on each other, but removed to a Discard,
+// either in pairs (in Pyramid, two cards that add up to 13) or on their own (a King).
+// The script's PairError says which cards can go; the player drags a card onto the one it
+// pairs with (or onto the Discard, to remove it on its own), or taps it.
+
+// pairMove works out what dropping a card on a pile would remove: the card, and the top card of the pile it pairs with,
+// or just the card, if the pile is a Discard. It returns true if the cards can be removed.
+// If they can't, and the script removes cards, err says why not;
+// if the script doesn't remove cards, or there's nothing on the pile to pair with, err is nil.
+func (b *Baize) pairMove(card *Card, dst *Pile) (other *Card, ok bool, err error) {
+	if dst == card.Owner() || len(b.script.Discards()) == 0 {
+		return nil, false, nil
+	}
+	switch dst.vtable.(type) {
+	case *Discard:
+		// other stays nil, to remove card on its own
+	case *Foundation, *Stock:
+		return nil, false, nil
+	default:
+		if other = dst.Peek(); other == nil {
+			return nil, false, nil
+		}
+	}
+	ok, err = b.script.PairError(card, other)
+	return other, ok, err
+}
+
+// removeCards moves a card, and the card it pairs with (if any), to the first Discard
+func (b *Baize) removeCards(card, other *Card) {
+	dst := b.script.Discards()[0]
+	MoveCard(card.Owner(), dst)
+	if other != nil {
+		MoveCard(other.Owner(), dst)
+	}
+}
+
+// pairMoves returns a move for every card that can be removed, on its own or with another card
+func (b *Baize) pairMoves() []Move {
+	var moves []Move
+	if len(b.script.Discards()) == 0 {
+		return moves // nowhere to remove cards to
+	}
+	for i, src := range b.piles {
+		card := src.Peek()
+		if card == nil || src.IsStock() {
+			continue
+		}
+		if ok, _ := src.CanMoveTail([]*Card{card}); !ok {
+			continue
+		}
+		for j, dst := range b.piles {
+			if _, ok, _ := b.pairMove(card, dst); ok {
+				moves = append(moves, Move{Src: i, N: 1, Dst: j})
+			}
+		}
+	}
+	return moves
+}
@@ -9,9 +9,7 @@ import (
 	"image"
 	"log"
 	"math/rand"
-	"time"
 
-	"github.com/hajimehoshi/ebiten/v2"
 	"oddstream.games/gosol/cardid"
 )
 
@@ -37,22 +35,6 @@ const (
 	MOVE_ONE_OR_ALL
 )
 
-const (
-	CARD_FACE_FAN_FACTOR_V = 3.7
-	CARD_FACE_FAN_FACTOR_H = 4
-	CARD_BACK_FAN_FACTOR   = 8
-)
-
-var DefaultFanFactor [7]float64 = [7]float64{
-	1.0,                    // FAN_NONE
-	CARD_FACE_FAN_FACTOR_V, // FAN_DOWN
-	CARD_FACE_FAN_FACTOR_H, // FAN_LEFT,
-	CARD_FACE_FAN_FACTOR_H, // FAN_RIGHT,
-	CARD_FACE_FAN_FACTOR_V, // FAN_DOWN3,
-	CARD_FACE_FAN_FACTOR_H, // FAN_LEFT3,
-	CARD_FACE_FAN_FACTOR_H, // FAN_RIGHT3,
-}
-
 // MovableTail is used for collecting tap destinations
 type MovableTail struct {
 	dst  *Pile
@@ -67,11 +49,11 @@ type PileVtabler interface {
 	Conformant() bool
 	UnsortedPairs() int
 	MovableTails() []*MovableTail
-	Placeholder() *ebiten.Image
 }
 
 // Pile is a generic container for cards
 type Pile struct {
+	baize     *Baize
 	category  string
 	vtable    PileVtabler
 	label     string
@@ -79,24 +61,23 @@ type Pile struct {
 	fanType   FanType
 	cards     []*Card
 	slot      image.Point // logical position on baize
-	pos       image.Point // actual position on baize
-	pos1      image.Point // waste pos #1
-	pos2      image.Point // waste pos #1
-	fanFactor float64
-	// buddyPos    image.Point
-	img *ebiten.Image
-	// target bool // experimental, might delete later, IDK
+	offset    image.Point // tenths of a slot to shift the pile by, for piles that overlap, eg in a pyramid
+	coveredBy []*Pile     // piles that overlap this one, so its cards can't move until they are empty
+	left      *Pile       // the pile to the left of this one in a grid, eg in Montana, or nil
+	wholePack bool        // a Foundation that takes every card in the pack, rather than a suit, eg in Golf
+	index     int         // where this pile is in the baize's piles
+	hash      uint64      // the cards' keys xored together, see Baize.Hash
 }
 
-func NewPile(category string, slot image.Point, fanType FanType, moveType MoveType) *Pile {
+func NewPile(baize *Baize, category string, slot image.Point, fanType FanType, moveType MoveType) *Pile {
 	var p *Pile = &Pile{
-		category:  category,
-		moveType:  moveType,
-		slot:      slot,
-		fanType:   fanType,
-		fanFactor: DefaultFanFactor[fanType],
+		baize:    baize,
+		category: category,
+		moveType: moveType,
+		slot:     slot,
+		fanType:  fanType,
 	}
-	TheGame.Baize.AddPile(p)
+	baize.AddPile(p)
 	return p
 }
 
@@ -106,7 +87,7 @@ func NewPile(category string, slot image.Point, fanType FanType, moveType MoveTy
 
 func (self *Pile) Reset() {
 	self.cards = self.cards[:0]
-	self.fanFactor = DefaultFanFactor[self.fanType]
+	self.hash = 0
 }
 
 // Hidden returns true if this pile is off screen
@@ -124,6 +105,16 @@ func (self *Pile) Hidden() bool {
 // 	return ok
 // }
 
+// Category returns the kind of pile this is, eg "Foundation"
+func (self *Pile) Category() string {
+	return self.category
+}
+
+// Baize returns the Baize this pile is on
+func (self *Pile) Baize() *Baize {
+	return self.baize
+}
+
 func (self *Pile) IsStock() bool {
 	// using a type assertion seems more idiomatic than a string comparison
 	_, ok := self.vtable.(*Stock)
@@ -131,32 +122,62 @@ func (self *Pile) IsStock() bool {
 	// return self.category == "Stock"
 }
 
-// Fill this pile with a new set of cards. Returns the number of cards added.
-func (self *Pile) Fill(packs, suits int) int {
-	var count int = packs * suits * 13
+// Fill this pile with a new set of cards, and some jokers in each pack.
+// Each suit has the cards in ranks, or Ace to King if ranks is nil.
+// Returns the number of cards added.
+func (self *Pile) Fill(packs, suits int, ranks []int, jokers int) int {
+	if ranks == nil {
+		ranks = standardRanks
+	}
+	if jokers < 0 || jokers > cardid.MaxJokers {
+		// any more and two jokers in a pack would share an id
+		log.Panicf("a pack can have up to %d jokers, not %d", cardid.MaxJokers, jokers)
+	}
+	var count int = packs * (suits*len(ranks) + jokers)
 
 	self.cards = make([]*Card, 0, count)
+	self.hash = 0
 
 	for pack := 0; pack < packs; pack++ {
 		for suit := 0; suit < suits; suit++ {
-			for ord := 1; ord < 14; ord++ {
+			for _, ord := range ranks {
 				// suits are numbered NOSUIT=0, CLUB=1, DIAMOND=2, HEART=3, SPADE=4
 				// (i.e. not 0..3)
 				// run the suits loop backwards, so spades are used first
 				// (folks expect Spider One Suit to use spades)
-				var c Card = NewCard(pack, cardid.SPADE-suit, ord, self.pos)
+				var c Card = NewCard(pack, cardid.SPADE-suit, ord)
 				self.Push(&c)
 			}
 		}
+		for i := 0; i < jokers; i++ {
+			var c Card = Card{id: cardid.NewJokerID(pack, i)}
+			self.Push(&c)
+		}
 	}
 
 	return count
 }
 
+// Shuffle the cards in this pile, using the seed of the baize,
+// so the same seed always gives the same deal
 func (self *Pile) Shuffle() {
-	rand.Seed(time.Now().UTC().UnixNano())
-	rand.Shuffle(self.Len(), self.Swap)
-	log.Printf("Shuffled %d cards", self.Len())
+	if self.baize.PySolDeals() {
+		self.ShufflePySol(self.baize.seed)
+		return
+	}
+	self.ShuffleWithSeed(self.baize.seed)
+}
+
+// ShuffleWithSeed shuffles the cards in this pile, so that the same cards
+// and the same seed always end up in the same order. It is for shuffling
+// part of a pack during a game, where PySolFC deal numbers don't apply.
+func (self *Pile) ShuffleWithSeed(seed uint64) {
+	// math/rand's Source is a fixed algorithm, so this is the same on every platform
+	rng := rand.New(rand.NewSource(int64(seed)))
+	rng.Shuffle(self.Len(), self.Swap)
+	if DebugMode {
+		log.Printf("Shuffled %d cards with seed %d", self.Len(), seed)
+	}
 }
 
 func (self *Pile) Cards() []*Card {
@@ -186,7 +207,7 @@ func (self *Pile) Label() string {
 func (self *Pile) SetLabel(label string) {
 	if self.label != label {
 		self.label = label
-		TheGame.Baize.setFlag(dirtyPileBackgrounds)
+		self.baize.pilesChanged()
 	}
 }
 
@@ -220,7 +241,9 @@ func (self *Pile) Less(i, j int) bool {
 
 // Swap satisfies the sort.Interface interface
 func (self *Pile) Swap(i, j int) {
+	self.hash ^= cardKey(self.cards[i].id, self.index, i) ^ cardKey(self.cards[j].id, self.index, j)
 	self.cards[i], self.cards[j] = self.cards[j], self.cards[i]
+	self.hash ^= cardKey(self.cards[i].id, self.index, i) ^ cardKey(self.cards[j].id, self.index, j)
 }
 
 // Get a *Card from this pile
@@ -231,11 +254,13 @@ func (self *Pile) Get(i int) *Card {
 // Append a *Card to this pile
 func (self *Pile) Append(c *Card) {
 	self.cards = append(self.cards, c)
+	self.hash ^= cardKey(c.id, self.index, len(self.cards)-1)
 }
 
 // Delete a *Card from this pile
 func (self *Pile) Delete(index int) {
 	self.cards = append(self.cards[:index], self.cards[index+1:]...)
+	self.rehash()
 }
 
 // Extract a specific *Card from this pile
@@ -266,34 +291,27 @@ func (self *Pile) Pop() *Card {
 		return nil
 	}
 	c := self.cards[len(self.cards)-1]
+	self.hash ^= cardKey(c.id, self.index, len(self.cards)-1)
 	self.cards = self.cards[:len(self.cards)-1]
 	c.SetOwner(nil)
 	c.FlipUp()
-	TheGame.Baize.setFlag(dirtyCardPositions)
+	self.baize.cardsChanged()
 	return c
 }
 
 // Push a Card onto the end of this Pile (a stack)
 func (self *Pile) Push(c *Card) {
-	var pos image.Point
-	if len(self.cards) == 0 {
-		pos = self.pos
-	} else {
-		pos = self.PosAfter(self.Peek()) // get this BEFORE appending card
-	}
-
 	self.cards = append(self.cards, c)
+	self.hash ^= cardKey(c.id, self.index, len(self.cards)-1)
 	c.SetOwner(self)
-	c.LerpTo(pos)
-
 	if self.IsStock() {
 		c.FlipDown() // see? cards can transition and flip at the same time
 	}
-	TheGame.Baize.setFlag(dirtyCardPositions)
+	self.baize.cardsChanged()
 }
 
 func (self *Pile) FlipUpExposedCard() {
-	if !self.IsStock() {
+	if !self.IsStock() && !self.Covered() {
 		if c := self.Peek(); c != nil {
 			c.FlipUp()
 		}
@@ -304,6 +322,7 @@ func (self *Pile) ReverseCards() {
 	for i, j := 0, len(self.cards)-1; i < j; i, j = i+1, j-1 {
 		self.cards[i], self.cards[j] = self.cards[j], self.cards[i]
 	}
+	self.rehash()
 }
 
 // BuryCards moves cards with the specified ordinal to the beginning of the pile
@@ -323,7 +342,6 @@ func (self *Pile) BuryCards(ordinal int) {
 	for i := 0; i < len(tmp); i++ {
 		self.Push(tmp[i])
 	}
-	self.Refan()
 	// nb the card owner does not change
 }
 
@@ -339,182 +357,73 @@ func (self *Pile) SetSlot(slot image.Point) {
 	self.slot = slot
 }
 
-// SetBaizePos sets the position of this Pile in Baize coords,
-// and also sets the auxillary waste pile fanned positions
-func (self *Pile) SetBaizePos(pos image.Point) {
-	self.pos = pos
-	switch self.fanType {
-	case FAN_DOWN3:
-		self.pos1.X = self.pos.X
-		self.pos1.Y = self.pos.Y + int(float64(CardHeight)/CARD_FACE_FAN_FACTOR_V)
-		self.pos2.X = self.pos.X
-		self.pos2.Y = self.pos1.Y + int(float64(CardHeight)/CARD_FACE_FAN_FACTOR_V)
-	case FAN_LEFT3:
-		self.pos1.X = self.pos.X - int(float64(CardWidth)/CARD_FACE_FAN_FACTOR_H)
-		self.pos1.Y = self.pos.Y
-		self.pos2.X = self.pos1.X - int(float64(CardWidth)/CARD_FACE_FAN_FACTOR_H)
-		self.pos2.Y = self.pos.Y
-	case FAN_RIGHT3:
-		self.pos1.X = self.pos.X + int(float64(CardWidth)/CARD_FACE_FAN_FACTOR_H)
-		self.pos1.Y = self.pos.Y
-		self.pos2.X = self.pos1.X + int(float64(CardWidth)/CARD_FACE_FAN_FACTOR_H)
-		self.pos2.Y = self.pos.Y
-	}
+// Offset returns how far this pile is shifted from its slot, in tenths of a slot
+func (self *Pile) Offset() image.Point {
+	return self.offset
 }
 
-func (self *Pile) BaizePos() image.Point {
-	return self.pos
+// SetOffset shifts this pile from its slot by tenths of a slot, so it can overlap other piles
+func (self *Pile) SetOffset(offset image.Point) {
+	self.offset = offset
 }
 
-func (self *Pile) ScreenPos() image.Point {
-	return self.pos.Add(TheGame.Baize.dragOffset)
+// interchangeable returns true if moving all the cards from this pile to the empty pile p would be pointless,
+// because they are the same kind of pile, with the same label, and neither depends on a neighbour
+func (self *Pile) interchangeable(p *Pile) bool {
+	return self.category == p.category && self.label == p.label && self.left == nil && p.left == nil
 }
 
-func (self *Pile) BaizeRect() image.Rectangle {
-	var r image.Rectangle
-	r.Min = self.pos
-	r.Max = r.Min.Add(image.Point{CardWidth, CardHeight})
-	return r
+// Left returns the pile to the left of this one in a grid, or nil if it's the first in its row
+func (self *Pile) Left() *Pile {
+	return self.left
 }
 
-func (self *Pile) ScreenRect() image.Rectangle {
-	var r image.Rectangle = self.BaizeRect()
-	r.Min = r.Min.Add(TheGame.Baize.dragOffset)
-	r.Max = r.Max.Add(TheGame.Baize.dragOffset)
-	return r
+// SetLeft says which pile is to the left of this one in a grid, for games like Montana,
+// where the card that can go in a space depends on the card next to it
+func (self *Pile) SetLeft(left *Pile) {
+	self.left = left
 }
 
-func (self *Pile) FannedBaizeRect() image.Rectangle {
-	var r image.Rectangle = self.BaizeRect()
-	if len(self.cards) > 1 {
-		var c *Card = self.Peek()
-		// if c.Dragging() {
-		// 	return r
-		// }
-		var cPos = c.BaizePos()
-		switch self.fanType {
-		case FAN_NONE:
-			// do nothing
-		case FAN_RIGHT, FAN_RIGHT3:
-			r.Max.X = cPos.X + CardWidth
-		case FAN_LEFT, FAN_LEFT3:
-			r.Max.X = cPos.X - CardWidth
-		case FAN_DOWN, FAN_DOWN3:
-			r.Max.Y = cPos.Y + CardHeight
-		}
-	}
-	return r
+// SetWholePack lets a Foundation take every card in the pack, rather than a suit,
+// for games like Golf where all the cards go to one Foundation
+func (self *Pile) SetWholePack() {
+	self.wholePack = true
 }
 
-func (self *Pile) FannedScreenRect() image.Rectangle {
-	var r image.Rectangle = self.FannedBaizeRect()
-	r.Min = r.Min.Add(TheGame.Baize.dragOffset)
-	r.Max = r.Max.Add(TheGame.Baize.dragOffset)
-	return r
+// SetCoveredBy says which piles overlap this one; its cards are blocked until they are all empty
+func (self *Pile) SetCoveredBy(piles ...*Pile) {
+	self.coveredBy = piles
 }
 
-// PosAfter returns the position of the next card
-func (self *Pile) PosAfter(c *Card) image.Point {
-	if len(self.cards) == 0 {
-		println("Panic! PosAfter called in impossible way")
-		return self.pos
-	}
-	var pos image.Point
-	if c.Lerping() {
-		pos = c.dst
-	} else {
-		pos = c.pos
-	}
-	if pos.X <= 0 && pos.Y <= 0 {
-		// the card is still at 0,0 where it started life
-		// and is yet to have pos calculated from the pile slot
-		// println("zero pos in PosAfter", self.category)
-		return pos
-	}
-	switch self.fanType {
-	case FAN_NONE:
-		// nothing to do
-	case FAN_DOWN:
-		if c.Prone() {
-			pos.Y += int(float64(CardHeight) / float64(CARD_BACK_FAN_FACTOR))
-		} else {
-			pos.Y += int(float64(CardHeight) / self.fanFactor)
-		}
-	case FAN_LEFT:
-		if c.Prone() {
-			pos.X -= int(float64(CardWidth) / float64(CARD_BACK_FAN_FACTOR))
-		} else {
-			pos.X -= int(float64(CardWidth) / self.fanFactor)
-		}
-	case FAN_RIGHT:
-		if c.Prone() {
-			pos.X += int(float64(CardWidth) / float64(CARD_BACK_FAN_FACTOR))
-		} else {
-			pos.X += int(float64(CardWidth) / self.fanFactor)
-		}
-	case FAN_DOWN3, FAN_LEFT3, FAN_RIGHT3:
-		switch len(self.cards) {
-		case 0:
-			// nothing to do
-		case 1:
-			pos = self.pos1 // incoming card at slot 1
-		case 2:
-			pos = self.pos2 // incoming card at slot 2
-		default:
-			pos = self.pos2 // incoming card at slot 2
-			// top card needs to transition from slot[2] to slot[1]
-			i := len(self.cards) - 1
-			self.cards[i].LerpTo(self.pos1)
-			// mid card needs to transition from slot[1] to slot[0]
-			// all other cards to slot[0]
-			for i > 0 {
-				i--
-				self.cards[i].LerpTo(self.pos)
-			}
+// Covered returns true if any of the piles overlapping this one has cards in it
+func (self *Pile) Covered() bool {
+	for _, p := range self.coveredBy {
+		if !p.Empty() {
+			return true
 		}
 	}
-	return pos
+	return false
 }
 
-func (self *Pile) Refan() {
-	// TODO trying set pos instead of transition
-	var doFan3 bool = false
-	switch self.fanType {
-	case FAN_NONE:
-		for _, c := range self.cards {
-			c.LerpTo(self.pos)
-		}
-	case FAN_DOWN3, FAN_LEFT3, FAN_RIGHT3:
-		for _, c := range self.cards {
-			c.LerpTo(self.pos)
-		}
-		doFan3 = true
-	case FAN_DOWN, FAN_LEFT, FAN_RIGHT:
-		var pos = self.pos
-		var i = 0
-		for _, c := range self.cards {
-			c.LerpTo(pos)
-			pos = self.PosAfter(self.cards[i])
-			i++
+// coveredOnlyBy returns true if the only pile overlapping this one that has cards in it is p
+func (self *Pile) coveredOnlyBy(p *Pile) bool {
+	for _, q := range self.coveredBy {
+		if q != p && !q.Empty() {
+			return false
 		}
 	}
+	return true
+}
 
-	if doFan3 {
-		switch len(self.cards) {
-		case 0:
-		case 1:
-			// nothing to do
-		case 2:
-			c := self.cards[1]
-			c.LerpTo(self.pos1)
-		default:
-			i := len(self.cards)
-			i--
-			c := self.cards[i]
-			c.LerpTo(self.pos2)
-			i--
-			c = self.cards[i]
-			c.LerpTo(self.pos1)
+// flipUpUncoveredCards is called when this pile has been emptied,
+// to turn up the cards in the piles it was the last to overlap, as in TriPeaks
+func (self *Pile) flipUpUncoveredCards() {
+	for _, p := range self.baize.piles {
+		for _, q := range p.coveredBy {
+			if q == self {
+				p.FlipUpExposedCard()
+				break
+			}
 		}
 	}
 }
@@ -527,6 +436,9 @@ func (self *Pile) CanMoveTail(tail []*Card) (bool, error) {
 			return false, errors.New("Cannot move a face down card")
 		}
 	}
+	if self.Covered() {
+		return false, errors.New("That card is covered by another card")
+	}
 	switch self.moveType {
 	case MOVE_NONE:
 		// eg Discard, Foundation
@@ -587,7 +499,9 @@ func (self *Pile) DefaultTailTapped(tail []*Card) {
 	card := tail[0]
 	if card.tapDestination != nil {
 		src := card.Owner()
-		if len(tail) == 1 {
+		if other, ok, _ := self.baize.pairMove(card, card.tapDestination); ok {
+			self.baize.removeCards(card, other)
+		} else if len(tail) == 1 {
 			MoveCard(src, card.tapDestination)
 		} else {
 			MoveTail(card, card.tapDestination)
@@ -598,72 +512,3 @@ func (self *Pile) DefaultTailTapped(tail []*Card) {
 
 // func (self *Pile) DefaultConformant() bool   { return false }
 // func (self *Pile) DefaultUnsortedPairs() int { return 0 }
-
-func (self *Pile) DrawStaticCards(screen *ebiten.Image) {
-	for _, c := range self.cards {
-		if c.Static() {
-			c.Draw(screen)
-		}
-	}
-}
-
-func (self *Pile) DrawAnimatingCards(screen *ebiten.Image) {
-	for _, c := range self.cards {
-		if c.Lerping() || c.Flipping() {
-			c.Draw(screen)
-		}
-	}
-}
-
-func (self *Pile) DrawDraggingCards(screen *ebiten.Image) {
-	for _, c := range self.cards {
-		if c.Dragging() {
-			c.Draw(screen)
-		}
-	}
-}
-
-func (self *Pile) Update() {
-	for _, card := range self.cards {
-		card.Update()
-	}
-}
-
-func (self *Pile) Draw(screen *ebiten.Image) {
-
-	if self.img == nil || self.Hidden() {
-		return
-	}
-
-	op := &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(float64(self.pos.X+TheGame.Baize.dragOffset.X), float64(self.pos.Y+TheGame.Baize.dragOffset.Y))
-	// if self.target && len(self.cards) == 0 {
-	// 	op.ColorM.Scale(0.75, 0.75, 0.75, 1)
-	// 	// op.GeoM.Translate(2, 2)
-	// }
-
-	if self.IsStock() && TheGame.Baize.Recycles() > 0 {
-		if pt := image.Pt(ebiten.CursorPosition()); pt.In(self.ScreenRect()) {
-			if ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
-				op.GeoM.Translate(2, 2)
-			}
-		}
-	}
-
-	// if DebugMode {
-	// 	if sz := self.SizeWithFanFactor(self.fanFactor); sz != 0 {
-	// 		switch self.fanType {
-	// 		case FAN_DOWN:
-	// 			rect := self.FannedScreenRect()
-	// 			ebitenutil.DrawRect(screen,
-	// 				float64(rect.Min.X),
-	// 				float64(rect.Min.Y),
-	// 				float64(rect.Max.X-rect.Min.X),
-	// 				float64(sz),
-	// 				color.RGBA{0, 0, 0, 32})
-	// 		}
-	// 	}
-	// }
-
-	screen.DrawImage(self.img, op)
-}
@@ -0,0 +1,197 @@
+package sol
+
+//lint:file-ignore ST1006 Receiver name will be anything I like, thank you
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// Player looks at a Baize and chooses the next move, or returns false to give up.
+// A Player may remember what it has seen during a game, so make a new one for each game.
+type Player interface {
+	ChooseMove(b *Baize) (Move, bool)
+}
+
+// Players makes the built in players, by name.
+// seed makes players that use random numbers repeatable.
+var Players = map[string]func(seed uint64) Player{
+	"random":    func(seed uint64) Player { return NewRandomPlayer(seed) },
+	"greedy":    func(seed uint64) Player { return NewGreedyPlayer() },
+	"lookahead": func(seed uint64) Player { return NewLookaheadPlayer() },
+	"solver":    func(seed uint64) Player { return NewSolverPlayer(WinnableDealNodes) },
+}
+
+// PlayerNames returns the names of the built in players, sorted
+func PlayerNames() []string {
+	var names []string
+	for name := range Players {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RandomPlayer makes any legal move
+type RandomPlayer struct {
+	rng *rand.Rand
+}
+
+// NewRandomPlayer makes a RandomPlayer
+func NewRandomPlayer(seed uint64) *RandomPlayer {
+	return &RandomPlayer{rng: rand.New(rand.NewSource(int64(seed)))}
+}
+
+func (self *RandomPlayer) ChooseMove(b *Baize) (Move, bool) {
+	moves := b.LegalMoves()
+	if len(moves) == 0 {
+		return Move{}, false
+	}
+	return moves[self.rng.Intn(len(moves))], true
+}
+
+// PlayerPatience is how many moves a GreedyPlayer or LookaheadPlayer will make
+// without getting any closer to winning, before giving up
+var PlayerPatience = 50
+
+// progress remembers where a player has been, and how close it has got to winning
+type progress struct {
+	seen    map[uint64]bool
+	best    int // the best evaluation so far
+	stalled int // moves since the evaluation got better
+}
+
+func newProgress() progress {
+	return progress{seen: make(map[uint64]bool), best: math.MinInt32}
+}
+
+// visit records the position on b, and returns false if the player may as well give up
+func (self *progress) visit(b *Baize) bool {
+	self.seen[b.Hash()] = true
+	if e := b.evaluate(); e > self.best {
+		self.best, self.stalled = e, 0
+	} else {
+		self.stalled++
+	}
+	return self.stalled < PlayerPatience
+}
+
+// firstNew returns the first of the moves that doesn't lead back to somewhere already seen
+func (self *progress) firstNew(b *Baize, moves []Move) (Move, bool) {
+	s := b.NewSolver()
+	if s == nil {
+		return Move{}, false
+	}
+	// try each move on the solver's baize, to see where it leads
+	for _, m := range moves {
+		ok := s.b.ApplyMove(m) && !self.seen[s.b.Hash()]
+		s.b.rewindTo(0)
+		if ok {
+			return m, true
+		}
+	}
+	return Move{}, false
+}
+
+// GreedyPlayer makes the moves a tap would make, preferring the card with the heaviest
+// tap weight found by FindDestinations, and only deals from the stock when there's nothing else to do.
+type GreedyPlayer struct {
+	progress
+}
+
+// NewGreedyPlayer makes a GreedyPlayer
+func NewGreedyPlayer() *GreedyPlayer {
+	return &GreedyPlayer{progress: newProgress()}
+}
+
+func (self *GreedyPlayer) ChooseMove(b *Baize) (Move, bool) {
+	if !self.visit(b) {
+		return Move{}, false
+	}
+	var moves []Move
+	var weights = make(map[Move]int)
+	for _, m := range b.LegalMoves() {
+		if m.Dst < 0 {
+			weights[m] = -1
+		} else {
+			src := b.piles[m.Src]
+			card := src.cards[src.Len()-m.N]
+			if card.tapDestination != b.piles[m.Dst] {
+				continue
+			}
+			// moveScore breaks ties
+			weights[m] = card.tapWeight*1000 + b.moveScore(m)
+		}
+		moves = append(moves, m)
+	}
+	sort.SliceStable(moves, func(i, j int) bool { return weights[moves[i]] > weights[moves[j]] })
+	return self.firstNew(b, moves)
+}
+
+// LookaheadPlayer makes the move Baize.Hints rates best
+type LookaheadPlayer struct {
+	Plies int // how far ahead to look
+	progress
+}
+
+// NewLookaheadPlayer makes a LookaheadPlayer that looks two moves ahead,
+// which is good enough, and a lot quicker than HintPlies in variants with many moves
+func NewLookaheadPlayer() *LookaheadPlayer {
+	return &LookaheadPlayer{Plies: 2, progress: newProgress()}
+}
+
+func (self *LookaheadPlayer) ChooseMove(b *Baize) (Move, bool) {
+	if !self.visit(b) {
+		return Move{}, false
+	}
+	return self.firstNew(b, b.rankedMoves(self.Plies))
+}
+
+// SolverPlayer asks the solver for a win, and follows it.
+// If the solver can't find one, it plays the rest of the game as a LookaheadPlayer.
+type SolverPlayer struct {
+	MaxNodes  int
+	plan      []Move
+	expect    []uint64 // the baize Hash each planned move starts from
+	failed    bool
+	lookahead *LookaheadPlayer
+}
+
+// NewSolverPlayer makes a SolverPlayer that lets the solver expand maxNodes positions each time it is asked
+func NewSolverPlayer(maxNodes int) *SolverPlayer {
+	return &SolverPlayer{MaxNodes: maxNodes, lookahead: NewLookaheadPlayer()}
+}
+
+func (self *SolverPlayer) ChooseMove(b *Baize) (Move, bool) {
+	if !self.failed && (len(self.plan) == 0 || self.expect[0] != b.Hash()) {
+		self.solve(b)
+	}
+	if len(self.plan) == 0 {
+		return self.lookahead.ChooseMove(b)
+	}
+	m := self.plan[0]
+	self.plan, self.expect = self.plan[1:], self.expect[1:]
+	return m, true
+}
+
+func (self *SolverPlayer) solve(b *Baize) {
+	self.plan, self.expect = nil, nil
+	s := b.NewSolver()
+	if s == nil {
+		self.failed = true
+		return
+	}
+	s.MaxNodes = self.MaxNodes
+	if r := s.Solve(context.Background()); r.Outcome != SOLVED {
+		self.failed = true
+		return
+	}
+	// the move log holds the position before each move of the win
+	for _, sav := range s.b.positions()[:len(s.path)] {
+		s.b.updateFromSavable(sav)
+		self.expect = append(self.expect, s.b.Hash())
+	}
+	self.plan = s.path
+}
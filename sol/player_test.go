@@ -0,0 +1,42 @@
+package sol
+
+import (
+	"context"
+	"testing"
+)
+
+func TestPlayers(t *testing.T) {
+	for _, name := range PlayerNames() {
+		b := PlayGame("Klondike", nil, 1, Players[name](1))
+		if b == nil || b.MovesMade() == 0 {
+			t.Errorf("%s player made no moves", name)
+		}
+	}
+	// the solver can win Klondike deal #1, so a SolverPlayer should
+	if b := PlayGame("Klondike", nil, 1, NewSolverPlayer(WinnableDealNodes)); !b.Complete() {
+		t.Error("solver player lost Klondike deal #1")
+	}
+}
+
+func TestTournament(t *testing.T) {
+	results := Tournament(context.Background(), []string{"Klondike", "Yukon"}, []string{"greedy", "random"}, 3, 1)
+	if len(results) != 4 {
+		t.Fatalf("got %d results, want 4", len(results))
+	}
+	for _, r := range results {
+		if r.Games != 3 {
+			t.Errorf("%s played %d games of %s, want 3", r.Player, r.Games, r.Variant)
+		}
+	}
+
+	results = []TournamentResult{
+		{Variant: "A", Player: "greedy", Games: 10, Won: 9},
+		{Variant: "B", Player: "greedy", Games: 10, Won: 5},
+		{Variant: "C", Player: "greedy", Games: 10, Won: 0},
+		{Variant: "D", Player: "random", Games: 10, Won: 0},
+	}
+	easier, harder := EasierAndHarder(results, "greedy", 50, 5)
+	if len(easier) != 2 || easier[0] != "A" || easier[1] != "B" || len(harder) != 1 || harder[0] != "C" {
+		t.Errorf("got easier %v harder %v", easier, harder)
+	}
+}
@@ -0,0 +1,174 @@
+package sol
+
+import (
+	"testing"
+
+	"oddstream.games/gosol/cardid"
+)
+
+// putCard swaps a card with the top card of pile p (or moves it there, if p is empty), so tests can set up a position
+func putCard(b *Baize, p *Pile, suit, ord int) *Card {
+	c := findCard(b, suit, ord)
+	top := p.Peek()
+	if c == top {
+		return c
+	}
+	q := c.Owner()
+	if top == nil {
+		for i := range q.cards {
+			if q.cards[i] == c {
+				q.cards = append(q.cards[:i], q.cards[i+1:]...)
+				break
+			}
+		}
+		p.cards = append(p.cards, c)
+		c.SetOwner(p)
+		p.rehash()
+		q.rehash()
+		return c
+	}
+	for i := range q.cards {
+		if q.cards[i] == c {
+			q.cards[i] = top
+		}
+	}
+	p.cards[len(p.cards)-1] = c
+	c.SetOwner(p)
+	top.SetOwner(q)
+	if c.Prone() != top.Prone() {
+		if c.Prone() {
+			c.FlipUp()
+			top.FlipDown()
+		} else {
+			c.FlipDown()
+			top.FlipUp()
+		}
+	}
+	p.rehash()
+	q.rehash()
+	return c
+}
+
+func newPyramid(t *testing.T, variant string) (*Baize, *Pyramid) {
+	b := NewBaize(variant, nil)
+	b.StartFreshGame()
+	pyr, ok := b.script.(*Pyramid)
+	if !ok {
+		t.Fatalf("%s is not a Pyramid", variant)
+	}
+	return b, pyr
+}
+
+func TestPyramidDeal(t *testing.T) {
+	b, pyr := newPyramid(t, "Pyramid")
+	if len(pyr.pyramid) != 28 {
+		t.Fatalf("Pyramid has %d piles in the pyramid, want 28", len(pyr.pyramid))
+	}
+	for i, p := range pyr.pyramid {
+		if p.Len() != 1 || p.Peek().Prone() {
+			t.Errorf("pyramid pile %d should have one face up card", i)
+		}
+		if covered := i < 21; p.Covered() != covered {
+			t.Errorf("pyramid pile %d covered is %v, want %v", i, p.Covered(), covered)
+		}
+	}
+	if ok, _ := pyr.pyramid[0].CanMoveTail(pyr.pyramid[0].Cards()); ok {
+		t.Error("the card at the top of the pyramid could be moved")
+	}
+	if err := b.Validate(); err != nil {
+		t.Error(err)
+	}
+
+	b, pyr = newPyramid(t, "Giza")
+	if b.script.Stock().Len() != 0 {
+		t.Errorf("Giza has %d cards left in the stock", b.script.Stock().Len())
+	}
+	for i, r := range pyr.reserves {
+		if r.Len() != 3 {
+			t.Errorf("Giza reserve %d has %d cards, want 3", i, r.Len())
+		}
+	}
+}
+
+func TestPyramidPairs(t *testing.T) {
+	b, pyr := newPyramid(t, "Pyramid")
+	discard := b.script.Discards()[0]
+	six := putCard(b, pyr.pyramid[21], cardid.HEART, 6)
+	seven := putCard(b, pyr.pyramid[22], cardid.SPADE, 7)
+	king := putCard(b, pyr.pyramid[23], cardid.CLUB, 13)
+	otherSix := putCard(b, pyr.pyramid[24], cardid.CLUB, 6)
+	putCard(b, pyr.pyramid[15], cardid.HEART, 7)
+	b.UndoPush()
+	before := b.Hash()
+
+	if ok, _ := b.DropTail([]*Card{six}, pyr.pyramid[24]); ok {
+		t.Error("two sixes were removed")
+	}
+	if ok, _ := b.DropTail([]*Card{otherSix}, pyr.pyramid[15]); ok {
+		t.Error("a six was paired with a covered seven")
+	}
+	if ok, _ := b.DropTail([]*Card{six}, discard); ok {
+		t.Error("a six was removed on its own")
+	}
+	if b.Hash() != before {
+		t.Fatal("a move that wasn't allowed changed the baize")
+	}
+
+	if ok, err := b.DropTail([]*Card{six}, pyr.pyramid[22]); !ok {
+		t.Fatalf("a six could not be paired with a seven: %s", err)
+	}
+	if discard.Len() != 2 || six.Owner() != discard || seven.Owner() != discard {
+		t.Error("the six and seven did not go to the discard pile")
+	}
+	if !pyr.pyramid[16].Covered() { // overlapped by 22, now empty, and 23, the king
+		t.Error("a card was uncovered while one of the cards overlapping it remained")
+	}
+	b.Undo()
+	if b.Hash() != before || six.Owner() != pyr.pyramid[21] || seven.Owner() != pyr.pyramid[22] {
+		t.Error("undo did not put the pair back")
+	}
+
+	b.FindDestinations()
+	if !b.TailTapped([]*Card{king}) || king.Owner() != discard {
+		t.Error("tapping a king did not remove it")
+	}
+	if pc := b.PercentComplete(); pc != 1 {
+		t.Errorf("PercentComplete is %d with one card removed, want 1", pc)
+	}
+
+	b, pyr = newPyramid(t, "Pyramid")
+	for _, p := range pyr.pyramid[1:] {
+		MoveCard(p, b.script.Discards()[0])
+	}
+	if b.Complete() {
+		t.Error("Pyramid complete with one card left")
+	}
+	MoveCard(pyr.pyramid[0], b.script.Discards()[0])
+	for b.script.Stock().Len() > 0 {
+		MoveCard(b.script.Stock(), b.script.Discards()[0])
+	}
+	MoveCard(b.script.Waste(), b.script.Discards()[0])
+	if !b.Complete() || b.PercentComplete() != 100 {
+		t.Error("Pyramid not complete with all the cards removed")
+	}
+}
+
+func TestPyramidRelaxed(t *testing.T) {
+	for _, tc := range []struct {
+		variant string
+		ok      bool
+	}{
+		{"Pyramid", false},
+		{"Pyramid Relaxed", true},
+	} {
+		b, pyr := newPyramid(t, tc.variant)
+		// pile 15 is overlapped by piles 21 and 22; take away 21, so only 22 overlaps it
+		MoveCard(pyr.pyramid[21], b.script.Discards()[0])
+		five := putCard(b, pyr.pyramid[22], cardid.SPADE, 5)
+		putCard(b, pyr.pyramid[15], cardid.HEART, 8)
+		b.UndoPush()
+		if ok, err := b.DropTail([]*Card{five}, pyr.pyramid[15]); ok != tc.ok {
+			t.Errorf("%s: pairing a card with the card it overlaps gave %v, want %v (%v)", tc.variant, ok, tc.ok, err)
+		}
+	}
+}
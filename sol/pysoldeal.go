@@ -0,0 +1,172 @@
+package sol
+
+//lint:file-ignore ST1006 Receiver name will be anything I like, thank you
+
+import (
+	"log"
+
+	"oddstream.games/gosol/cardid"
+)
+
+// PySolFC numbers its deals too, so variants with a PySolFC name in Variants
+// can optionally be shuffled the same way, and (with help from StartGame)
+// dealt into the same layout.
+// Game numbers below 32000 use the Microsoft FreeCell generator (msRand),
+// larger numbers use Python's random module, a Mersenne Twister.
+
+const pysolMsLimit = 32000
+
+// mtRand is MT19937 seeded the way Python's random.Random(n) seeds it
+type mtRand struct {
+	mt  [624]uint32
+	mti int
+}
+
+func newMtRand(seed uint64) *mtRand {
+	r := &mtRand{}
+	// Python turns an int seed into 32-bit words, least significant first
+	var key []uint32
+	for n := seed; n != 0; n >>= 32 {
+		key = append(key, uint32(n))
+	}
+	if len(key) == 0 {
+		key = []uint32{0}
+	}
+	r.initByArray(key)
+	return r
+}
+
+func (r *mtRand) initGenrand(s uint32) {
+	r.mt[0] = s
+	for i := 1; i < len(r.mt); i++ {
+		r.mt[i] = 1812433253*(r.mt[i-1]^(r.mt[i-1]>>30)) + uint32(i)
+	}
+	r.mti = len(r.mt)
+}
+
+func (r *mtRand) initByArray(key []uint32) {
+	const n = len(r.mt)
+	r.initGenrand(19650218)
+	i, j := 1, 0
+	k := n
+	if len(key) > k {
+		k = len(key)
+	}
+	for ; k > 0; k-- {
+		r.mt[i] = (r.mt[i] ^ ((r.mt[i-1] ^ (r.mt[i-1] >> 30)) * 1664525)) + key[j] + uint32(j)
+		i++
+		j++
+		if i >= n {
+			r.mt[0] = r.mt[n-1]
+			i = 1
+		}
+		if j >= len(key) {
+			j = 0
+		}
+	}
+	for k = n - 1; k > 0; k-- {
+		r.mt[i] = (r.mt[i] ^ ((r.mt[i-1] ^ (r.mt[i-1] >> 30)) * 1566083941)) - uint32(i)
+		i++
+		if i >= n {
+			r.mt[0] = r.mt[n-1]
+			i = 1
+		}
+	}
+	r.mt[0] = 0x80000000
+}
+
+func (r *mtRand) uint32() uint32 {
+	const n, m = len(r.mt), 397
+	if r.mti >= n {
+		for kk := 0; kk < n; kk++ {
+			y := (r.mt[kk] & 0x80000000) | (r.mt[(kk+1)%n] & 0x7fffffff)
+			r.mt[kk] = r.mt[(kk+m)%n] ^ (y >> 1)
+			if y&1 != 0 {
+				r.mt[kk] ^= 0x9908b0df
+			}
+		}
+		r.mti = 0
+	}
+	y := r.mt[r.mti]
+	r.mti++
+	y ^= y >> 11
+	y ^= (y << 7) & 0x9d2c5680
+	y ^= (y << 15) & 0xefc60000
+	y ^= y >> 18
+	return y
+}
+
+// below returns 0 <= x < n, as Python's Random._randbelow does
+func (r *mtRand) below(n int) int {
+	var k uint
+	for b := n; b > 0; b >>= 1 {
+		k++
+	}
+	for {
+		x := int(r.uint32() >> (32 - k))
+		if x < n {
+			return x
+		}
+	}
+}
+
+// pysolIndex is the position of a card in PySolFC's unshuffled talon,
+// which is built deck by deck, with suits in the order clubs, spades, hearts, diamonds
+func pysolIndex(c *Card) int {
+	var suit int
+	switch c.Suit() {
+	case cardid.CLUB:
+		suit = 0
+	case cardid.SPADE:
+		suit = 1
+	case cardid.HEART:
+		suit = 2
+	case cardid.DIAMOND:
+		suit = 3
+	}
+	return c.id.Pack()*52 + suit*13 + c.Ordinal() - 1
+}
+
+// ShufflePySol puts the cards in this pile into the order that PySolFC's talon would have
+// for a game number, so that popping cards off this pile one at a time deals the same cards.
+func (self *Pile) ShufflePySol(game uint64) {
+	cards := make([]*Card, self.Len())
+	for _, c := range self.cards {
+		idx := pysolIndex(c)
+		if idx < 0 || idx >= len(cards) || cards[idx] != nil {
+			log.Printf("Cannot make PySolFC game #%d from %d cards", game, self.Len())
+			return
+		}
+		cards[idx] = c
+	}
+
+	var randint func(n int) int // 0 <= x <= n
+	if game < pysolMsLimit {
+		r := newMsRand(game)
+		randint = func(n int) int { return int(r.next() % uint64(n+1)) }
+		if len(cards) == 52 {
+			// PySolFC puts a single pack into Microsoft's order first, so FreeCell games match
+			ms := make([]*Card, 0, 52)
+			for ord := 0; ord < 13; ord++ {
+				for _, suit := range []int{0, 39, 26, 13} {
+					ms = append(ms, cards[ord+suit])
+				}
+			}
+			cards = ms
+		}
+	} else {
+		r := newMtRand(game)
+		randint = func(n int) int { return r.below(n + 1) }
+	}
+
+	for n := len(cards) - 1; n > 0; n-- {
+		j := randint(n)
+		cards[n], cards[j] = cards[j], cards[n]
+	}
+	// PySolFC's talon is a stack too, dealing from the end of the list
+	copy(self.cards, cards)
+	self.rehash()
+	if DebugMode {
+		log.Printf("Shuffled %d cards as PySolFC game #%d", self.Len(), game)
+	}
+}
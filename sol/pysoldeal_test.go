@@ -0,0 +1,95 @@
+package sol
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMtRandIsPythonRandom(t *testing.T) {
+	// random.Random(seed).getrandbits(32) from CPython
+	for _, tc := range []struct {
+		seed uint64
+		want uint32
+	}{
+		{123456789, 2754794679},
+		{0, 3626764237},
+		{1<<40 + 5, 2166296868},
+	} {
+		if got := newMtRand(tc.seed).uint32(); got != tc.want {
+			t.Errorf("seed %d: got %d, want %d", tc.seed, got, tc.want)
+		}
+	}
+}
+
+func newPySolBaize(v string) *Baize {
+	s := DefaultSettings()
+	s.PySolDeals = true
+	b := NewBaize(v, s)
+	b.StartFreshGame()
+	return b
+}
+
+func TestPySolFreecellIsMicrosoft(t *testing.T) {
+	// PySolFC's FreeCell game numbers below 32000 are Microsoft's
+	b := newPySolBaize("Freecell")
+	for _, kd := range msKnownDeals {
+		if kd.deal >= pysolMsLimit {
+			continue
+		}
+		b.NewDealFromSeed(kd.deal)
+		tabs := b.script.Tableaux()
+		for i, name := range strings.Fields(kd.layout) {
+			if got := msCardName(tabs[i%8].Cards()[i/8]); got != name {
+				t.Fatalf("PySolFC FreeCell game #%d card %d: got %s, want %s", kd.deal, i, got, name)
+			}
+		}
+	}
+}
+
+func TestPySolKnownDeals(t *testing.T) {
+	// each tableau from the bottom card up, then the top of the waste, as PySolFC deals them;
+	// made by running python3 testdata/pysoldeals.py, which shuffles with CPython's random.Random
+	// as PySolFC's MTRandom does, and deals in the order of each game's PySolFC startGame
+	for _, tc := range []struct {
+		variant string
+		game    uint64
+		want    string
+	}{
+		{"Klondike", 123456, "AH | AC 4S | QC TD AD | 2C 5S 9D JH | 6S 4C 6H 9C TH | QD 5C 2S 8H JS 6C | KD 4D 8C 3D QS 3C 2H | 3H"},
+		{"Klondike", 1000000000000, "9S | QH 2C | QD 9D 2S | 7D 8D TS 7C | TD AD 3C 2H JC | 4D KC 8H 3H JH QS | 5H 6H JD 7S JS QC 5C | 5S"},
+		{"Yukon", 123456, "3S | KD 2H AH 5H JC 2D | QD 4D 6C 3H 5D JD 7H | 6S 5C 8C TH KS KH QH TC | 2C 4C 2S 3D JH 4H 7S 8S KC | QC 5S 6H 8H QS AD TS AS 9S 7C | AC TD 9D 9C JS 3C 4S 7D 9H 6D 8D"},
+		{"Spider Four Suits", 123456, "KD TD 5H 8S 8H 7S | JD 2S 9D 9H 3H | QH 4H 6C 7S 7H | 4C 4H JH KH QD JD | TS JC 8C AS AD | AC JS 5C 6D 9S | 7H 3D KC KC 9S 3H | TC 3S AH TS QC | 7C 4S 7C QC 3S | 9H TD KS JC JH 2H"},
+		{"Forty Thieves", 123456, "KD TD 5H 8S | JD 2S 9D 9H | QH 4H 6C 7S | 4C 4H JH KH | TS JC 8C AS | AC JS 5C 6D | 7H 3D KC KC | TC 3S AH TS | 7C 4S 7C QC | 9H TD KS JC | 8H"},
+	} {
+		b := newPySolBaize(tc.variant)
+		b.NewDealFromSeed(tc.game)
+		checkCards(t, b)
+		var piles []string
+		for _, tab := range b.script.Tableaux() {
+			var cards []string
+			for _, c := range tab.Cards() {
+				cards = append(cards, msCardName(c))
+			}
+			piles = append(piles, strings.Join(cards, " "))
+		}
+		if w := b.script.Waste(); w != nil {
+			piles = append(piles, msCardName(w.Peek()))
+		}
+		if got := strings.Join(piles, " | "); got != tc.want {
+			t.Errorf("PySolFC %s game #%d:\ngot  %s\nwant %s", tc.variant, tc.game, got, tc.want)
+		}
+	}
+}
+
+func TestPySolVariants(t *testing.T) {
+	for v, script := range Variants {
+		if script.PySol() == "" {
+			continue
+		}
+		b := newPySolBaize(v)
+		for _, game := range []uint64{1, 31999, 32000, 123456789} {
+			b.NewDealFromSeed(game)
+			checkCards(t, b)
+		}
+	}
+}
@@ -5,11 +5,14 @@ package sol
 import (
 	"fmt"
 	"log"
+	"reflect"
 
-	"oddstream.games/gosol/sound"
+	"oddstream.games/gosol/util"
 )
 
 type ScriptBase struct {
+	baize *Baize
+
 	cells       []*Pile
 	discards    []*Pile
 	foundations []*Pile
@@ -19,8 +22,11 @@ type ScriptBase struct {
 	waste       *Pile
 
 	wikipedia    string
+	pysol        string // name of the PySolFC game with the same deal, if any
 	cardColors   int
 	packs, suits int
+	ranks        []int // in each suit, in order, if not Ace to King
+	jokers       int   // per pack
 }
 
 type Scripter interface {
@@ -31,6 +37,7 @@ type Scripter interface {
 	TailMoveError([]*Card) (bool, error)
 	TailAppendError(*Pile, []*Card) (bool, error)
 	UnsortedPairs(*Pile) int
+	PairError(*Card, *Card) (bool, error)
 
 	TailTapped([]*Card)
 	PileTapped(*Pile)
@@ -44,11 +51,31 @@ type Scripter interface {
 	Waste() *Pile
 
 	Complete() bool
+	PercentComplete() int
 	Wikipedia() string
+	PySol() string
 	CardColors() int
 	SafeCollect() bool
+	CanCollect() bool
 	Packs() int
 	Suits() int
+	Ranks() []int
+	Jokers() int
+
+	setBaize(*Baize)
+}
+
+// cloneScript makes a shallow copy of one of the prototype scripts in Variants,
+// so that each Baize can have it's own script.
+func cloneScript(proto Scripter) Scripter {
+	v := reflect.ValueOf(proto).Elem()
+	clone := reflect.New(v.Type())
+	clone.Elem().Set(v)
+	return clone.Interface().(Scripter)
+}
+
+func (sb *ScriptBase) setBaize(b *Baize) {
+	sb.baize = b
 }
 
 // fallback/default functions for ScriptBase+Scripter /////////////////////////
@@ -66,6 +93,13 @@ func (sb ScriptBase) AfterMove() {}
 
 func (sb ScriptBase) PileTapped(pile *Pile) {}
 
+// PairError says if a card can be removed with another card (the top card of its pile),
+// or on its own if the other card is nil, in games like Pyramid; see Baize.pairMove.
+// By default cards are never removed, which is not an error.
+func (sb ScriptBase) PairError(*Card, *Card) (bool, error) {
+	return false, nil
+}
+
 func (sb ScriptBase) Cells() []*Pile {
 	return sb.cells
 }
@@ -96,7 +130,7 @@ func (sb ScriptBase) Waste() *Pile {
 
 // Complete - default is number of cards in Foundations == number of cards in CardLibrary.
 //
-// In Bisley, there may be <13 cards in a Foundation.
+// In Bisley, there may be fewer cards in a Foundation than there are in a suit.
 // This will need overriding for any variants with Discard piles.
 // Could also do this by checking if any pile other than a Foundation is not empty.
 func (sb ScriptBase) Complete() bool {
@@ -104,7 +138,20 @@ func (sb ScriptBase) Complete() bool {
 	for _, f := range sb.foundations {
 		n += len(f.cards)
 	}
-	return n == TheGame.Baize.cardCount
+	return n == sb.baize.cardCount
+}
+
+// PercentComplete - default is the proportion of pairs of cards in all the piles that are sorted
+func (sb ScriptBase) PercentComplete() int {
+	var pairs, unsorted, percent int
+	for _, p := range sb.baize.piles {
+		if p.Len() > 1 {
+			pairs += p.Len() - 1
+		}
+		unsorted += p.vtable.UnsortedPairs()
+	}
+	percent = (int)(100.0 - util.MapValue(float64(unsorted), 0, float64(pairs), 0.0, 100.0))
+	return percent
 }
 
 // SpiderComplete - used to override default Complete() in Spider varaints.
@@ -114,10 +161,10 @@ func (sb ScriptBase) Complete() bool {
 // There aren't any foundations.
 func (sb ScriptBase) SpiderComplete() bool {
 	for _, t := range sb.tableaux {
-		switch len(t.cards) {
+		switch len(NonJokers(t.cards)) {
 		case 0:
-			// that's fine
-		case 13: // TODO 104 cards, 8 tabs = 13 cards/tab
+			// that's fine, even if there are some jokers left over
+		case len(sb.Ranks()): // a whole suit
 			if !t.vtable.Conformant() {
 				return false
 			}
@@ -136,6 +183,11 @@ func (sb ScriptBase) Wikipedia() string {
 	}
 }
 
+// PySol returns the name of the PySolFC game that deals the same layout, or "" if there isn't one
+func (sb ScriptBase) PySol() string {
+	return sb.pysol
+}
+
 func (sb ScriptBase) CardColors() int {
 	if sb.cardColors == 0 { // uninitialized default
 		return 2
@@ -148,6 +200,12 @@ func (sb ScriptBase) SafeCollect() bool {
 	return sb.CardColors() == 2
 }
 
+// CanCollect - by default, cards can be collected to the foundations automatically;
+// not in games like Golf, where choosing which card goes to the foundation is the game
+func (sb ScriptBase) CanCollect() bool {
+	return true
+}
+
 func (sb ScriptBase) Packs() int {
 	if sb.packs == 0 {
 		return 1
@@ -162,12 +220,39 @@ func (sb ScriptBase) Suits() int {
 	return sb.suits
 }
 
+// standardRanks are the ranks in each suit of a full pack
+var standardRanks = []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13}
+
+// Ranks returns the ordinals of the cards in each suit, lowest first
+func (sb ScriptBase) Ranks() []int {
+	if sb.ranks == nil {
+		return standardRanks
+	}
+	return sb.ranks
+}
+
+// Jokers returns the number of jokers in each pack
+func (sb ScriptBase) Jokers() int {
+	return sb.jokers
+}
+
 // You can't use functions as keys in maps : the key type must be comparable
 // so you can't do: var ExtendedColorMap = map[CardPairCompareFunc]bool{}
 // type CardPairCompareFunc func(CardPair) (bool, error)
 
 // useful generic game library of functions ///////////////////////////////////
 
+// NonJokers returns the cards that aren't jokers
+func NonJokers(cards []*Card) []*Card {
+	var result []*Card
+	for _, c := range cards {
+		if !c.Joker() {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
 func AnyCardsProne(cards []*Card) bool {
 	for _, c := range cards {
 		if c.Prone() {
@@ -182,7 +267,10 @@ func MoveCard(src *Pile, dst *Pile) *Card {
 	if c := src.Pop(); c != nil {
 		dst.Push(c)
 		src.FlipUpExposedCard()
-		sound.Play("Place")
+		if src.Empty() {
+			src.flipUpUncoveredCards()
+		}
+		dst.baize.playSound("Place")
 		return c
 	}
 	return nil
@@ -211,25 +299,29 @@ func MoveTail(card *Card, dst *Pile) {
 			dst.Push(c)
 		}
 		src.FlipUpExposedCard()
-		sound.Play("Place")
+		if src.Empty() {
+			src.flipUpUncoveredCards()
+		}
+		dst.baize.playSound("Place")
 	}
 }
 
 func RecycleWasteToStock(waste *Pile, stock *Pile) {
-	if TheGame.Baize.Recycles() > 0 {
+	var b *Baize = stock.baize
+	if b.Recycles() > 0 {
 		for waste.Len() > 0 {
 			MoveCard(waste, stock)
 		}
-		TheGame.Baize.SetRecycles(TheGame.Baize.Recycles() - 1)
+		b.SetRecycles(b.Recycles() - 1)
 		switch {
-		case TheGame.Baize.recycles == 0:
-			TheGame.UI.ToastInfo("No more recycles")
-		case TheGame.Baize.recycles == 1:
-			TheGame.UI.ToastInfo(fmt.Sprintf("%d recycle remaining", TheGame.Baize.Recycles()))
-		case TheGame.Baize.recycles < 10:
-			TheGame.UI.ToastInfo(fmt.Sprintf("%d recycles remaining", TheGame.Baize.Recycles()))
+		case b.recycles == 0:
+			b.toastInfo("No more recycles")
+		case b.recycles == 1:
+			b.toastInfo(fmt.Sprintf("%d recycle remaining", b.Recycles()))
+		case b.recycles < 10:
+			b.toastInfo(fmt.Sprintf("%d recycles remaining", b.Recycles()))
 		}
 	} else {
-		TheGame.UI.ToastInfo("No more recycles")
+		b.toastInfo("No more recycles")
 	}
 }
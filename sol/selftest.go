@@ -0,0 +1,126 @@
+package sol
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"runtime/debug"
+)
+
+// SelfTest fuzzes the engine: it plays seeded deals of each variant with random moves,
+// undoing and redoing, restarting, and saving and loading the game as it goes,
+// and checks the baize with Validate after every step.
+// It returns what went wrong, at most once per deal, saying how to get there again.
+func SelfTest(ctx context.Context, variants []string, deals int, firstSeed uint64, moves int) []error {
+	settings := DefaultSettings()
+	settings.PySolDeals = false
+
+	var errs []error
+	for _, v := range variants {
+		for seed := firstSeed; seed < firstSeed+uint64(deals); seed++ {
+			if ctx.Err() != nil {
+				return errs
+			}
+			if err := selfTestDeal(v, settings, seed, moves); err != nil {
+				errs = append(errs, fmt.Errorf("%s deal %d, %w", v, seed, err))
+			}
+		}
+	}
+	return errs
+}
+
+// selfTestDeal plays one deal of a variant for SelfTest
+func selfTestDeal(variant string, settings *Settings, seed uint64, moves int) (err error) {
+	var step = "building the piles"
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%s: panic: %v\n%s", step, r, debug.Stack())
+		}
+	}()
+	check := func(b *Baize) error {
+		if err := b.Validate(); err != nil {
+			return fmt.Errorf("%s: %w", step, err)
+		}
+		return nil
+	}
+
+	b := NewBaize(variant, settings)
+	if b == nil {
+		return fmt.Errorf("no such variant")
+	}
+	b.StartFreshGame()
+	if err := check(b); err != nil {
+		return err
+	}
+	step = "dealing"
+	b.NewDealFromSeed(seed)
+	if err := check(b); err != nil {
+		return err
+	}
+	deal := b.Hash()
+
+	p := NewRandomPlayer(seed)
+	for i := 1; i <= moves && !b.Complete(); i++ {
+		m, ok := p.ChooseMove(b)
+		if !ok {
+			break
+		}
+		step = fmt.Sprintf("move %d (%s)", i, m)
+		if !b.ApplyMove(m) {
+			return fmt.Errorf("%s: a legal move could not be made", step)
+		}
+		if err := check(b); err != nil {
+			return err
+		}
+		if i%7 == 0 && !b.Complete() {
+			step = fmt.Sprintf("undoing move %d", i)
+			before := b.Hash()
+			b.Undo()
+			if err := check(b); err != nil {
+				return err
+			}
+			step = fmt.Sprintf("redoing move %d", i)
+			b.Redo()
+			if err := check(b); err != nil {
+				return err
+			}
+			if b.Hash() != before {
+				return fmt.Errorf("%s: the position is not the same as before it was undone", step)
+			}
+		}
+	}
+
+	step = fmt.Sprintf("saving and loading after %d moves", b.MovesMade())
+	bytes, err := json.Marshal(b.MoveLog())
+	if err != nil {
+		return fmt.Errorf("%s: %w", step, err)
+	}
+	ml, err := unmarshalMoveLog(bytes)
+	if err != nil {
+		return fmt.Errorf("%s: %w", step, err)
+	}
+	other := NewBaize(variant, settings)
+	other.StartFreshGame()
+	if !other.isMoveLogOk(ml) {
+		return fmt.Errorf("%s: the saved game is not ok", step)
+	}
+	other.SetMoveLog(ml)
+	if err := check(other); err != nil {
+		return err
+	}
+	if other.Hash() != b.Hash() || other.MovesMade() != b.MovesMade() {
+		return fmt.Errorf("%s: the loaded game is not the same as the saved one", step)
+	}
+
+	if !b.Complete() {
+		step = "restarting the deal"
+		b.RestartDeal()
+		if err := check(b); err != nil {
+			return err
+		}
+		if b.Hash() != deal {
+			return fmt.Errorf("%s: the position is not the same as the deal", step)
+		}
+	}
+	return nil
+}
@@ -1,10 +1,5 @@
 package sol
 
-import (
-	"oddstream.games/gosol/sound"
-	"oddstream.games/gosol/ui"
-)
-
 // Settings holds user preferences.
 // Colors are named from the web extended colors at https://en.wikipedia.org/wiki/Web_colors
 type Settings struct {
@@ -30,13 +25,16 @@ type Settings struct {
 	AlwaysShowMovableCards             bool
 	CardRatio                          float64
 	AniSpeed                           float64
+	PySolDeals                         bool
+	WinnableDeals                      map[string]bool `json:",omitempty"` // variants that only get deals a solver can win
 	LastVersionMajor, LastVersionMinor int
 	// FixedCards                         bool
 	// FixedCardWidth, FixedCardHeight    int
 }
 
-func NewSettings() *Settings {
-	s := &Settings{
+// DefaultSettings returns the settings a new player starts with
+func DefaultSettings() *Settings {
+	return &Settings{
 		Variant:                "Klondike",
 		BaizeColor:             "BaizeGreen",
 		PowerMoves:             true,
@@ -64,40 +62,28 @@ func NewSettings() *Settings {
 		LastVersionMajor: 0,
 		LastVersionMinor: 0,
 	}
+}
+
+// NewSettings returns the default settings, overwritten by any saved settings
+func NewSettings() *Settings {
+	s := DefaultSettings()
 	s.Load()
 	return s
 }
 
-func ShowSettingsDrawer() {
-	var BooleanSettings = []ui.BooleanSetting{
-		{Title: "Power moves", Var: &TheGame.Settings.PowerMoves},
-		{Title: "Auto collect", Var: &TheGame.Settings.AutoCollect},
-		{Title: "Safe collect", Var: &TheGame.Settings.SafeCollect},
-		{Title: "Show movable cards", Var: &TheGame.Settings.ShowMovableCards},
-		{Title: "Colorful cards", Var: &TheGame.Settings.ColorfulCards, Update: func() { TheGame.Baize.setFlag(dirtyCardImages) }},
-		{Title: "Mute sounds", Var: &TheGame.Settings.Mute, Update: func() {
-			if TheGame.Settings.Mute {
-				sound.SetVolume(0.0)
-			} else {
-				sound.SetVolume(TheGame.Settings.Volume)
-			}
-		}},
-		{Title: "Mirror baize", Var: &TheGame.Settings.MirrorBaize, Update: func() {
-			savedUndoStack := TheGame.Baize.undoStack
-			TheGame.Baize.StartFreshGame()
-			TheGame.Baize.SetUndoStack(savedUndoStack)
-		}},
-	}
-
-	TheGame.UI.ShowSettingsDrawer(&BooleanSettings)
+// WinnableDealsOnly returns true if the player only wants deals of variant v that a solver has proven can be won
+func (s *Settings) WinnableDealsOnly(v string) bool {
+	return s.WinnableDeals[v]
 }
 
-func ShowAniSpeedDrawer() {
-	var AniSpeedSettings = []ui.FloatSetting{
-		{Title: "Fast", Var: &TheGame.Settings.AniSpeed, Value: 0.3},
-		{Title: "Normal", Var: &TheGame.Settings.AniSpeed, Value: 0.6},
-		{Title: "Slow", Var: &TheGame.Settings.AniSpeed, Value: 0.9},
+// SetWinnableDealsOnly turns winnable deals on or off for variant v
+func (s *Settings) SetWinnableDealsOnly(v string, on bool) {
+	if s.WinnableDeals == nil {
+		s.WinnableDeals = make(map[string]bool)
+	}
+	if on {
+		s.WinnableDeals[v] = true
+	} else {
+		delete(s.WinnableDeals, v)
 	}
-
-	TheGame.UI.ShowAniSpeedDrawer(&AniSpeedSettings)
 }
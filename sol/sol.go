@@ -0,0 +1,17 @@
+// Package sol provides a polymorphic solitaire engine.
+//
+// Package sol knows nothing about windows, sounds or toasts, so a Baize
+// can be built, dealt and played from a test, a command line tool or a solver.
+// A front end (eg package game) watches a Baize through the Observer interface.
+package sol
+
+var (
+	// GosolVersionMajor is the integer version number
+	GosolVersionMajor int = 5
+	// CsolVersionMinor is the integer version number
+	GosolVersionMinor int = 15
+	// CSolVersionDate is the ISO 8601 date of bumping the version number
+	GosolVersionDate string = "2023-02-26"
+	// DebugMode is a boolean set by command line flag -debug
+	DebugMode bool = false
+)
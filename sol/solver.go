@@ -0,0 +1,234 @@
+package sol
+
+//lint:file-ignore ST1006 Receiver name will be anything I like, thank you
+
+import (
+	"context"
+	"runtime"
+	"sort"
+	"time"
+)
+
+// SolverOutcome says how a search ended
+type SolverOutcome int
+
+const (
+	SOLVED     SolverOutcome = iota // found a way to win
+	UNSOLVABLE                      // looked at every reachable position, there is no way to win
+	GAVE_UP                         // ran out of nodes or time, or was cancelled
+)
+
+func (o SolverOutcome) String() string {
+	switch o {
+	case SOLVED:
+		return "solved"
+	case UNSOLVABLE:
+		return "unsolvable"
+	default:
+		return "gave up"
+	}
+}
+
+// SolverResult is what a Solver found
+type SolverResult struct {
+	Outcome SolverOutcome
+	Moves   []Move // the winning moves, if Outcome is SOLVED
+	Nodes   int    // number of positions expanded
+}
+
+// Solver searches for a winning sequence of moves from a position.
+//
+// It plays on its own off-screen Baize, using the variant's script
+// exactly as a player would (see Baize.LegalMoves and Baize.ApplyMove),
+// so any variant can be solved without the solver knowing its rules.
+// The search is depth first, most promising moves first,
+// with a transposition table so no position is expanded twice.
+type Solver struct {
+	MaxNodes int           // give up after expanding this many positions, 0 means no limit
+	MaxTime  time.Duration // give up after this long, 0 means no limit
+
+	b        *Baize
+	visited  map[uint64]bool
+	path     []Move
+	nodes    int
+	deadline time.Time
+	ctx      context.Context
+	gaveUp   bool
+}
+
+// NewSolver makes a solver for a position in a variant.
+// The position is copied, so the caller is free to carry on using it.
+func NewSolver(variant string, settings *Settings, sav *SavableBaize) *Solver {
+	var s Settings
+	if settings != nil {
+		s = *settings
+	} else {
+		s = *DefaultSettings()
+	}
+	// the solver decides what to collect
+	s.AutoCollect = false
+	b := NewBaize(variant, &s)
+	if b == nil {
+		return nil
+	}
+	b.StartFreshGame()
+	if !b.isSavableOk(sav) {
+		return nil
+	}
+	b.updateFromSavable(sav)
+	b.restartLog()
+	b.FindDestinations()
+	return &Solver{b: b, MaxNodes: 100000, MaxTime: 10 * time.Second}
+}
+
+// NewSolver makes a solver for the current position on this baize.
+// Call it on the goroutine that owns the baize; the solver can then be run on any other.
+func (b *Baize) NewSolver() *Solver {
+	return NewSolver(b.variant, b.settings, b.UndoPeek())
+}
+
+// Solve searches until it finds a win, proves there isn't one,
+// hits MaxNodes or MaxTime, or ctx is cancelled.
+func (s *Solver) Solve(ctx context.Context) SolverResult {
+	s.ctx = ctx
+	s.visited = make(map[uint64]bool)
+	s.path = nil
+	s.nodes = 0
+	s.gaveUp = false
+	if s.MaxTime > 0 {
+		s.deadline = time.Now().Add(s.MaxTime)
+	}
+	if s.search() {
+		moves := make([]Move, len(s.path))
+		copy(moves, s.path)
+		return SolverResult{Outcome: SOLVED, Moves: moves, Nodes: s.nodes}
+	}
+	if s.gaveUp {
+		return SolverResult{Outcome: GAVE_UP, Nodes: s.nodes}
+	}
+	return SolverResult{Outcome: UNSOLVABLE, Nodes: s.nodes}
+}
+
+// shorten takes the winning path found by Solve, and cuts out any detours,
+// by looking for a move from each position that jumps further along the path.
+// Returns the shorter path, and the number of positions on it that had only one legal move.
+func (s *Solver) shorten() ([]Move, int) {
+	b := s.b
+	// the move log holds every position on the winning path
+	states := b.positions()
+	index := make(map[uint64]int, len(states))
+	for i, sav := range states {
+		b.updateFromSavable(sav)
+		index[b.Hash()] = i
+	}
+
+	var moves []Move
+	var forced int
+	for i := 0; i < len(states)-1; {
+		b.updateFromSavable(states[i])
+		b.restartLog()
+		legal := b.LegalMoves()
+		if len(legal) == 1 {
+			forced++
+		}
+		best, bestMove := i+1, s.path[i]
+		for _, m := range legal {
+			if !b.ApplyMove(m) {
+				continue
+			}
+			if j, ok := index[b.Hash()]; ok && j > best {
+				best, bestMove = j, m
+			}
+			b.rewindTo(0)
+		}
+		moves = append(moves, bestMove)
+		i = best
+	}
+	return moves, forced
+}
+
+func (s *Solver) outOfBudget() bool {
+	if s.MaxNodes > 0 && s.nodes >= s.MaxNodes {
+		return true
+	}
+	// checking the clock and the context is slower than expanding a node
+	if s.nodes%256 == 0 {
+		if s.MaxTime > 0 && time.Now().After(s.deadline) {
+			return true
+		}
+		if s.ctx != nil && s.ctx.Err() != nil {
+			return true
+		}
+		// let the game carry on, in case it's sharing a thread with us (wasm)
+		runtime.Gosched()
+	}
+	return false
+}
+
+func (s *Solver) search() bool {
+	b := s.b
+	if b.Complete() {
+		return true
+	}
+	key := b.Hash()
+	if s.visited[key] {
+		return false
+	}
+	if s.outOfBudget() {
+		s.gaveUp = true
+		return false
+	}
+	s.visited[key] = true
+	s.nodes++
+
+	moves := b.LegalMoves()
+	scores := make(map[Move]int, len(moves))
+	for _, m := range moves {
+		scores[m] = b.moveScore(m)
+	}
+	sort.SliceStable(moves, func(i, j int) bool { return scores[moves[i]] > scores[moves[j]] })
+
+	depth := b.MovesMade()
+	for _, m := range moves {
+		if !b.ApplyMove(m) {
+			continue
+		}
+		s.path = append(s.path, m)
+		if s.search() {
+			return true
+		}
+		s.path = s.path[:len(s.path)-1]
+		// back to where we were before the move
+		b.rewindTo(depth)
+		if s.gaveUp {
+			return false
+		}
+	}
+	return false
+}
+
+// moveScore guesses how good a move is, so the solver tries the best first.
+// Moving to a foundation is best, then moves that turn over or free up a card;
+// dealing from the stock is a last resort.
+func (b *Baize) moveScore(m Move) int {
+	src := b.piles[m.Src]
+	if m.Dst < 0 {
+		return -100
+	}
+	var score int
+	switch b.piles[m.Dst].vtable.(type) {
+	case *Foundation, *Discard:
+		score += 100
+	case *Cell:
+		score -= 10
+	}
+	if m.N == src.Len() {
+		score += 20 // empties a pile
+	} else if src.cards[src.Len()-m.N-1].Prone() {
+		score += 50 // turns over a card
+	}
+	if dst := b.piles[m.Dst]; !dst.Empty() && dst.Peek().Suit() == src.cards[src.Len()-m.N].Suit() {
+		score += 5
+	}
+	return score
+}
@@ -0,0 +1,59 @@
+package sol
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSolverSolves(t *testing.T) {
+	b := NewBaize("Klondike", nil)
+	b.StartFreshGame()
+	b.NewDealFromSeed(1)
+	r := b.NewSolver().Solve(context.Background())
+	if r.Outcome != SOLVED {
+		t.Fatalf("Klondike deal #1: %s after %d nodes", r.Outcome, r.Nodes)
+	}
+	if b.MovesMade() != 0 {
+		t.Fatal("solver changed the baize it was asked about")
+	}
+	// the solution must work when played on the real baize
+	for i, m := range r.Moves {
+		if !b.ApplyMove(m) {
+			t.Fatalf("move %d (%s) did nothing", i, m)
+		}
+		checkCards(t, b)
+	}
+	if !b.Complete() {
+		t.Error("solution did not complete the game")
+	}
+}
+
+func TestSolverUnsolvable(t *testing.T) {
+	// Simple Simon deal #1 has three moves, all of them dead ends
+	b := NewBaize("Simple Simon", nil)
+	b.StartFreshGame()
+	b.NewDealFromSeed(1)
+	if r := b.NewSolver().Solve(context.Background()); r.Outcome != UNSOLVABLE {
+		t.Errorf("Simple Simon deal #1: %s after %d nodes", r.Outcome, r.Nodes)
+	}
+}
+
+func TestSolverLimits(t *testing.T) {
+	b := NewBaize("Freecell", nil)
+	b.StartFreshGame()
+	b.NewDealFromSeed(1)
+
+	s := b.NewSolver()
+	s.MaxNodes = 10
+	if r := s.Solve(context.Background()); r.Outcome != GAVE_UP || r.Nodes > 10 {
+		t.Errorf("node limit: %s after %d nodes", r.Outcome, r.Nodes)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s = b.NewSolver()
+	s.MaxNodes = 0
+	if r := s.Solve(ctx); r.Outcome != GAVE_UP {
+		t.Errorf("cancelled: %s after %d nodes", r.Outcome, r.Nodes)
+	}
+}
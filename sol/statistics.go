@@ -15,6 +15,13 @@ type Statistics struct {
 type VariantStatistics struct {
 	// PascalCase for JSON
 	Won, Lost, CurrStreak, BestStreak, WorstStreak, SumPercents, BestPercent, BestMoves, WorstMoves, SumMoves int `json:",omitempty"`
+	// BestMovesSeed is the deal that was won in BestMoves moves, so it can be replayed
+	BestMovesSeed uint64 `json:",omitempty"`
+	// Winnable holds the statistics for deals that were proven winnable before they were dealt,
+	// kept apart so they don't flatter the win rate of random deals
+	Winnable *VariantStatistics `json:",omitempty"`
+	// Games is a record of the most recent games, newest last
+	Games []GameRecord `json:",omitempty"`
 	// Won is number of games with 100%
 	// Lost is number of games with % less than 100
 	// Won + Lost is total number of games played (won or abandoned)
@@ -22,6 +29,19 @@ type VariantStatistics struct {
 	// average % is (sum of Percents) + (100 * Won) / (Won+Lost)
 }
 
+// GameRecord is what is remembered about one finished game
+type GameRecord struct {
+	Seed       uint64      `json:",omitempty"`
+	Won        bool        `json:",omitempty"`
+	Moves      int         `json:",omitempty"`
+	Percent    int         `json:",omitempty"`
+	Difficulty *Difficulty `json:",omitempty"`
+	Hints      int         `json:",omitempty"`
+}
+
+// maxGameRecords is how many GameRecords are kept for each variant
+const maxGameRecords = 100
+
 // NewStatistics creates a new Statistics object (a map)
 // and loads the saved statistics into it from file
 func NewStatistics() *Statistics {
@@ -62,7 +82,11 @@ func (stats *VariantStatistics) strings(v string) []string {
 			strs = append(strs, fmt.Sprintf("Best percent: %d%%", stats.BestPercent))
 		} else {
 			// won at least one game
-			strs = append(strs, fmt.Sprintf("Best number of moves: %d", stats.BestMoves))
+			if stats.BestMovesSeed != 0 {
+				strs = append(strs, fmt.Sprintf("Best number of moves: %d (deal #%d)", stats.BestMoves, stats.BestMovesSeed))
+			} else {
+				strs = append(strs, fmt.Sprintf("Best number of moves: %d", stats.BestMoves))
+			}
 			strs = append(strs, fmt.Sprintf("Worst number of moves: %d", stats.WorstMoves))
 			strs = append(strs, fmt.Sprintf("Average number of moves: %d", stats.SumMoves/stats.Won))
 		}
@@ -76,6 +100,30 @@ func (stats *VariantStatistics) strings(v string) []string {
 		if stats.WorstStreak != 0 {
 			strs = append(strs, fmt.Sprintf("Worst streak: %d", stats.WorstStreak))
 		}
+
+		// how the recent rated deals went
+		var won, played = map[string]int{}, map[string]int{}
+		var hints, hinted int
+		for _, g := range stats.Games {
+			if g.Hints > 0 {
+				hints += g.Hints
+				hinted++
+			}
+			if g.Difficulty != nil {
+				played[g.Difficulty.Rating()]++
+				if g.Won {
+					won[g.Difficulty.Rating()]++
+				}
+			}
+		}
+		for _, rating := range []string{EASY, MEDIUM, HARD} {
+			if played[rating] > 0 {
+				strs = append(strs, fmt.Sprintf("Recent %s deals won: %d of %d", rating, won[rating], played[rating]))
+			}
+		}
+		if hinted > 0 {
+			strs = append(strs, fmt.Sprintf("Hints used: %d, in %d of the last %d games", hints, hinted, len(stats.Games)))
+		}
 	}
 
 	return strs
@@ -85,15 +133,22 @@ func (s *Statistics) strings() []string {
 	var strs []string = []string{}
 	var numPlayed, numWon, numLost int
 	for _, vs := range s.StatsMap {
-		numPlayed += vs.Won + vs.Lost
-		numWon += vs.Won
-		numLost += vs.Lost
+		won, lost := vs.Won, vs.Lost
+		if vs.Winnable != nil {
+			won += vs.Winnable.Won
+			lost += vs.Winnable.Lost
+		}
+		numPlayed += won + lost
+		numWon += won
+		numLost += lost
 	}
 	strs = append(strs, fmt.Sprintf("Played: %d", numPlayed))
 	strs = append(strs, fmt.Sprintf("Won: %d", numWon))
 	strs = append(strs, fmt.Sprintf("Lost: %d", numLost))
-	winRate := (numWon * 100) / (numPlayed)
-	strs = append(strs, fmt.Sprintf("Win rate: %d%%", winRate))
+	if numPlayed > 0 {
+		winRate := (numWon * 100) / (numPlayed)
+		strs = append(strs, fmt.Sprintf("Win rate: %d%%", winRate))
+	}
 	return strs
 }
 
@@ -107,14 +162,49 @@ func (s *Statistics) findVariant(v string) *VariantStatistics {
 	return vstats
 }
 
+// findDeals returns the statistics for either the random or the winnable deals of a variant
+func (s *Statistics) findDeals(v string, winnable bool) *VariantStatistics {
+	vstats := s.findVariant(v)
+	if !winnable {
+		return vstats
+	}
+	if vstats.Winnable == nil {
+		vstats.Winnable = &VariantStatistics{}
+	}
+	return vstats.Winnable
+}
+
 func (s *Statistics) Played(v string) int {
 	vstats := s.findVariant(v)
-	return vstats.Won + vstats.Lost
+	played := vstats.Won + vstats.Lost
+	if vstats.Winnable != nil {
+		played += vstats.Winnable.Won + vstats.Winnable.Lost
+	}
+	return played
 }
 
-func (s *Statistics) RecordWonGame(v string, moves int) string {
+// addGame remembers a finished game, forgetting the oldest if there are too many
+func (stats *VariantStatistics) addGame(b *Baize, won bool) {
+	stats.Games = append(stats.Games, GameRecord{
+		Seed:       b.Seed(),
+		Won:        won,
+		Moves:      b.MovesMade(),
+		Percent:    b.PercentComplete(),
+		Difficulty: b.Difficulty(),
+		Hints:      b.HintsUsed(),
+	})
+	if len(stats.Games) > maxGameRecords {
+		stats.Games = stats.Games[len(stats.Games)-maxGameRecords:]
+	}
+}
 
-	vstats := s.findVariant(v)
+// RecordWonGame records the game on b as won
+func (s *Statistics) RecordWonGame(b *Baize) string {
+
+	v := b.Variant()
+	moves := b.MovesMade()
+	seed := b.Seed()
+	vstats := s.findDeals(v, b.Winnable())
 
 	vstats.Won = vstats.Won + 1
 
@@ -131,20 +221,25 @@ func (s *Statistics) RecordWonGame(v string, moves int) string {
 
 	if vstats.BestMoves == 0 || moves < vstats.BestMoves {
 		vstats.BestMoves = moves
+		vstats.BestMovesSeed = seed
 	}
 	if vstats.WorstMoves == 0 || moves > vstats.WorstMoves {
 		vstats.WorstMoves = moves
 	}
 	vstats.SumMoves += moves
+	vstats.addGame(b, true)
 
 	s.Save()
 
 	return fmt.Sprintf("Recording completed game of %s", v)
 }
 
-func (s *Statistics) RecordLostGame(v string, percent int) string {
+// RecordLostGame records the game on b as lost (abandoned)
+func (s *Statistics) RecordLostGame(b *Baize) string {
 
-	vstats := s.findVariant(v)
+	v := b.Variant()
+	percent := b.PercentComplete()
+	vstats := s.findDeals(v, b.Winnable())
 
 	vstats.Lost = vstats.Lost + 1
 	// don't see that currStreak can ever be zero
@@ -161,17 +256,23 @@ func (s *Statistics) RecordLostGame(v string, percent int) string {
 		vstats.BestPercent = percent
 	}
 	vstats.SumPercents += percent
+	vstats.addGame(b, false)
 
 	s.Save()
 
 	return fmt.Sprintf("Recording lost game of %s, %d%% complete", v, percent)
 }
 
-func ShowStatisticsDrawer() {
-	vstats := TheGame.Statistics.findVariant(TheGame.Baize.variant)
-	var strs []string = vstats.strings(TheGame.Baize.variant)
+// Strings returns the statistics for variant v, followed by the totals for all variants
+func (s *Statistics) Strings(v string) []string {
+	vstats := s.findVariant(v)
+	var strs []string = vstats.strings(v)
+	if vstats.Winnable != nil {
+		strs = append(strs, " ")
+		strs = append(strs, vstats.Winnable.strings(v+" (winnable deals)")...)
+	}
 	strs = append(strs, " ") // n.b. can't use empty string
 	strs = append(strs, "ALL VARIANTS")
-	strs = append(strs, TheGame.Statistics.strings()...)
-	TheGame.UI.ShowTextDrawer(strs)
+	strs = append(strs, s.strings()...)
+	return strs
 }
@@ -0,0 +1,79 @@
+#!/usr/bin/env python3
+"""Print PySolFC layouts, for the expected values in pysoldeal_test.go.
+
+PySolFC shuffles games numbered 32000 and over with its MTRandom class, which is
+CPython's random.Random seeded with the game number, and then deals from the end
+of the talon as each game's startGame does. This follows PySolFC 2.x's
+pysolrandom.py (BasicRandom.shuffle) and the startGame methods in klondike.py,
+yukon.py, spider.py and fortythieves.py.
+
+    python3 sol/testdata/pysoldeals.py
+"""
+import random
+
+SUITS = "CSHD"  # the order PySolFC builds a deck in
+RANKS = "A23456789TJQK"
+
+
+def talon(game, decks):
+    cards = [r + s for _ in range(decks) for s in SUITS for r in RANKS]
+    rnd = random.Random(game)
+    n = len(cards) - 1
+    while n > 0:
+        j = rnd.randint(0, n)
+        cards[n], cards[j] = cards[j], cards[n]
+        n -= 1
+    return cards
+
+
+def deal_row(talon, rows, piles, reverse=False):
+    for i in (reversed(rows) if reverse else rows):
+        piles[i].append(talon.pop())
+
+
+def klondike(game):
+    t, piles = talon(game, 1), [[] for _ in range(7)]
+    for i in range(1, 7):
+        deal_row(t, range(i, 7), piles, reverse=True)
+    deal_row(t, range(7), piles, reverse=True)
+    return piles, t.pop()
+
+
+def yukon(game):
+    t, piles = talon(game, 1), [[] for _ in range(7)]
+    for i in range(1, 7):
+        deal_row(t, range(i, 7), piles)
+    for _ in range(4):
+        deal_row(t, range(1, 7), piles)
+    deal_row(t, range(7), piles)
+    return piles, None
+
+
+def spider(game):
+    t, piles = talon(game, 2), [[] for _ in range(10)]
+    for _ in range(4):
+        deal_row(t, range(10), piles)
+    deal_row(t, (0, 3, 6, 9), piles)
+    deal_row(t, range(10), piles)
+    return piles, None
+
+
+def forty_thieves(game):
+    t, piles = talon(game, 2), [[] for _ in range(10)]
+    for _ in range(4):
+        deal_row(t, range(10), piles)
+    return piles, t.pop()
+
+
+for name, fn, game in [
+    ("Klondike", klondike, 123456),
+    ("Klondike", klondike, 1000000000000),
+    ("Yukon", yukon, 123456),
+    ("Spider", spider, 123456),
+    ("Forty Thieves", forty_thieves, 123456),
+]:
+    piles, waste = fn(game)
+    layout = " | ".join(" ".join(p) for p in piles)
+    if waste:
+        layout += " | " + waste
+    print(f'{{"{name}", {game}, "{layout}"}},')
@@ -0,0 +1,76 @@
+-- Antares, by Thomas Warfield: the four left tableaux are FreeCell piles,
+-- and the four right tableaux are Scorpion piles
+
+Name = "My Antares"
+Wikipedia = "https://www.goodsol.com/games/antares.html"
+
+function BuildPiles()
+	NewStock(-5, -5)
+	for x = 0, 3 do
+		NewCell(x, 0)
+	end
+	for x = 5, 8 do
+		NewFoundation(x, 0):SetLabel("A")
+	end
+	for x = 0, 3 do
+		NewTableau(x, 1, "Down", "OnePlus")
+	end
+	for x = 5, 8 do
+		NewTableau(x, 1, "Down", "Any")
+	end
+end
+
+function StartGame()
+	for i, t in ipairs(Tableaux()) do
+		local n = 6
+		if i > 4 then
+			n = 7
+		end
+		for j = 1, n do
+			MoveCard(Stock(), t)
+		end
+	end
+	SetRecycles(0)
+end
+
+-- inFirstFour returns true if a pile is one of the FreeCell piles
+function inFirstFour(pile)
+	for i, t in ipairs(Tableaux()) do
+		if t == pile then
+			return i <= 4
+		end
+	end
+	return false
+end
+
+function TailMoveError(tail)
+	local pile = tail[1]:Owner()
+	if pile:Category() == "Tableau" and inFirstFour(pile) then
+		return TailConformant(tail, "DownAltColor")
+	end
+	-- else Scorpion rules - move anything anywhere
+end
+
+function TailAppendError(dst, tail)
+	local card = tail[1]
+	if dst:Category() == "Foundation" then
+		if dst:Empty() then
+			return Compare_Empty(dst, card)
+		end
+		return Compare_UpSuit(dst:Peek(), card)
+	elseif dst:Category() == "Tableau" then
+		if inFirstFour(card:Owner()) then
+			local err = TailConformant(tail, "DownAltColor")
+			if err then
+				return err
+			end
+		end
+		if dst:Empty() then
+			return Compare_Empty(dst, card)
+		elseif inFirstFour(dst) then
+			return Compare_DownAltColor(dst:Peek(), card)
+		else
+			return Compare_DownSuit(dst:Peek(), card)
+		end
+	end
+end
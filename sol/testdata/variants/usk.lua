@@ -0,0 +1,65 @@
+-- Usk, with its one redeal: the tableaux are picked up, left to right,
+-- and dealt out again, without shuffling, in the same shape
+
+Name = "My Usk"
+Wikipedia = "https://politaire.com/help/usk"
+
+-- how many cards are dealt to each tableau
+layout = {8, 8, 8, 7, 6, 5, 4, 3, 2, 1}
+
+function BuildPiles()
+	NewStock(0, 0)
+	for x = 6, 9 do
+		NewFoundation(x, 0):SetLabel("A")
+	end
+	for x = 0, 9 do
+		NewTableau(x, 1, "Down"):SetLabel("K")
+	end
+end
+
+function dealCards()
+	for i, t in ipairs(Tableaux()) do
+		for n = 1, layout[i] do
+			MoveCard(Stock(), t)
+		end
+	end
+end
+
+function StartGame()
+	dealCards()
+	SetRecycles(1)
+end
+
+function TailMoveError(tail)
+	if tail[1]:Owner():Category() == "Tableau" then
+		return TailConformant(tail, "DownAltColor")
+	end
+end
+
+function TailAppendError(dst, tail)
+	if dst:Empty() then
+		return Compare_Empty(dst, tail[1])
+	elseif dst:Category() == "Foundation" then
+		return Compare_UpSuit(dst:Peek(), tail[1])
+	else
+		return Compare_DownAltColor(dst:Peek(), tail[1])
+	end
+end
+
+function PileTapped(pile)
+	if pile ~= Stock() then
+		return
+	end
+	if Recycles() == 0 then
+		Toast("No more recycles")
+		return
+	end
+	for _, t in ipairs(Tableaux()) do
+		if not t:Empty() then
+			MoveTail(t:Get(1), Stock())
+		end
+	end
+	Stock():ReverseCards()
+	dealCards()
+	SetRecycles(0)
+end
@@ -0,0 +1,124 @@
+package sol
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// TournamentMaxMoves stops a game that a player is never going to finish
+var TournamentMaxMoves = 1000
+
+// TournamentResult is how one player got on at one variant
+type TournamentResult struct {
+	Variant, Player string
+	Games, Won      int
+	Moves           int // moves made, over all the games
+	Percent         int // percent complete, over all the games
+}
+
+// WinRate returns the percentage of games won
+func (r TournamentResult) WinRate() float64 {
+	if r.Games == 0 {
+		return 0
+	}
+	return float64(r.Won) * 100 / float64(r.Games)
+}
+
+// AverageMoves returns the average number of moves made in a game
+func (r TournamentResult) AverageMoves() float64 {
+	if r.Games == 0 {
+		return 0
+	}
+	return float64(r.Moves) / float64(r.Games)
+}
+
+// AveragePercent returns the average percent complete at the end of a game
+func (r TournamentResult) AveragePercent() float64 {
+	if r.Games == 0 {
+		return 0
+	}
+	return float64(r.Percent) / float64(r.Games)
+}
+
+func (r TournamentResult) String() string {
+	return fmt.Sprintf("%-24s %-10s %6d games %6.1f%% won %7.1f moves %5.1f%% complete",
+		r.Variant, r.Player, r.Games, r.WinRate(), r.AverageMoves(), r.AveragePercent())
+}
+
+// PlayGame deals deal #seed of a variant on a baize of its own, and lets a player play it
+// until it is won, the player gives up or makes an illegal move, or TournamentMaxMoves have been made.
+// Returns the baize, so the caller can see how it ended.
+func PlayGame(variant string, settings *Settings, seed uint64, p Player) *Baize {
+	b := NewBaize(variant, settings)
+	if b == nil {
+		return nil
+	}
+	b.StartFreshGame()
+	b.NewDealFromSeed(seed)
+	for b.MovesMade() < TournamentMaxMoves && !b.Complete() {
+		m, ok := p.ChooseMove(b)
+		if !ok || !b.ApplyMove(m) {
+			break
+		}
+	}
+	return b
+}
+
+// Tournament has each player play the same deals of each variant, and reports how they got on.
+// The deals are numbered from firstSeed.
+// Results come back in order of variant, then player.
+func Tournament(ctx context.Context, variants []string, players []string, deals int, firstSeed uint64) []TournamentResult {
+	// the players decide what to collect, and the deals are always numbered the same
+	settings := DefaultSettings()
+	settings.AutoCollect = false
+	settings.PySolDeals = false
+
+	var results []TournamentResult
+	for _, v := range variants {
+		for _, name := range players {
+			newPlayer, ok := Players[name]
+			if !ok {
+				continue
+			}
+			r := TournamentResult{Variant: v, Player: name}
+			for seed := firstSeed; seed < firstSeed+uint64(deals); seed++ {
+				if ctx.Err() != nil {
+					return results
+				}
+				b := PlayGame(v, settings, seed, newPlayer(seed))
+				if b == nil {
+					break
+				}
+				r.Games++
+				if b.Complete() {
+					r.Won++
+				}
+				r.Moves += b.MovesMade()
+				r.Percent += b.PercentComplete()
+			}
+			results = append(results, r)
+		}
+	}
+	return results
+}
+
+// EasierAndHarder sorts the variants a player played in a tournament by win rate,
+// and returns those it won at least easy percent of, and those it won no more than hard percent of,
+// ready to replace the opinions in the "> Easier" and "> Harder" VariantGroups
+func EasierAndHarder(results []TournamentResult, player string, easy, hard float64) (easier, harder []string) {
+	for _, r := range results {
+		if r.Player != player || r.Games == 0 {
+			continue
+		}
+		switch {
+		case r.WinRate() >= easy:
+			easier = append(easier, r.Variant)
+		case r.WinRate() <= hard:
+			harder = append(harder, r.Variant)
+		}
+	}
+	sort.Strings(easier)
+	sort.Strings(harder)
+	return easier, harder
+}
@@ -3,14 +3,12 @@ package sol
 //lint:file-ignore ST1006 Receiver name will be anything I like, thank you
 
 import (
-	"image"
 	"log"
 
 	"oddstream.games/gosol/cardid"
-	"oddstream.games/gosol/sound"
 )
 
-// The CardID contains everything we need to serialize the card: pack, ordinal, suit and prone flag
+// The CardID contains everything we need to serialize the card: pack, ordinal, suit, which joker, and prone flag
 
 type SavablePile struct {
 	Category string          // for readability and sanity checks
@@ -18,10 +16,16 @@ type SavablePile struct {
 	Cards    []cardid.CardID `json:",omitempty"`
 }
 
+// SavableBaize is a copy of a position, as dealt, or in a move log snapshot
 type SavableBaize struct {
 	Piles    []*SavablePile `json:",omitempty"`
-	Bookmark int            `json:",omitempty"`
 	Recycles int            `json:",omitempty"`
+	Seed     uint64         `json:",omitempty"`
+	// games saved before there was a move log kept these with every position
+	Bookmark   int         `json:",omitempty"`
+	Winnable   bool        `json:",omitempty"`
+	Difficulty *Difficulty `json:",omitempty"`
+	Hints      int         `json:",omitempty"`
 }
 
 func (self *Pile) savable() *SavablePile {
@@ -32,7 +36,7 @@ func (self *Pile) savable() *SavablePile {
 	return sp
 }
 
-func (self *Pile) updateFromSavable(sp *SavablePile, cardPositionMap map[cardid.CardID]image.Point) {
+func (self *Pile) updateFromSavable(sp *SavablePile, cardMap map[cardid.CardID]*Card) {
 	if self.category != sp.Category {
 		log.Panicf("Baize pile (%s) and SavablePile (%s) are different", self.category, sp.Category)
 	}
@@ -40,24 +44,19 @@ func (self *Pile) updateFromSavable(sp *SavablePile, cardPositionMap map[cardid.
 	// undo
 	// a card that was face up (eg because it was top of pile)
 	// now needs to be face down (because it is no longer top of pile)
-	// card is created same face up/down as it was saved
-	// when it should be created face up, then flipped.
+	// card is restored same face up/down as it was saved
+	// when it should be restored face up, then flipped.
 
 	for _, cid := range sp.Cards {
-		// pos will be set by Pile.Push(), and then lerped to
-		// but we need to set a pos to lerp from
-		// the default of {0,0} looks wrong
-		// so we get the last known position to lerp from
-		// nb a lot of the time, the pos won't change
-		// ie we lerp from pos to pos, which is handled efficiently
-		pos, ok := cardPositionMap[cid]
+		// reuse the existing card object, so that anyone watching
+		// the baize (eg the front end) can keep track of it;
+		// nb a lot of the time, the card won't have moved
+		c, ok := cardMap[cid.PackSuitOrdinal()]
 		if !ok {
-			pos = cardPositionMap[cid.PackSuitOrdinal()]
-			// card was put in the map face up, but is now face down
-			// if still not ok, pos will be image.Point{0, 0}
+			c = &Card{}
 		}
-		var c Card = Card{id: cid, pos: pos}
-		self.Push(&c) // will always flip down if pile is Stock
+		c.id = cid
+		self.Push(c) // will always flip down if pile is Stock
 	}
 	if len(self.cards) != len(sp.Cards) {
 		log.Panicf("%s cards rebuilt incorrectly", self.category)
@@ -66,32 +65,193 @@ func (self *Pile) updateFromSavable(sp *SavablePile, cardPositionMap map[cardid.
 }
 
 func (b *Baize) newSavableBaize() *SavableBaize {
-	sb := &SavableBaize{Bookmark: b.bookmark, Recycles: b.recycles}
+	sb := &SavableBaize{Recycles: b.recycles, Seed: b.seed}
 	for _, p := range b.piles {
 		sb.Piles = append(sb.Piles, p.savable())
 	}
 	return sb
 }
 
+// UndoPush records what the last move did to the baize in the move log,
+// or, if the log is empty, starts it with a copy of the baize as dealt.
+// A new move after an undo leaves the moves that were undone behind as a branch,
+// unless it is the move that was undone, made again.
 func (b *Baize) UndoPush() {
 	sb := b.newSavableBaize()
-	b.undoStack = append(b.undoStack, sb)
+	if b.position == nil {
+		b.snapshots = []*SavableBaize{sb}
+		b.steps = nil
+		b.position = sb
+		b.hashes = []uint64{b.Hash()}
+		return
+	}
+	if len(b.redo) > 0 {
+		st := b.redo[len(b.redo)-1]
+		if next, ok := st.applyTo(b.position); ok && samePosition(next, sb) {
+			b.redo = b.redo[:len(b.redo)-1]
+			b.appendStep(st, sb)
+			return
+		}
+		b.branchOff()
+	}
+	b.appendStep(diffPositions(b.position, sb), sb)
+}
+
+// appendStep adds a step to the end of the move log; sb is the position it leads to
+func (b *Baize) appendStep(st *Step, sb *SavableBaize) {
+	b.steps = append(b.steps, st)
+	if len(b.steps)%SnapshotInterval == 0 {
+		b.snapshots = append(b.snapshots, sb)
+	}
+	b.position = sb
+	b.hashes = append(b.hashes, b.Hash())
 }
 
+// UndoPeek returns a copy of the current position, as recorded at the end of the move log.
+// The copy must not be changed; it stays the same object until the log changes.
 func (b *Baize) UndoPeek() *SavableBaize {
-	if len(b.undoStack) == 0 {
-		return nil
+	return b.position
+}
+
+// restartLog makes the current position the start of a fresh move log
+func (b *Baize) restartLog() {
+	b.snapshots, b.steps, b.position, b.redo = nil, nil, nil, nil
+	b.branches, b.hashes = nil, nil
+	b.UndoPush()
+}
+
+// positionAt rebuilds the position after the first n moves in the log,
+// starting from the nearest snapshot before it
+func (b *Baize) positionAt(n int) *SavableBaize {
+	sb := b.snapshots[n/SnapshotInterval]
+	for i := n / SnapshotInterval * SnapshotInterval; i < n; i++ {
+		sb, _ = b.steps[i].applyTo(sb)
 	}
-	return b.undoStack[len(b.undoStack)-1]
+	return sb
 }
 
-func (b *Baize) UndoPop() (*SavableBaize, bool) {
-	if len(b.undoStack) == 0 {
-		return &SavableBaize{}, false
+// positions rebuilds every position in the log, starting with the deal
+func (b *Baize) positions() []*SavableBaize {
+	sb := b.snapshots[0]
+	positions := []*SavableBaize{sb}
+	for _, st := range b.steps {
+		sb, _ = st.applyTo(sb)
+		positions = append(positions, sb)
+	}
+	return positions
+}
+
+func (b *Baize) cardMap() map[cardid.CardID]*Card {
+	var cardMap map[cardid.CardID]*Card = make(map[cardid.CardID]*Card)
+	b.ForeachCard(func(c *Card) { cardMap[c.id.PackSuitOrdinal()] = c })
+	return cardMap
+}
+
+// moveCards shifts the top n cards of one pile onto another, just as they are
+func moveCards(src *Pile, n int, dst *Pile) {
+	tail := src.cards[len(src.cards)-n:]
+	for _, c := range tail {
+		c.SetOwner(dst)
+	}
+	dst.cards = append(dst.cards, tail...)
+	src.cards = src.cards[:len(src.cards)-n]
+	src.rehash()
+	dst.rehash()
+}
+
+// replaceCards replaces the cards above the bottom keep cards of a pile
+func (self *Pile) replaceCards(keep int, ids []cardid.CardID, cardMap map[cardid.CardID]*Card) {
+	self.cards = self.cards[:keep]
+	for _, cid := range ids {
+		c, ok := cardMap[cid.PackSuitOrdinal()]
+		if !ok {
+			c = &Card{}
+		}
+		c.id = cid
+		c.SetOwner(self)
+		self.cards = append(self.cards, c)
+	}
+	self.rehash()
+}
+
+// redoStep makes the changes a step records to the cards on the baize again
+func (b *Baize) redoStep(st *Step) {
+	for _, m := range st.Moves {
+		moveCards(b.piles[m.Src], m.N, b.piles[m.Dst])
+	}
+	if len(st.Cards) > 0 {
+		cardMap := b.cardMap()
+		for _, sc := range st.Cards {
+			b.piles[sc.Pile].replaceCards(sc.Keep, sc.New, cardMap)
+		}
+	}
+	for _, f := range st.Flips {
+		c := b.piles[f.Pile].cards[f.Index]
+		c.SetProne(!c.Prone())
+	}
+	for _, l := range st.Labels {
+		b.piles[l.Pile].SetLabel(l.New)
+	}
+	b.recycles += st.Recycles
+	if st.Recycles != 0 {
+		b.pilesChanged() // recreate Stock placeholder
+	}
+	b.cardsChanged()
+}
+
+// undoStep takes back what a step did to the cards on the baize,
+// without having to rebuild every pile
+func (b *Baize) undoStep(st *Step) {
+	b.recycles -= st.Recycles
+	for _, l := range st.Labels {
+		b.piles[l.Pile].SetLabel(l.Old)
+	}
+	for i := len(st.Flips) - 1; i >= 0; i-- {
+		c := b.piles[st.Flips[i].Pile].cards[st.Flips[i].Index]
+		c.SetProne(!c.Prone())
+	}
+	if len(st.Cards) > 0 {
+		cardMap := b.cardMap()
+		for _, sc := range st.Cards {
+			b.piles[sc.Pile].replaceCards(sc.Keep, sc.Old, cardMap)
+		}
+	}
+	for i := len(st.Moves) - 1; i >= 0; i-- {
+		m := st.Moves[i]
+		moveCards(b.piles[m.Dst], m.N, b.piles[m.Src])
+	}
+	if st.Recycles != 0 {
+		b.pilesChanged() // recreate Stock placeholder
+	}
+	b.cardsChanged()
+}
+
+// takeBack takes back moves until only the first n in the log have been made,
+// keeping them so they can be redone
+func (b *Baize) takeBack(n int) {
+	for i := len(b.steps) - 1; i >= n && i >= 0; i-- {
+		b.redo = append(b.redo, b.steps[i])
+	}
+	b.rewindTo(n)
+}
+
+// rewindTo takes back moves until only the first n in the log have been made
+func (b *Baize) rewindTo(n int) {
+	if n < 0 || n >= len(b.steps) {
+		return
 	}
-	sav := b.undoStack[len(b.undoStack)-1]
-	b.undoStack = b.undoStack[:len(b.undoStack)-1]
-	return sav, true
+	if len(b.steps)-n > SnapshotInterval {
+		// quicker to start again from a snapshot
+		b.updateFromSavable(b.positionAt(n))
+	} else {
+		for i := len(b.steps) - 1; i >= n; i-- {
+			b.undoStep(b.steps[i])
+		}
+	}
+	b.steps = b.steps[:n]
+	b.snapshots = b.snapshots[:n/SnapshotInterval+1]
+	b.position = b.newSavableBaize()
+	b.hashes = b.hashes[:n+1]
 }
 
 func (b *Baize) isSavableOk(sb *SavableBaize) bool {
@@ -108,16 +268,47 @@ func (b *Baize) isSavableOk(sb *SavableBaize) bool {
 	return true
 }
 
-func (b *Baize) isSavableStackOk(stack []*SavableBaize) bool {
-	if stack == nil {
-		log.Print("No savable stack")
+// isMoveLogOk checks that a move log is for this baize, and that every step in it can be made
+func (b *Baize) isMoveLogOk(ml *MoveLog) bool {
+	if ml == nil || len(ml.Snapshots) == 0 {
+		log.Print("No move log")
 		return false
 	}
-	for i := 0; i < len(stack); i++ {
-		if !b.isSavableOk(stack[i]) {
+	for _, sb := range ml.Snapshots {
+		if !b.isSavableOk(sb) {
+			return false
+		}
+	}
+	sb := ml.Snapshots[0]
+	for i, st := range ml.Steps {
+		var ok bool
+		if sb, ok = st.applyTo(sb); !ok {
+			log.Printf("Move %d in the move log cannot be made", i+1)
+			return false
+		}
+	}
+	for i := len(ml.Redo) - 1; i >= 0; i-- {
+		var ok bool
+		if sb, ok = ml.Redo[i].applyTo(sb); !ok {
+			log.Print("Moves to redo in the move log cannot be made")
 			return false
 		}
 	}
+	branches, ok := ml.branches()
+	if !ok {
+		log.Print("Branches in the move log do not fit together")
+		return false
+	}
+	for i, br := range branches {
+		sb := ml.Snapshots[0]
+		for _, st := range br.steps {
+			var ok bool
+			if sb, ok = st.applyTo(sb); !ok {
+				log.Printf("Branch %d in the move log cannot be played", i+1)
+				return false
+			}
+		}
+	}
 	return true
 }
 
@@ -125,96 +316,118 @@ func (b *Baize) updateFromSavable(sb *SavableBaize) {
 	if len(b.piles) != len(sb.Piles) {
 		log.Panicf("Baize piles (%d) and SavableBaize piles (%d) are different", len(b.piles), len(sb.Piles))
 	}
-	var cardPositionMap map[cardid.CardID]image.Point = make(map[cardid.CardID]image.Point)
-	b.ForeachCard(func(c *Card) { cardPositionMap[c.id] = c.pos })
-
+	cardMap := b.cardMap()
 	for i := 0; i < len(sb.Piles); i++ {
-		b.piles[i].updateFromSavable(sb.Piles[i], cardPositionMap)
+		b.piles[i].updateFromSavable(sb.Piles[i], cardMap)
 	}
-	sound.Play("TakeOutPackage")
-	b.bookmark = sb.Bookmark
 	b.recycles = sb.Recycles
-	b.setFlag(dirtyCardPositions)
+	b.pilesChanged()
+	b.cardsChanged()
 }
 
-// Undo reverts the Baize state to it's previous state
-func (b *Baize) Undo() {
-	if len(b.undoStack) < 2 {
-		TheGame.UI.ToastError("Nothing to undo")
-		return
+// MoveLog returns the game played on this baize so far, eg to be saved
+func (b *Baize) MoveLog() *MoveLog {
+	return &MoveLog{
+		Interval:   SnapshotInterval,
+		Seed:       b.seed,
+		Winnable:   b.winnable,
+		Difficulty: b.difficulty,
+		Hints:      b.hints,
+		Bookmarks:  b.bookmarks,
+		Snapshots:  b.snapshots,
+		Steps:      b.steps,
+		Redo:       b.redo,
+		Branches:   b.savedBranches(),
 	}
-	if b.Complete() {
-		TheGame.UI.ToastError("Cannot undo a completed game") // otherwise the stats can be cooked
-		return
+}
+
+// SetMoveLog replaces the game on this baize with one from a move log (eg loaded from a saved game)
+// and puts the cards where the end of the log says they should be
+func (b *Baize) SetMoveLog(ml *MoveLog) {
+	b.seed = ml.Seed // will be 0 in games saved before seeds were used
+	b.winnable = ml.Winnable
+	b.difficulty = ml.Difficulty
+	b.hints = ml.Hints
+	b.bookmarks = ml.Bookmarks
+	if ml.Bookmark > 0 && len(ml.Bookmarks) == 0 {
+		b.bookmarks = []Bookmark{{Name: bookmarkName(ml.Bookmark - 1), Moves: ml.Bookmark - 1}}
 	}
-	var saved bool = TheGame.Settings.AutoCollect
-	TheGame.Settings.AutoCollect = false
-	_, ok := b.UndoPop() // removes current state
-	if !ok {
-		log.Panic("error popping current state from undo stack")
+	b.steps = ml.Steps
+	b.redo = ml.Redo
+	b.removeBookmarksAfter(len(b.steps) + len(b.redo))
+	b.branches, _ = ml.branches() // isMoveLogOk has checked them
+	if ml.Interval == SnapshotInterval && len(ml.Snapshots) == len(ml.Steps)/SnapshotInterval+1 {
+		b.snapshots = ml.Snapshots
+	} else {
+		// take the snapshots again
+		b.snapshots = ml.Snapshots[:1]
+		sb := b.snapshots[0]
+		for i, st := range b.steps {
+			sb, _ = st.applyTo(sb)
+			if (i+1)%SnapshotInterval == 0 {
+				b.snapshots = append(b.snapshots, sb)
+			}
+		}
 	}
-	sav, ok := b.UndoPop() // removes previous state for examination
-	if !ok {
-		log.Panic("error popping second state from undo stack")
+	b.position = b.positionAt(len(b.steps))
+	b.hashes = nil
+	for _, sb := range b.positions() {
+		b.hashes = append(b.hashes, sb.hash())
 	}
-	b.updateFromSavable(sav)
-	b.UndoPush() // replace current state
+	b.toast("Glass", "Loaded a saved game of "+b.variant)
+	b.updateFromSavable(b.position)
 	b.FindDestinations()
-	TheGame.Settings.AutoCollect = saved
 }
 
-func (b *Baize) RestartDeal() {
-	if b.Complete() {
-		TheGame.UI.ToastError("Cannot restart a completed game") // otherwise the stats can be cooked
+// Undo reverts the Baize state to it's previous state
+func (b *Baize) Undo() {
+	if len(b.steps) == 0 {
+		b.toastError("Nothing to undo")
 		return
 	}
-	var sav *SavableBaize
-	var ok bool
-	for len(b.undoStack) > 0 {
-		sav, ok = b.UndoPop()
-		if !ok {
-			log.Panic("error popping from undo stack")
-		}
+	if b.Complete() {
+		b.toastError("Cannot undo a completed game") // otherwise the stats can be cooked
+		return
 	}
-	b.updateFromSavable(sav)
-	b.bookmark = 0 // do this AFTER UpdateFromSavable
-	b.UndoPush()   // replace current state
+	b.takeBack(len(b.steps) - 1)
+	b.playSound("TakeOutPackage")
 	b.FindDestinations()
 }
 
-// SavePosition saves the current Baize state
-func (b *Baize) SavePosition() {
-	if b.Complete() {
-		TheGame.UI.ToastError("Cannot bookmark a completed game") // otherwise the stats can be cooked
-		return
-	}
-	b.bookmark = len(b.undoStack)
-	sb := b.UndoPeek()
-	sb.Bookmark = b.bookmark
-	sb.Recycles = b.recycles
-	TheGame.UI.ToastInfo("Position bookmarked")
+// CanRedo returns true if there are undone moves that can be made again
+func (b *Baize) CanRedo() bool {
+	return len(b.redo) > 0
 }
 
-// LoadPosition loads a previously saved Baize state
-func (b *Baize) LoadPosition() {
-	if b.bookmark == 0 || b.bookmark > len(b.undoStack) {
-		// println("bookmark", b.bookmark, "undostack", len(b.undoStack))
-		TheGame.UI.ToastError("No bookmark")
+// Redo makes the last move that was undone again
+func (b *Baize) Redo() {
+	if len(b.redo) == 0 {
+		b.toastError("Nothing to redo")
 		return
 	}
 	if b.Complete() {
-		TheGame.UI.ToastError("Cannot undo a completed game") // otherwise the stats can be cooked
+		b.toastError("Cannot change a completed game") // otherwise the stats can be cooked
 		return
 	}
-	var sav *SavableBaize
-	var ok bool
-	for len(b.undoStack)+1 > b.bookmark {
-		sav, ok = b.UndoPop()
-		if !ok {
-			log.Panic("error popping from undo stack")
-		}
+	b.redoOne()
+	b.playSound("Slide")
+	b.FindDestinations()
+}
+
+// redoOne moves a step from the redo stack back onto the end of the move log
+func (b *Baize) redoOne() {
+	st := b.redo[len(b.redo)-1]
+	b.redo = b.redo[:len(b.redo)-1]
+	b.redoStep(st)
+	b.appendStep(st, b.newSavableBaize())
+}
+
+func (b *Baize) RestartDeal() {
+	if b.Complete() {
+		b.toastError("Cannot restart a completed game") // otherwise the stats can be cooked
+		return
 	}
-	b.updateFromSavable(sav)
-	b.UndoPush() // replace current state
+	b.takeBack(0)
+	b.playSound("TakeOutPackage")
 	b.FindDestinations()
 }
@@ -15,28 +15,28 @@ type Alhambra struct {
 
 func (self *Alhambra) BuildPiles() {
 
-	self.stock = NewStock(image.Point{0, 3}, FAN_NONE, 2, 4, nil, 0)
+	self.stock = NewStock(self.baize, image.Point{0, 3}, FAN_NONE, 2, 4, nil, 0)
 
 	// waste pile implemented as a tableau because cards may be built on it
 	self.tableaux = nil
-	t := NewTableau(image.Point{1, 3}, FAN_RIGHT3, MOVE_ONE)
+	t := NewTableau(self.baize, image.Point{1, 3}, FAN_RIGHT3, MOVE_ONE)
 	self.tableaux = append(self.tableaux, t)
 
 	self.foundations = nil
 	for x := 0; x < 4; x++ {
-		f := NewFoundation(image.Point{x, 0})
+		f := NewFoundation(self.baize, image.Point{x, 0})
 		self.foundations = append(self.foundations, f)
 		f.SetLabel("A")
 	}
 	for x := 4; x < 8; x++ {
-		f := NewFoundation(image.Point{x, 0})
+		f := NewFoundation(self.baize, image.Point{x, 0})
 		self.foundations = append(self.foundations, f)
 		f.SetLabel("K")
 	}
 
 	self.reserves = nil
 	for x := 0; x < 8; x++ {
-		r := NewReserve(image.Point{x, 1}, FAN_DOWN)
+		r := NewReserve(self.baize, image.Point{x, 1}, FAN_DOWN)
 		self.reserves = append(self.reserves, r)
 	}
 }
@@ -58,7 +58,7 @@ func (self *Alhambra) StartGame() {
 		}
 	}
 
-	TheGame.Baize.SetRecycles(2)
+	self.baize.SetRecycles(2)
 }
 
 func (*Alhambra) TailMoveError(tail []*Card) (bool, error) {
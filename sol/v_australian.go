@@ -12,19 +12,19 @@ type Australian struct {
 }
 
 func (self *Australian) BuildPiles() {
-	self.stock = NewStock(image.Point{0, 0}, FAN_NONE, 1, 4, nil, 0)
-	self.waste = NewWaste(image.Point{1, 0}, FAN_RIGHT3)
+	self.stock = NewStock(self.baize, image.Point{0, 0}, FAN_NONE, 1, 4, nil, 0)
+	self.waste = NewWaste(self.baize, image.Point{1, 0}, FAN_RIGHT3)
 
 	self.foundations = nil
 	for x := 4; x < 8; x++ {
-		f := NewFoundation(image.Point{x, 0})
+		f := NewFoundation(self.baize, image.Point{x, 0})
 		self.foundations = append(self.foundations, f)
 		f.SetLabel("A")
 	}
 
 	self.tableaux = nil
 	for x := 0; x < 8; x++ {
-		t := NewTableau(image.Point{x, 1}, FAN_DOWN, MOVE_ANY)
+		t := NewTableau(self.baize, image.Point{x, 1}, FAN_DOWN, MOVE_ANY)
 		self.tableaux = append(self.tableaux, t)
 		t.SetLabel("K")
 	}
@@ -37,7 +37,7 @@ func (self *Australian) StartGame() {
 		}
 	}
 	MoveCard(self.stock, self.waste)
-	TheGame.Baize.SetRecycles(0)
+	self.baize.SetRecycles(0)
 }
 
 func (self *Australian) AfterMove() {
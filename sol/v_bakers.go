@@ -15,23 +15,23 @@ type BakersDozen struct {
 
 func (self *BakersDozen) BuildPiles() {
 
-	self.stock = NewStock(image.Point{-5, -5}, FAN_NONE, 1, 4, nil, 0)
+	self.stock = NewStock(self.baize, image.Point{-5, -5}, FAN_NONE, 1, 4, nil, 0)
 
 	self.tableaux = nil
 	for x := 0; x < 7; x++ {
-		t := NewTableau(image.Point{x, 0}, FAN_DOWN, MOVE_ONE)
+		t := NewTableau(self.baize, image.Point{x, 0}, FAN_DOWN, MOVE_ONE)
 		self.tableaux = append(self.tableaux, t)
 		t.SetLabel("X")
 	}
 	for x := 0; x < 6; x++ {
-		t := NewTableau(image.Point{x, 3}, FAN_DOWN, MOVE_ONE)
+		t := NewTableau(self.baize, image.Point{x, 3}, FAN_DOWN, MOVE_ONE)
 		self.tableaux = append(self.tableaux, t)
 		t.SetLabel("X")
 	}
 
 	self.foundations = nil
 	for y := 0; y < 4; y++ {
-		f := NewFoundation(image.Point{9, y})
+		f := NewFoundation(self.baize, image.Point{9, y})
 		self.foundations = append(self.foundations, f)
 		f.SetLabel("A")
 	}
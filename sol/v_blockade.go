@@ -13,18 +13,18 @@ type Blockade struct {
 
 func (self *Blockade) BuildPiles() {
 
-	self.stock = NewStock(image.Point{0, 0}, FAN_NONE, 2, 4, nil, 0)
+	self.stock = NewStock(self.baize, image.Point{0, 0}, FAN_NONE, 2, 4, nil, 0)
 
 	self.foundations = nil
 	for x := 4; x < 12; x++ {
-		f := NewFoundation(image.Point{x, 0})
+		f := NewFoundation(self.baize, image.Point{x, 0})
 		self.foundations = append(self.foundations, f)
 		f.SetLabel("A")
 	}
 
 	self.tableaux = nil
 	for x := 0; x < 12; x++ {
-		t := NewTableau(image.Point{x, 1}, FAN_DOWN, MOVE_ANY)
+		t := NewTableau(self.baize, image.Point{x, 1}, FAN_DOWN, MOVE_ANY)
 		self.tableaux = append(self.tableaux, t)
 	}
 }
@@ -33,7 +33,7 @@ func (self *Blockade) StartGame() {
 	for _, pile := range self.tableaux {
 		MoveCard(self.stock, pile)
 	}
-	TheGame.Baize.SetRecycles(0)
+	self.baize.SetRecycles(0)
 }
 
 func (self *Blockade) AfterMove() {
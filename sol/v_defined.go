@@ -0,0 +1,188 @@
+package sol
+
+//lint:file-ignore ST1005 Error messages are toasted, so need to be capitalized
+//lint:file-ignore ST1006 Receiver name will be anything I like, thank you
+
+// Defined plays a variant described by a VariantDef, loaded from a data file
+type Defined struct {
+	ScriptBase
+	def   *VariantDef
+	rules map[*Pile]*PileDef
+	deals map[*Pile]string // the deal pattern of each pile
+}
+
+func (self *Defined) BuildPiles() {
+	self.rules = make(map[*Pile]*PileDef)
+	self.deals = make(map[*Pile]string)
+	self.cells, self.discards, self.foundations, self.reserves, self.tableaux = nil, nil, nil, nil, nil
+	for i := range self.def.Piles {
+		pd := &self.def.Piles[i]
+		fan, move := fanTypes[pd.Fan], moveTypes[pd.MoveType]
+		for j := 0; j < pd.repeat(); j++ {
+			var p *Pile
+			slot := pd.slot(j)
+			switch pd.Category {
+			case "Stock":
+				p = NewStock(self.baize, slot, fan, self.Packs(), self.Suits(), nil, self.Jokers())
+				self.stock = p
+			case "Waste":
+				p = NewWaste(self.baize, slot, fan)
+				self.waste = p
+			case "Foundation":
+				p = NewFoundation(self.baize, slot)
+				self.foundations = append(self.foundations, p)
+			case "Tableau":
+				p = NewTableau(self.baize, slot, fan, move)
+				self.tableaux = append(self.tableaux, p)
+			case "Cell":
+				p = NewCell(self.baize, slot)
+				self.cells = append(self.cells, p)
+			case "Reserve":
+				p = NewReserve(self.baize, slot, fan)
+				self.reserves = append(self.reserves, p)
+			case "Discard":
+				p = NewDiscard(self.baize, slot, fan)
+				self.discards = append(self.discards, p)
+			}
+			p.SetLabel(pd.Label)
+			self.rules[p] = pd
+			if len(pd.Deal) > 0 {
+				self.deals[p] = pd.Deal[j%len(pd.Deal)]
+			}
+		}
+	}
+}
+
+func (self *Defined) StartGame() {
+	if self.def.MicrosoftDeals {
+		self.stock.ShuffleMicrosoft(self.baize.seed)
+	}
+	// deal to the piles in the order they were built
+	var dealt = make(map[*Pile]int)
+	deal := func(p *Pile) bool {
+		pattern := self.deals[p]
+		if dealt[p] >= len(pattern) {
+			return false
+		}
+		if c := MoveCard(self.stock, p); c != nil && pattern[dealt[p]] == 'd' {
+			c.FlipDown()
+		}
+		dealt[p]++
+		return true
+	}
+	for more := true; more; {
+		more = false
+		for _, p := range self.baize.piles {
+			if self.def.DealByRows {
+				more = deal(p) || more
+			} else {
+				for deal(p) {
+				}
+			}
+		}
+	}
+	self.baize.SetRecycles(self.def.Stock.Recycles)
+	if self.def.Stock.DealTo == "Waste" {
+		self.dealToWaste()
+	}
+}
+
+func (self *Defined) dealToWaste() {
+	for i := 0; i < self.draw(); i++ {
+		MoveCard(self.stock, self.waste)
+	}
+}
+
+func (self *Defined) draw() int {
+	if self.def.Stock.Draw == 0 {
+		return 1
+	}
+	return self.def.Stock.Draw
+}
+
+func (self *Defined) AfterMove() {
+	if self.def.Stock.RefillWaste && self.waste.Len() == 0 && self.stock.Len() != 0 {
+		self.dealToWaste()
+	}
+}
+
+// sequence returns the rule the cards in a tail moved from a pile must follow, or nil if there isn't one
+func (self *Defined) sequence(pile *Pile) CardPairCompareFunc {
+	pd := self.rules[pile]
+	name := pd.Sequence
+	if name == "" {
+		name = pd.Build
+	}
+	fn, _ := compareFunc(name)
+	return fn
+}
+
+func (self *Defined) TailMoveError(tail []*Card) (bool, error) {
+	if fn := self.sequence(tail[0].Owner()); fn != nil {
+		return TailConformant(tail, fn)
+	}
+	return true, nil
+}
+
+func (self *Defined) TailAppendError(dst *Pile, tail []*Card) (bool, error) {
+	if dst.Empty() {
+		return Compare_Empty(dst, tail[0])
+	}
+	if fn, ok := compareFunc(self.rules[dst].Build); ok {
+		return fn(CardPair{dst.Peek(), tail[0]})
+	}
+	return true, nil
+}
+
+func (self *Defined) UnsortedPairs(pile *Pile) int {
+	fn := self.sequence(pile)
+	if fn == nil {
+		fn = func(CardPair) (bool, error) { return true, nil }
+	}
+	return UnsortedPairs(pile, fn)
+}
+
+func (self *Defined) TailTapped(tail []*Card) {
+	var pile *Pile = tail[0].Owner()
+	if pile != self.stock {
+		pile.vtable.TailTapped(tail)
+		return
+	}
+	switch self.def.Stock.DealTo {
+	case "Waste":
+		if len(tail) == 1 {
+			self.dealToWaste()
+		}
+	case "Tableaux":
+		if self.def.Stock.FillTableauxFirst {
+			var tabCards, emptyTabs int
+			for _, tab := range self.tableaux {
+				if tab.Len() == 0 {
+					emptyTabs++
+				} else {
+					tabCards += tab.Len()
+				}
+			}
+			if emptyTabs > 0 && tabCards >= len(self.tableaux) {
+				self.baize.toastError("All empty tableaux must be filled before dealing a new row")
+				return
+			}
+		}
+		for _, tab := range self.tableaux {
+			MoveCard(self.stock, tab)
+		}
+	}
+}
+
+func (self *Defined) PileTapped(pile *Pile) {
+	if pile == self.stock && self.def.Stock.DealTo == "Waste" {
+		RecycleWasteToStock(self.waste, self.stock)
+	}
+}
+
+func (self *Defined) Complete() bool {
+	if self.def.Complete == "Sequences" {
+		return self.SpiderComplete()
+	}
+	return self.ScriptBase.Complete()
+}
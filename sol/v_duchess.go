@@ -16,28 +16,28 @@ type Duchess struct {
 
 func (self *Duchess) BuildPiles() {
 
-	self.stock = NewStock(image.Point{1, 1}, FAN_NONE, 1, 4, nil, 0)
+	self.stock = NewStock(self.baize, image.Point{1, 1}, FAN_NONE, 1, 4, nil, 0)
 
 	self.reserves = []*Pile{}
 	for i := 0; i < 4; i++ {
-		self.reserves = append(self.reserves, NewReserve(image.Point{i * 2, 0}, FAN_RIGHT))
+		self.reserves = append(self.reserves, NewReserve(self.baize, image.Point{i * 2, 0}, FAN_RIGHT))
 	}
 
-	self.waste = NewWaste(image.Point{1, 2}, FAN_DOWN3)
+	self.waste = NewWaste(self.baize, image.Point{1, 2}, FAN_DOWN3)
 
 	self.foundations = []*Pile{}
 	for x := 3; x < 7; x++ {
-		self.foundations = append(self.foundations, NewFoundation(image.Point{x, 1}))
+		self.foundations = append(self.foundations, NewFoundation(self.baize, image.Point{x, 1}))
 	}
 
 	self.tableaux = []*Pile{}
 	for x := 3; x < 7; x++ {
-		self.tableaux = append(self.tableaux, NewTableau(image.Point{x, 2}, FAN_DOWN, MOVE_ANY))
+		self.tableaux = append(self.tableaux, NewTableau(self.baize, image.Point{x, 2}, FAN_DOWN, MOVE_ANY))
 	}
 }
 
 func (self *Duchess) StartGame() {
-	TheGame.Baize.SetRecycles(1)
+	self.baize.SetRecycles(1)
 	for _, pile := range self.foundations {
 		pile.SetLabel("")
 	}
@@ -50,7 +50,7 @@ func (self *Duchess) StartGame() {
 	for _, pile := range self.tableaux {
 		MoveCard(self.stock, pile)
 	}
-	TheGame.UI.ToastInfo("Move a Reserve card to a Foundation")
+	self.baize.toastInfo("Move a Reserve card to a Foundation")
 }
 
 func (self *Duchess) AfterMove() {
@@ -67,7 +67,7 @@ func (self *Duchess) AfterMove() {
 			}
 		}
 		if ord == 0 {
-			TheGame.UI.ToastInfo("Move a Reserve card to a Foundation")
+			self.baize.toastInfo("Move a Reserve card to a Foundation")
 		} else {
 			for _, f := range self.foundations {
 				f.SetLabel(util.OrdinalToShortString(ord))
@@ -33,19 +33,19 @@ func (self *FortyThieves) BuildPiles() {
 		self.tabCompareFunc = CardPair.Compare_DownSuit
 	}
 
-	self.stock = NewStock(image.Point{0, 0}, FAN_NONE, self.packs, 4, nil, 0)
-	self.waste = NewWaste(image.Point{1, 0}, FAN_RIGHT3)
+	self.stock = NewStock(self.baize, image.Point{0, 0}, FAN_NONE, self.packs, 4, nil, 0)
+	self.waste = NewWaste(self.baize, image.Point{1, 0}, FAN_RIGHT3)
 
 	self.foundations = nil
 	for _, x := range self.founds {
-		f := NewFoundation(image.Point{x, 0})
+		f := NewFoundation(self.baize, image.Point{x, 0})
 		self.foundations = append(self.foundations, f)
 		f.SetLabel("A")
 	}
 
 	self.tableaux = nil
 	for _, x := range self.tabs {
-		t := NewTableau(image.Point{x, 1}, FAN_DOWN, self.moveType)
+		t := NewTableau(self.baize, image.Point{x, 1}, FAN_DOWN, self.moveType)
 		self.tableaux = append(self.tableaux, t)
 	}
 }
@@ -77,9 +77,18 @@ func (self *FortyThieves) StartGame() {
 			self.foundations[7].Push(c)
 		}
 	}
-	for _, pile := range self.tableaux {
+	if self.baize.PySolDeals() {
+		// a row at a time, left to right, as PySolFC does
 		for i := 0; i < self.cardsPerTab; i++ {
-			MoveCard(self.stock, pile)
+			for _, pile := range self.tableaux {
+				MoveCard(self.stock, pile)
+			}
+		}
+	} else {
+		for _, pile := range self.tableaux {
+			for i := 0; i < self.cardsPerTab; i++ {
+				MoveCard(self.stock, pile)
+			}
 		}
 	}
 	for _, row := range self.proneRows {
@@ -87,7 +96,7 @@ func (self *FortyThieves) StartGame() {
 			pile.Get(row).FlipDown()
 		}
 	}
-	TheGame.Baize.SetRecycles(self.recycles)
+	self.baize.SetRecycles(self.recycles)
 	MoveCard(self.stock, self.waste)
 }
 
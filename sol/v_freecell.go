@@ -14,6 +14,7 @@ type Freecell struct {
 	ScriptBase
 	tabCompareFunc CardPairCompareFunc
 	blind, easy    bool
+	msDeals        bool // deal numbers are Microsoft FreeCell deal numbers
 }
 
 func (self *Freecell) BuildPiles() {
@@ -25,23 +26,23 @@ func (self *Freecell) BuildPiles() {
 		self.tabCompareFunc = CardPair.Compare_DownAltColor
 	}
 
-	self.stock = NewStock(image.Point{-5, -5}, FAN_NONE, 1, 4, nil, 0)
+	self.stock = NewStock(self.baize, image.Point{-5, -5}, FAN_NONE, 1, 4, nil, 0)
 
 	self.cells = []*Pile{}
 	for x := 0; x < 4; x++ {
-		self.cells = append(self.cells, NewCell(image.Point{x, 0}))
+		self.cells = append(self.cells, NewCell(self.baize, image.Point{x, 0}))
 	}
 
 	self.foundations = []*Pile{}
 	for x := 4; x < 8; x++ {
-		f := NewFoundation(image.Point{x, 0})
+		f := NewFoundation(self.baize, image.Point{x, 0})
 		self.foundations = append(self.foundations, f)
 		f.SetLabel("A")
 	}
 
 	self.tableaux = []*Pile{}
 	for x := 0; x < 8; x++ {
-		t := NewTableau(image.Point{x, 1}, FAN_DOWN, MOVE_ONE_PLUS)
+		t := NewTableau(self.baize, image.Point{x, 1}, FAN_DOWN, MOVE_ONE_PLUS)
 		self.tableaux = append(self.tableaux, t)
 	}
 }
@@ -57,6 +58,14 @@ func (self *Freecell) StartGame() {
 				MoveCard(self.stock, t)
 			}
 		}
+	} else if self.msDeals || self.baize.PySolDeals() {
+		// a row at a time, left to right, as Microsoft FreeCell and PySolFC do
+		if !self.baize.PySolDeals() {
+			self.stock.ShuffleMicrosoft(self.baize.seed)
+		}
+		for i := 0; self.stock.Len() > 0; i++ {
+			MoveCard(self.stock, self.tableaux[i%len(self.tableaux)])
+		}
 	} else {
 		// 4 piles of 7 cards
 		// 4 piles of 6 cards
@@ -0,0 +1,160 @@
+package sol
+
+//lint:file-ignore ST1005 Error messages are toasted, so need to be capitalized
+//lint:file-ignore ST1006 Receiver name will be anything I like, thank you
+
+import (
+	"errors"
+	"image"
+
+	"oddstream.games/gosol/cardid"
+)
+
+// Golf, TriPeaks and Black Hole have a single Foundation, which takes any exposed card
+// a rank up or down from the card on top of it. The game is won when the layout has been cleared.
+type Golf struct {
+	ScriptBase
+	tabs, cardsPerTab int  // the tableaux, and the cards dealt to each; with no tableaux, the layout is three peaks
+	blackHole         bool // the Ace of Spades starts on the foundation, and the rest of the pack is dealt to the layout
+	wrap              bool // an Ace can go on a King, and a King on an Ace
+}
+
+func (self *Golf) BuildPiles() {
+	if self.blackHole {
+		// every card is dealt, so the stock is kept off the baize
+		self.stock = NewStock(self.baize, image.Point{-5, -5}, FAN_NONE, 1, 4, nil, 0)
+		self.foundations = []*Pile{NewFoundation(self.baize, image.Point{4, 0})}
+	} else {
+		self.stock = NewStock(self.baize, image.Point{0, 0}, FAN_NONE, 1, 4, nil, 0)
+		self.foundations = []*Pile{NewFoundation(self.baize, image.Point{1, 0})}
+	}
+	self.foundations[0].SetWholePack()
+	self.tableaux, self.reserves = nil, nil
+	if self.tabs == 0 {
+		self.buildPeaks()
+		return
+	}
+	// up to nine tableaux in a row, with a row left between them for the cards to fan down into
+	for i := 0; i < self.tabs; i++ {
+		self.tableaux = append(self.tableaux, NewTableau(self.baize, image.Point{i % 9, 1 + i/9*2}, FAN_DOWN, MOVE_ONE))
+	}
+}
+
+// buildPeaks makes three peaks of overlapping reserves, four rows deep; the positions are in tenths of a slot,
+// and each card is overlapped by the cards half a slot either side of it in the row below
+func (self *Golf) buildPeaks() {
+	rows := [][]int{
+		{15, 45, 75},
+		{10, 20, 40, 50, 70, 80},
+		{5, 15, 25, 35, 45, 55, 65, 75, 85},
+		{0, 10, 20, 30, 40, 50, 60, 70, 80, 90},
+	}
+	var above map[int]*Pile
+	for row, xs := range rows {
+		var this map[int]*Pile = make(map[int]*Pile)
+		y := 10 + row*5
+		for _, x := range xs {
+			p := NewReserve(self.baize, image.Point{x / 10, y / 10}, FAN_NONE)
+			p.SetOffset(image.Point{x % 10, y % 10})
+			self.reserves = append(self.reserves, p)
+			this[x] = p
+		}
+		for x, p := range above {
+			p.SetCoveredBy(this[x-5], this[x+5])
+		}
+		above = this
+	}
+}
+
+func (self *Golf) StartGame() {
+	if self.blackHole {
+		if c := self.stock.Extract(0, 1, cardid.SPADE); c != nil {
+			self.foundations[0].Push(c)
+		}
+	}
+	for _, pile := range self.tableaux {
+		for i := 0; i < self.cardsPerTab; i++ {
+			MoveCard(self.stock, pile)
+		}
+	}
+	for _, pile := range self.reserves {
+		MoveCard(self.stock, pile)
+	}
+	for _, pile := range self.reserves {
+		if pile.Covered() {
+			pile.Peek().FlipDown()
+		}
+	}
+	if !self.blackHole {
+		MoveCard(self.stock, self.foundations[0])
+	}
+}
+
+// dealt returns the number of cards dealt to the layout
+func (self *Golf) dealt() int {
+	return len(self.tableaux)*self.cardsPerTab + len(self.reserves)
+}
+
+// layoutCards returns the number of cards left in the tableaux and reserves
+func (self *Golf) layoutCards() int {
+	var n int
+	for _, p := range self.tableaux {
+		n += p.Len()
+	}
+	for _, p := range self.reserves {
+		n += p.Len()
+	}
+	return n
+}
+
+func (*Golf) TailMoveError([]*Card) (bool, error) {
+	return true, nil
+}
+
+func (self *Golf) TailAppendError(dst *Pile, tail []*Card) (bool, error) {
+	switch dst.vtable.(type) {
+	case *Foundation:
+		if dst.Empty() {
+			return Compare_Empty(dst, tail[0])
+		}
+		if self.wrap {
+			return CardPair{dst.Peek(), tail[0]}.Compare_UpOrDownWrap()
+		}
+		return CardPair{dst.Peek(), tail[0]}.Compare_UpOrDown()
+	default:
+		return false, errors.New("Cards can only go to the Foundation")
+	}
+}
+
+// UnsortedPairs - cards in the layout are never in sequence
+func (*Golf) UnsortedPairs(pile *Pile) int {
+	if pile.Empty() {
+		return 0
+	}
+	return pile.Len() - 1
+}
+
+func (self *Golf) TailTapped(tail []*Card) {
+	var pile *Pile = tail[0].Owner()
+	if pile == self.stock && len(tail) == 1 {
+		// there's no waste; the stock deals straight to the foundation
+		MoveCard(self.stock, self.foundations[0])
+	} else {
+		pile.vtable.TailTapped(tail)
+	}
+}
+
+// CanCollect - no, picking which card goes to the foundation is the game
+func (*Golf) CanCollect() bool {
+	return false
+}
+
+// Complete when the layout has been cleared; there may be cards left in the stock
+func (self *Golf) Complete() bool {
+	return self.layoutCards() == 0
+}
+
+// PercentComplete is the proportion of the layout that has been cleared
+func (self *Golf) PercentComplete() int {
+	return (self.dealt() - self.layoutCards()) * 100 / self.dealt()
+}
@@ -25,46 +25,65 @@ func (self *Klondike) BuildPiles() {
 	if self.draw == 0 {
 		self.draw = 1
 	}
-	self.stock = NewStock(image.Point{0, 0}, FAN_NONE, self.Packs(), 4, nil, 0)
-	self.waste = NewWaste(image.Point{1, 0}, FAN_RIGHT3)
+	self.stock = NewStock(self.baize, image.Point{0, 0}, FAN_NONE, self.Packs(), 4, nil, self.Jokers())
+	self.waste = NewWaste(self.baize, image.Point{1, 0}, FAN_RIGHT3)
 
 	self.foundations = []*Pile{}
 	for _, x := range self.founds {
-		f := NewFoundation(image.Point{x, 0})
+		f := NewFoundation(self.baize, image.Point{x, 0})
 		self.foundations = append(self.foundations, f)
 		f.SetLabel("A")
 	}
 
 	self.tableaux = []*Pile{}
 	for _, x := range self.tabs {
-		t := NewTableau(image.Point{x, 1}, FAN_DOWN, MOVE_ANY)
+		t := NewTableau(self.baize, image.Point{x, 1}, FAN_DOWN, MOVE_ANY)
 		t.SetLabel("K")
 		self.tableaux = append(self.tableaux, t)
 	}
 }
 
 func (self *Klondike) StartGame() {
-	var dealDown int = 0
-	for _, pile := range self.tableaux {
-		for i := 0; i < dealDown; i++ {
-			card := MoveCard(self.stock, pile)
-			if card == nil {
-				log.Print("No card")
-				break
-			}
-			if !self.thoughtful {
-				card.FlipDown()
+	if self.baize.PySolDeals() {
+		self.pysolDeal()
+	} else {
+		var dealDown int = 0
+		for _, pile := range self.tableaux {
+			for i := 0; i < dealDown; i++ {
+				card := MoveCard(self.stock, pile)
+				if card == nil {
+					log.Print("No card")
+					break
+				}
+				if !self.thoughtful {
+					card.FlipDown()
+				}
 			}
+			dealDown++
+			MoveCard(self.stock, pile)
 		}
-		dealDown++
-		MoveCard(self.stock, pile)
 	}
-	TheGame.Baize.SetRecycles(self.recycles)
+	self.baize.SetRecycles(self.recycles)
 	for i := 0; i < self.draw; i++ {
 		MoveCard(self.stock, self.waste)
 	}
 }
 
+// pysolDeal deals the same layout as PySolFC, which deals a row at a time, right to left
+func (self *Klondike) pysolDeal() {
+	for row := 1; row < len(self.tableaux); row++ {
+		for i := len(self.tableaux) - 1; i >= row; i-- {
+			card := MoveCard(self.stock, self.tableaux[i])
+			if card != nil && !self.thoughtful {
+				card.FlipDown()
+			}
+		}
+	}
+	for i := len(self.tableaux) - 1; i >= 0; i-- {
+		MoveCard(self.stock, self.tableaux[i])
+	}
+}
+
 func (self *Klondike) AfterMove() {
 	if self.waste.Len() == 0 && self.stock.Len() != 0 {
 		for i := 0; i < self.draw; i++ {
@@ -88,10 +107,12 @@ func (*Klondike) TailMoveError(tail []*Card) (bool, error) {
 func (*Klondike) TailAppendError(dst *Pile, tail []*Card) (bool, error) {
 	switch dst.vtable.(type) {
 	case *Foundation:
-		if dst.Empty() {
+		// jokers go along with the cards on a foundation, and carry on with the card beneath them
+		var cards []*Card = NonJokers(dst.cards)
+		if len(cards) == 0 {
 			return Compare_Empty(dst, tail[0])
 		} else {
-			return CardPair{dst.Peek(), tail[0]}.Compare_UpSuit()
+			return CardPair{cards[len(cards)-1], tail[0]}.Compare_UpSuit()
 		}
 	case *Tableau:
 		if dst.Empty() {
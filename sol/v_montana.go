@@ -0,0 +1,238 @@
+package sol
+
+//lint:file-ignore ST1005 Error messages are toasted, so need to be capitalized
+//lint:file-ignore ST1006 Receiver name will be anything I like, thank you
+
+import (
+	"errors"
+	"fmt"
+	"image"
+)
+
+// Montana, Gaps and Blue Moon deal the cards into a grid of four rows, one card to a space,
+// with a gap left by each Ace. A gap is filled with the card one above the card to its left, in the same suit.
+// The aim is to get each row into suit order, from the left. Tapping the stock gathers the cards that aren't
+// yet in order, shuffles them, and deals them again.
+type Montana struct {
+	ScriptBase
+	keepAces   bool // the Aces start each row, rather than being taken out to leave the gaps
+	randomGaps bool // a redeal shuffles the Aces in and takes them out again, rather than leaving a gap after the cards in order
+}
+
+// columns returns the number of spaces in each row of the grid
+func (self *Montana) columns() int {
+	if self.keepAces {
+		return 14 // an Ace at the start of each row
+	}
+	return 13
+}
+
+// row returns the spaces in a row of the grid, left to right
+func (self *Montana) row(r int) []*Pile {
+	cols := self.columns()
+	return self.tableaux[r*cols : (r+1)*cols]
+}
+
+// firstOrdinal is the card that starts each row
+func (self *Montana) firstOrdinal() int {
+	if self.keepAces {
+		return 1
+	}
+	return 2
+}
+
+func (self *Montana) BuildPiles() {
+	cols := self.columns()
+	self.stock = NewStock(self.baize, image.Point{0, 4}, FAN_NONE, 1, 4, nil, 0)
+	self.tableaux = nil
+	for y := 0; y < 4; y++ {
+		var left *Pile
+		for x := 0; x < cols; x++ {
+			t := NewTableau(self.baize, image.Point{x, y}, FAN_NONE, MOVE_ONE)
+			t.SetLeft(left)
+			self.tableaux = append(self.tableaux, t)
+			left = t
+		}
+	}
+	if self.keepAces {
+		self.discards = nil
+	} else {
+		// the Aces are taken out of the game
+		self.discards = []*Pile{NewDiscard(self.baize, image.Point{cols - 1, 4}, FAN_NONE)}
+	}
+}
+
+func (self *Montana) StartGame() {
+	if self.keepAces {
+		// deal all the cards, leaving the first space in each row, then move the Aces there
+		for r := 0; r < 4; r++ {
+			for _, p := range self.row(r)[1:] {
+				MoveCard(self.stock, p)
+			}
+		}
+		var r int
+		for _, p := range self.tableaux {
+			if c := p.Peek(); c != nil && c.Ordinal() == 1 && p.Left() != nil {
+				MoveCard(p, self.row(r)[0])
+				r++
+			}
+		}
+	} else {
+		for _, p := range self.tableaux {
+			MoveCard(self.stock, p)
+		}
+		self.removeAces()
+	}
+	self.baize.SetRecycles(2)
+}
+
+// removeAces moves the Aces out of the grid to the discard pile, leaving gaps
+func (self *Montana) removeAces() {
+	for _, p := range self.tableaux {
+		if c := p.Peek(); c != nil && c.Ordinal() == 1 {
+			MoveCard(p, self.discards[0])
+		}
+	}
+}
+
+// sortedCards returns the number of cards at the start of a row that are in suit order
+func (self *Montana) sortedCards(row []*Pile) int {
+	var n int
+	var prev *Card
+	for _, p := range row {
+		c := p.Peek()
+		if c == nil {
+			break
+		}
+		if prev == nil {
+			if c.Ordinal() != self.firstOrdinal() {
+				break
+			}
+		} else if ok, _ := (CardPair{prev, c}).Compare_UpSuit(); !ok {
+			break
+		}
+		prev = c
+		n++
+	}
+	return n
+}
+
+// redeal gathers the cards that aren't in order, shuffles them and deals them back into the grid.
+// Usually a gap is left after the cards in order in each row;
+// with randomGaps, the Aces are shuffled in and taken out again, leaving the gaps anywhere.
+func (self *Montana) redeal() {
+	var b *Baize = self.baize
+	if b.Recycles() == 0 {
+		b.toastInfo("No more redeals")
+		return
+	}
+	var sorted [4]int
+	for r := 0; r < 4; r++ {
+		sorted[r] = self.sortedCards(self.row(r))
+		for _, p := range self.row(r)[sorted[r]:] {
+			for p.Len() > 0 {
+				MoveCard(p, self.stock)
+			}
+		}
+	}
+	if self.randomGaps {
+		for self.discards[0].Len() > 0 {
+			MoveCard(self.discards[0], self.stock)
+		}
+	}
+	// each redeal gets a seed of its own, otherwise the cards would fall the same way every time;
+	// it only depends on the deal and how many redeals are left, so undo and redo see the same cards
+	self.stock.ShuffleWithSeed(b.seed ^ uint64(b.Recycles())*0x9e3779b97f4a7c15)
+	for r := 0; r < 4; r++ {
+		for i, p := range self.row(r)[sorted[r]:] {
+			if i == 0 && !self.randomGaps {
+				continue
+			}
+			MoveCard(self.stock, p)
+		}
+	}
+	if self.randomGaps {
+		self.removeAces()
+	}
+	b.SetRecycles(b.Recycles() - 1)
+	switch b.Recycles() {
+	case 0:
+		b.toastInfo("No more redeals")
+	case 1:
+		b.toastInfo("1 redeal remaining")
+	default:
+		b.toastInfo(fmt.Sprintf("%d redeals remaining", b.Recycles()))
+	}
+}
+
+func (*Montana) TailMoveError([]*Card) (bool, error) {
+	return true, nil
+}
+
+func (self *Montana) TailAppendError(dst *Pile, tail []*Card) (bool, error) {
+	card := tail[0]
+	if !dst.Empty() {
+		return false, errors.New("A card can only go in a gap")
+	}
+	left := dst.Left()
+	if left == nil {
+		if card.Ordinal() != self.firstOrdinal() {
+			if self.keepAces {
+				return false, errors.New("Only an Ace can start a row")
+			}
+			return false, errors.New("Only a Two can start a row")
+		}
+		return true, nil
+	}
+	if left.Empty() {
+		return false, errors.New("Cannot fill a gap next to another gap")
+	}
+	if left.Peek().rank() == left.Peek().topRank() {
+		return false, errors.New("Nothing can follow a King")
+	}
+	return CardPair{left.Peek(), card}.Compare_UpSuit()
+}
+
+func (*Montana) UnsortedPairs(*Pile) int {
+	return 0 // one card to a space
+}
+
+func (self *Montana) TailTapped(tail []*Card) {
+	var pile *Pile = tail[0].Owner()
+	if pile == self.stock {
+		return // the stock only holds cards while they are redealt
+	}
+	pile.vtable.TailTapped(tail)
+}
+
+func (self *Montana) PileTapped(pile *Pile) {
+	if pile == self.stock {
+		self.redeal()
+	}
+}
+
+// inGrid returns the number of cards in the grid, and how many of those are in order
+func (self *Montana) inGrid() (cards, sorted int) {
+	for r := 0; r < 4; r++ {
+		sorted += self.sortedCards(self.row(r))
+		for _, p := range self.row(r) {
+			cards += p.Len()
+		}
+	}
+	return
+}
+
+// Complete when every card in the grid is in order
+func (self *Montana) Complete() bool {
+	cards, sorted := self.inGrid()
+	return cards == sorted
+}
+
+// PercentComplete is the proportion of the cards in the grid that are in order, not counting any Aces at the start of the rows
+func (self *Montana) PercentComplete() int {
+	cards, sorted := self.inGrid()
+	if self.keepAces {
+		cards, sorted = cards-4, sorted-4
+	}
+	return sorted * 100 / cards
+}
@@ -13,23 +13,23 @@ type Oddstream struct {
 
 func (self *Oddstream) BuildPiles() {
 
-	self.stock = NewStock(image.Point{0, 0}, FAN_NONE, 2, 4, nil, 0)
+	self.stock = NewStock(self.baize, image.Point{0, 0}, FAN_NONE, 2, 4, nil, 0)
 
 	self.cells = []*Pile{}
 	for x := 1; x < 4; x++ {
-		c := NewCell(image.Point{x, 0})
+		c := NewCell(self.baize, image.Point{x, 0})
 		self.cells = append(self.cells, c)
 	}
 	self.foundations = []*Pile{}
 	for x := 4; x < 12; x++ {
-		f := NewFoundation(image.Point{x, 0})
+		f := NewFoundation(self.baize, image.Point{x, 0})
 		f.SetLabel("A")
 		self.foundations = append(self.foundations, f)
 	}
 
 	self.tableaux = []*Pile{}
 	for x := 0; x < 12; x++ {
-		t := NewTableau(image.Point{x, 1}, FAN_DOWN, MOVE_ANY)
+		t := NewTableau(self.baize, image.Point{x, 1}, FAN_DOWN, MOVE_ANY)
 		if x%2 == 0 {
 			t.SetLabel("K")
 		} else {
@@ -49,7 +49,7 @@ func (self *Oddstream) StartGame() {
 		}
 	}
 
-	TheGame.Baize.SetRecycles(0)
+	self.baize.SetRecycles(0)
 }
 
 func (*Oddstream) TailMoveError(tail []*Card) (bool, error) {
@@ -0,0 +1,133 @@
+package sol
+
+//lint:file-ignore ST1005 Error messages are toasted, so need to be capitalized
+//lint:file-ignore ST1006 Receiver name will be anything I like, thank you
+
+import (
+	"errors"
+	"image"
+)
+
+// Pyramid deals 28 cards face up in a pyramid of seven rows, each card overlapping two in the row above.
+// Cards are removed to the Discard in pairs that add up to 13, and Kings on their own.
+// A card in the pyramid can't be used until both the cards overlapping it have gone.
+type Pyramid struct {
+	ScriptBase
+	pyramid   []*Pile // a pile for each card in the pyramid, a row at a time, top row first
+	recycles  int
+	relaxed   bool // a card can be paired with a card that is overlapping it
+	nreserves int  // Giza deals the rest of the pack to this many reserves, rather than keeping a stock
+}
+
+func (self *Pyramid) BuildPiles() {
+	// the pyramid piles overlap, so their positions are worked out in tenths of a slot;
+	// above reserves, the pyramid is centered over them
+	var left int = 10
+	if self.nreserves > 0 {
+		self.stock = NewStock(self.baize, image.Point{-5, -5}, FAN_NONE, 1, 4, nil, 0)
+		self.waste = nil
+		left = 5
+	} else {
+		self.stock = NewStock(self.baize, image.Point{0, 0}, FAN_NONE, 1, 4, nil, 0)
+		self.waste = NewWaste(self.baize, image.Point{0, 1}, FAN_DOWN3)
+	}
+
+	self.pyramid = nil
+	for row := 0; row < 7; row++ {
+		for i := 0; i <= row; i++ {
+			x, y := left+(6-row)*5+i*10, row*5
+			p := NewReserve(self.baize, image.Point{x / 10, y / 10}, FAN_NONE)
+			p.SetOffset(image.Point{x % 10, y % 10})
+			self.pyramid = append(self.pyramid, p)
+		}
+	}
+	// the card at (row, i) is overlapped by the cards at (row+1, i) and (row+1, i+1)
+	var n int
+	for row := 0; row < 6; row++ {
+		for i := 0; i <= row; i++ {
+			below := n + row + 1
+			self.pyramid[n].SetCoveredBy(self.pyramid[below], self.pyramid[below+1])
+			n++
+		}
+	}
+
+	self.reserves = nil
+	for x := 0; x < self.nreserves; x++ {
+		self.reserves = append(self.reserves, NewReserve(self.baize, image.Point{x, 4}, FAN_DOWN))
+	}
+
+	self.discards = []*Pile{NewDiscard(self.baize, image.Point{8, 0}, FAN_NONE)}
+}
+
+func (self *Pyramid) StartGame() {
+	for _, p := range self.pyramid {
+		MoveCard(self.stock, p)
+	}
+	for self.stock.Len() > 0 && len(self.reserves) > 0 {
+		for _, r := range self.reserves {
+			MoveCard(self.stock, r)
+		}
+	}
+	self.baize.SetRecycles(self.recycles)
+	if self.waste != nil {
+		MoveCard(self.stock, self.waste)
+	}
+}
+
+func (*Pyramid) TailMoveError([]*Card) (bool, error) {
+	return true, nil
+}
+
+func (*Pyramid) TailAppendError(*Pile, []*Card) (bool, error) {
+	return false, errors.New("Cards are removed in pairs that add up to 13, and Kings on their own")
+}
+
+func (*Pyramid) UnsortedPairs(*Pile) int {
+	return 0
+}
+
+func (self *Pyramid) PairError(c1, c2 *Card) (bool, error) {
+	if c1.Prone() || (c2 != nil && c2.Prone()) {
+		return false, errors.New("Cannot remove a face down card")
+	}
+	if c2 == nil {
+		if c1.Ordinal() != 13 {
+			return false, errors.New("Only a King can be removed on its own")
+		}
+		return true, nil
+	}
+	if p := c2.Owner(); p.Covered() && !(self.relaxed && p.coveredOnlyBy(c1.Owner())) {
+		return false, errors.New("That card is covered by another card")
+	}
+	if c1.Ordinal()+c2.Ordinal() != 13 {
+		return false, errors.New("The cards must add up to 13")
+	}
+	return true, nil
+}
+
+func (self *Pyramid) TailTapped(tail []*Card) {
+	var pile *Pile = tail[0].Owner()
+	if pile == self.stock && len(tail) == 1 {
+		if self.waste != nil {
+			MoveCard(self.stock, self.waste)
+		}
+	} else {
+		pile.vtable.TailTapped(tail)
+	}
+}
+
+func (self *Pyramid) PileTapped(pile *Pile) {
+	if pile == self.stock && self.waste != nil {
+		RecycleWasteToStock(self.waste, self.stock)
+	}
+}
+
+// Complete when all the cards have been removed
+func (self *Pyramid) Complete() bool {
+	return self.discards[0].Len() == self.baize.cardCount
+}
+
+// PercentComplete is the proportion of the cards that have been removed
+func (self *Pyramid) PercentComplete() int {
+	return self.discards[0].Len() * 100 / self.baize.cardCount
+}
@@ -15,17 +15,17 @@ type Scorpion struct {
 
 func (self *Scorpion) BuildPiles() {
 
-	self.stock = NewStock(image.Point{0, 0}, FAN_NONE, 1, 4, nil, 0)
+	self.stock = NewStock(self.baize, image.Point{0, 0}, FAN_NONE, 1, 4, nil, 0)
 
 	self.discards = []*Pile{}
 	for x := 3; x < 7; x++ {
-		d := NewDiscard(image.Point{x, 0}, FAN_NONE)
+		d := NewDiscard(self.baize, image.Point{x, 0}, FAN_NONE)
 		self.discards = append(self.discards, d)
 	}
 
 	self.tableaux = []*Pile{}
 	for x := 0; x < 7; x++ {
-		t := NewTableau(image.Point{x, 1}, FAN_DOWN, MOVE_ANY)
+		t := NewTableau(self.baize, image.Point{x, 1}, FAN_DOWN, MOVE_ANY)
 		t.SetLabel("K")
 		self.tableaux = append(self.tableaux, t)
 	}
@@ -47,7 +47,7 @@ func (self *Scorpion) StartGame() {
 			tab.cards[j].FlipDown()
 		}
 	}
-	TheGame.Baize.SetRecycles(0)
+	self.baize.SetRecycles(0)
 	if DebugMode && self.stock.Len() > 0 {
 		log.Println("*** still", self.stock.Len(), "cards in Stock ***")
 	}
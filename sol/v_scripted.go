@@ -0,0 +1,165 @@
+package sol
+
+//lint:file-ignore ST1005 Error messages are toasted, so need to be capitalized
+//lint:file-ignore ST1006 Receiver name will be anything I like, thank you
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"log"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// scriptTimeout is how long a script function can run before it is stopped,
+// so a script stuck in a loop can't hang the game
+var scriptTimeout = time.Second
+
+// Scripted plays a variant whose rules are a Lua script, loaded from a file.
+// Each baize runs the script in its own interpreter.
+type Scripted struct {
+	ScriptBase
+	name, fname string
+	proto       *lua.FunctionProto
+	L           *lua.LState
+	luaPiles    map[*Pile]*lua.LUserData
+	luaCards    map[*Card]*lua.LUserData
+	reported    map[string]bool // errors already toasted
+}
+
+func (self *Scripted) setBaize(b *Baize) {
+	self.ScriptBase.setBaize(b)
+	self.L = nil // don't share the prototype's interpreter
+	self.reported = make(map[string]bool)
+}
+
+// run runs a function in the script with a time limit, leaving nret results on the stack
+func (self *Scripted) run(L *lua.LState, fn *lua.LFunction, nret int, args ...lua.LValue) error {
+	ctx, cancel := context.WithTimeout(context.Background(), scriptTimeout)
+	defer cancel()
+	L.SetContext(ctx)
+	defer L.RemoveContext()
+	err := L.CallByParam(lua.P{Fn: fn, NRet: nret, Protect: true}, args...)
+	if apiErr, ok := err.(*lua.ApiError); ok {
+		if ctx.Err() != nil {
+			return fmt.Errorf("%s took too long; is it stuck in a loop?", fn.Proto.SourceName)
+		}
+		return errors.New(apiErr.Object.String()) // without the stack traceback
+	}
+	return err
+}
+
+// has returns true if the script defines a hook
+func (self *Scripted) has(hook string) bool {
+	_, ok := self.L.GetGlobal(hook).(*lua.LFunction)
+	return ok
+}
+
+// call calls a hook in the script, and returns its result.
+// If the script fails, the player is told (once), rather than the game stopping.
+func (self *Scripted) call(hook string, args ...lua.LValue) (lua.LValue, error) {
+	fn, ok := self.L.GetGlobal(hook).(*lua.LFunction)
+	if !ok {
+		return lua.LNil, nil
+	}
+	if err := self.run(self.L, fn, 1, args...); err != nil {
+		self.report(fmt.Sprintf("%s: %s", hook, err))
+		return lua.LNil, err
+	}
+	ret := self.L.Get(-1)
+	self.L.Pop(1)
+	return ret, nil
+}
+
+// report tells the player what went wrong with the script, once
+func (self *Scripted) report(msg string) {
+	if !self.reported[msg] {
+		self.reported[msg] = true
+		log.Println(msg)
+		self.baize.toastError(msg)
+	}
+}
+
+// ruleResult turns what a rule hook returned into what the engine expects;
+// nil (or nothing, or true) means yes, and a string (or false) means no, and why not
+func ruleResult(ret lua.LValue, err error) (bool, error) {
+	if err != nil {
+		return false, err
+	}
+	switch ret := ret.(type) {
+	case lua.LString:
+		return false, errors.New(string(ret))
+	case lua.LBool:
+		if !ret {
+			return false, errors.New("That is not allowed")
+		}
+	}
+	return true, nil
+}
+
+func (self *Scripted) BuildPiles() {
+	if self.L == nil {
+		self.L = self.newState()
+		if err := self.run(self.L, self.L.NewFunctionFromProto(self.proto), 0); err != nil {
+			self.report(err.Error())
+		}
+	}
+	self.luaPiles = make(map[*Pile]*lua.LUserData)
+	self.stock, self.waste = nil, nil
+	self.cells, self.discards, self.foundations, self.reserves, self.tableaux = nil, nil, nil, nil, nil
+	self.call("BuildPiles")
+	if self.stock == nil {
+		// the engine can't do anything without a stock
+		self.report("BuildPiles: there must be a Stock")
+		self.stock = NewStock(self.baize, image.Point{-5, -5}, FAN_NONE, self.Packs(), self.Suits(), nil, self.Jokers())
+	}
+}
+
+func (self *Scripted) StartGame() {
+	self.call("StartGame")
+}
+
+func (self *Scripted) AfterMove() {
+	self.call("AfterMove")
+}
+
+func (self *Scripted) TailMoveError(tail []*Card) (bool, error) {
+	return ruleResult(self.call("TailMoveError", self.tailValue(self.L, tail)))
+}
+
+func (self *Scripted) TailAppendError(dst *Pile, tail []*Card) (bool, error) {
+	if !self.has("TailAppendError") {
+		if dst.Empty() {
+			return Compare_Empty(dst, tail[0])
+		}
+		return true, nil
+	}
+	return ruleResult(self.call("TailAppendError", self.pileValue(self.L, dst), self.tailValue(self.L, tail)))
+}
+
+// UnsortedPairs counts the pairs of cards the script wouldn't let be moved together
+func (self *Scripted) UnsortedPairs(pile *Pile) int {
+	return UnsortedPairs(pile, func(cp CardPair) (bool, error) {
+		return self.TailMoveError([]*Card{cp.c1, cp.c2})
+	})
+}
+
+func (self *Scripted) TailTapped(tail []*Card) {
+	if !self.has("TailTapped") {
+		tail[0].Owner().vtable.TailTapped(tail)
+		return
+	}
+	self.call("TailTapped", self.tailValue(self.L, tail))
+}
+
+func (self *Scripted) PileTapped(pile *Pile) {
+	self.call("PileTapped", self.pileValue(self.L, pile))
+}
+
+// Name returns the name of the variant the script plays
+func (self *Scripted) Name() string {
+	return self.name
+}
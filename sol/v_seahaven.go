@@ -14,23 +14,23 @@ type Seahaven struct {
 
 func (self *Seahaven) BuildPiles() {
 
-	self.stock = NewStock(image.Point{-5, -5}, FAN_NONE, 1, 4, nil, 0)
+	self.stock = NewStock(self.baize, image.Point{-5, -5}, FAN_NONE, 1, 4, nil, 0)
 
 	self.cells = nil
 	for x := 0; x < 4; x++ {
-		self.cells = append(self.cells, NewCell(image.Point{x, 0}))
+		self.cells = append(self.cells, NewCell(self.baize, image.Point{x, 0}))
 	}
 
 	self.foundations = nil
 	for x := 6; x < 10; x++ {
-		f := NewFoundation(image.Point{x, 0})
+		f := NewFoundation(self.baize, image.Point{x, 0})
 		self.foundations = append(self.foundations, f)
 		f.SetLabel("A")
 	}
 
 	self.tableaux = nil
 	for x := 0; x < 10; x++ {
-		t := NewTableau(image.Point{x, 1}, FAN_DOWN, MOVE_ONE_PLUS)
+		t := NewTableau(self.baize, image.Point{x, 1}, FAN_DOWN, MOVE_ONE_PLUS)
 		self.tableaux = append(self.tableaux, t)
 		t.SetLabel("K")
 	}
@@ -47,7 +47,7 @@ func (self *Seahaven) StartGame() {
 	if DebugMode && self.stock.Len() > 0 {
 		log.Println("*** still", self.stock.Len(), "cards in Stock ***")
 	}
-	TheGame.Baize.SetRecycles(0)
+	self.baize.SetRecycles(0)
 }
 
 func (self *Seahaven) TailMoveError(tail []*Card) (bool, error) {
@@ -15,17 +15,17 @@ type SimpleSimon struct {
 
 func (self *SimpleSimon) BuildPiles() {
 
-	self.stock = NewStock(image.Point{-5, -5}, FAN_NONE, 1, 4, nil, 0)
+	self.stock = NewStock(self.baize, image.Point{-5, -5}, FAN_NONE, 1, 4, nil, 0)
 
 	self.discards = []*Pile{}
 	for x := 3; x < 7; x++ {
-		d := NewDiscard(image.Point{x, 0}, FAN_NONE)
+		d := NewDiscard(self.baize, image.Point{x, 0}, FAN_NONE)
 		self.discards = append(self.discards, d)
 	}
 
 	self.tableaux = []*Pile{}
 	for x := 0; x < 10; x++ {
-		t := NewTableau(image.Point{x, 1}, FAN_DOWN, MOVE_ANY)
+		t := NewTableau(self.baize, image.Point{x, 1}, FAN_DOWN, MOVE_ANY)
 		self.tableaux = append(self.tableaux, t)
 	}
 }
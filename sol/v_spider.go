@@ -14,17 +14,17 @@ type Spider struct {
 
 func (self *Spider) BuildPiles() {
 
-	self.stock = NewStock(image.Point{0, 0}, FAN_NONE, self.packs, self.suits, nil, 0)
+	self.stock = NewStock(self.baize, image.Point{0, 0}, FAN_NONE, self.packs, self.suits, nil, self.Jokers())
 
 	self.discards = nil
 	for x := 2; x < 10; x++ {
-		d := NewDiscard(image.Point{x, 0}, FAN_NONE)
+		d := NewDiscard(self.baize, image.Point{x, 0}, FAN_NONE)
 		self.discards = append(self.discards, d)
 	}
 
 	self.tableaux = nil
 	for x := 0; x < 10; x++ {
-		t := NewTableau(image.Point{x, 1}, FAN_DOWN, MOVE_ANY)
+		t := NewTableau(self.baize, image.Point{x, 1}, FAN_DOWN, MOVE_ANY)
 		self.tableaux = append(self.tableaux, t)
 	}
 }
@@ -33,6 +33,12 @@ func (self *Spider) StartGame() {
 	// The Tableau consists of 10 stacks with 6 cards in the first 4 stacks, with the 6th card face up,
 	// and 5 cards in the remaining 6 stacks, with the 5th card face up.
 
+	if self.baize.PySolDeals() {
+		self.pysolDeal()
+		self.baize.SetRecycles(0)
+		return
+	}
+
 	for i := 0; i < 4; i++ {
 		pile := self.tableaux[i]
 		for j := 0; j < 6; j++ {
@@ -50,7 +56,23 @@ func (self *Spider) StartGame() {
 			c.FlipUp()
 		}
 	}
-	TheGame.Baize.SetRecycles(0)
+	self.baize.SetRecycles(0)
+}
+
+// pysolDeal deals the same layout as PySolFC, which deals a row at a time, left to right,
+// and gives the extra cards to stacks 1, 4, 7 and 10 rather than the first four
+func (self *Spider) pysolDeal() {
+	for row := 0; row < 4; row++ {
+		for _, t := range self.tableaux {
+			MoveCard(self.stock, t).FlipDown()
+		}
+	}
+	for i := 0; i < len(self.tableaux); i += 3 {
+		MoveCard(self.stock, self.tableaux[i]).FlipDown()
+	}
+	for _, t := range self.tableaux {
+		MoveCard(self.stock, t)
+	}
 }
 
 func (*Spider) TailMoveError(tail []*Card) (bool, error) {
@@ -69,7 +91,9 @@ func (*Spider) TailAppendError(dst *Pile, tail []*Card) (bool, error) {
 	// why the pretty asterisks? google method pointer receivers in interfaces; *Tableau is a different type to Tableau
 	switch dst.vtable.(type) {
 	case *Discard:
-		if tail[0].Ordinal() != 13 {
+		// a joker stands for one card, so a run with a joker in it is never a whole suit,
+		// and the Discard won't take it
+		if tail[0].Joker() || tail[0].rank() != tail[0].topRank() {
 			return false, errors.New("Can only discard starting from a King")
 		}
 		ok, err := TailConformant(tail, CardPair.Compare_DownSuit)
@@ -102,7 +126,7 @@ func (self *Spider) TailTapped(tail []*Card) {
 			}
 		}
 		if emptyTabs > 0 && tabCards >= len(self.tableaux) {
-			TheGame.UI.ToastError("All empty tableaux must be filled before dealing a new row")
+			self.baize.toastError("All empty tableaux must be filled before dealing a new row")
 		} else {
 			for _, tab := range self.tableaux {
 				MoveCard(self.stock, tab)
@@ -18,17 +18,17 @@ func (self *Spiderette) BuildPiles() {
 		self.cardColors = 4
 	}
 
-	self.stock = NewStock(image.Point{0, 0}, FAN_NONE, self.packs, self.suits, nil, 0)
+	self.stock = NewStock(self.baize, image.Point{0, 0}, FAN_NONE, self.Packs(), self.Suits(), nil, 0)
 
 	self.discards = []*Pile{}
 	for x := 3; x < 7; x++ {
-		d := NewDiscard(image.Point{x, 0}, FAN_NONE)
+		d := NewDiscard(self.baize, image.Point{x, 0}, FAN_NONE)
 		self.discards = append(self.discards, d)
 	}
 
 	self.tableaux = []*Pile{}
 	for x := 0; x < 7; x++ {
-		t := NewTableau(image.Point{x, 1}, FAN_DOWN, MOVE_ANY)
+		t := NewTableau(self.baize, image.Point{x, 1}, FAN_DOWN, MOVE_ANY)
 		self.tableaux = append(self.tableaux, t)
 	}
 }
@@ -49,7 +49,7 @@ func (self *Spiderette) StartGame() {
 			c.FlipUp()
 		}
 	}
-	TheGame.Baize.SetRecycles(0)
+	self.baize.SetRecycles(0)
 }
 
 func (*Spiderette) TailMoveError(tail []*Card) (bool, error) {
@@ -101,7 +101,7 @@ func (self *Spiderette) TailTapped(tail []*Card) {
 			}
 		}
 		if emptyTabs > 0 && tabCards >= len(self.tableaux) {
-			TheGame.UI.ToastError("All empty tableaux must be filled before dealing a new row")
+			self.baize.toastError("All empty tableaux must be filled before dealing a new row")
 		} else {
 			for _, tab := range self.tableaux {
 				MoveCard(self.stock, tab)
@@ -15,45 +15,45 @@ type Westcliff struct {
 }
 
 func (self *Westcliff) BuildPiles() {
-	self.stock = NewStock(image.Point{0, 0}, FAN_NONE, 1, 4, nil, 0)
+	self.stock = NewStock(self.baize, image.Point{0, 0}, FAN_NONE, 1, 4, nil, 0)
 	switch self.variant {
 	case "Classic":
-		self.waste = NewWaste(image.Point{1, 0}, FAN_RIGHT3)
+		self.waste = NewWaste(self.baize, image.Point{1, 0}, FAN_RIGHT3)
 		self.foundations = []*Pile{}
 		for x := 3; x < 7; x++ {
-			f := NewFoundation(image.Point{x, 0})
+			f := NewFoundation(self.baize, image.Point{x, 0})
 			self.foundations = append(self.foundations, f)
 			f.SetLabel("A")
 		}
 		self.tableaux = []*Pile{}
 		for x := 0; x < 7; x++ {
-			t := NewTableau(image.Point{x, 1}, FAN_DOWN, MOVE_ANY)
+			t := NewTableau(self.baize, image.Point{x, 1}, FAN_DOWN, MOVE_ANY)
 			self.tableaux = append(self.tableaux, t)
 		}
 	case "American":
-		self.waste = NewWaste(image.Point{1, 0}, FAN_RIGHT3)
+		self.waste = NewWaste(self.baize, image.Point{1, 0}, FAN_RIGHT3)
 		self.foundations = []*Pile{}
 		for x := 6; x < 10; x++ {
-			f := NewFoundation(image.Point{x, 0})
+			f := NewFoundation(self.baize, image.Point{x, 0})
 			self.foundations = append(self.foundations, f)
 			f.SetLabel("A")
 		}
 		self.tableaux = []*Pile{}
 		for x := 0; x < 10; x++ {
-			t := NewTableau(image.Point{x, 1}, FAN_DOWN, MOVE_ANY)
+			t := NewTableau(self.baize, image.Point{x, 1}, FAN_DOWN, MOVE_ANY)
 			self.tableaux = append(self.tableaux, t)
 		}
 	case "Easthaven":
 		self.waste = nil
 		self.foundations = []*Pile{}
 		for x := 3; x < 7; x++ {
-			f := NewFoundation(image.Point{x, 0})
+			f := NewFoundation(self.baize, image.Point{x, 0})
 			self.foundations = append(self.foundations, f)
 			f.SetLabel("A")
 		}
 		self.tableaux = []*Pile{}
 		for x := 0; x < 7; x++ {
-			t := NewTableau(image.Point{x, 1}, FAN_DOWN, MOVE_ANY)
+			t := NewTableau(self.baize, image.Point{x, 1}, FAN_DOWN, MOVE_ANY)
 			self.tableaux = append(self.tableaux, t)
 			t.SetLabel("K")
 		}
@@ -90,7 +90,7 @@ func (self *Westcliff) StartGame() {
 			MoveCard(self.stock, self.waste)
 		}
 	}
-	TheGame.Baize.SetRecycles(0)
+	self.baize.SetRecycles(0)
 }
 
 func (self *Westcliff) AfterMove() {
@@ -15,11 +15,11 @@ type Yukon struct {
 
 func (self *Yukon) BuildPiles() {
 
-	self.stock = NewStock(image.Point{-5, -5}, FAN_NONE, 1, 4, nil, 0)
+	self.stock = NewStock(self.baize, image.Point{-5, -5}, FAN_NONE, 1, 4, nil, 0)
 
 	self.foundations = nil
 	for y := 0; y < 4; y++ {
-		f := NewFoundation(image.Point{8, y})
+		f := NewFoundation(self.baize, image.Point{8, y})
 		self.foundations = append(self.foundations, f)
 		f.SetLabel("A")
 	}
@@ -27,14 +27,14 @@ func (self *Yukon) BuildPiles() {
 	self.cells = nil
 	y := 4
 	for i := 0; i < self.extraCells; i++ {
-		c := NewCell(image.Point{8, y})
+		c := NewCell(self.baize, image.Point{8, y})
 		self.cells = append(self.cells, c)
 		y += 1
 	}
 
 	self.tableaux = nil
 	for x := 0; x < 7; x++ {
-		t := NewTableau(image.Point{x, 0}, FAN_DOWN, MOVE_ANY)
+		t := NewTableau(self.baize, image.Point{x, 0}, FAN_DOWN, MOVE_ANY)
 		self.tableaux = append(self.tableaux, t)
 		t.SetLabel("K")
 	}
@@ -42,6 +42,11 @@ func (self *Yukon) BuildPiles() {
 
 func (self *Yukon) StartGame() {
 
+	if self.baize.PySolDeals() {
+		self.pysolDeal()
+		return
+	}
+
 	MoveCard(self.stock, self.tableaux[0])
 	var dealDown int = 1
 	for x := 1; x < 7; x++ {
@@ -63,6 +68,25 @@ func (self *Yukon) StartGame() {
 	}
 }
 
+// pysolDeal deals the same layout as PySolFC, which deals a row at a time, left to right
+func (self *Yukon) pysolDeal() {
+	for row := 1; row < len(self.tableaux); row++ {
+		for _, t := range self.tableaux[row:] {
+			if c := MoveCard(self.stock, t); c != nil {
+				c.FlipDown()
+			}
+		}
+	}
+	for row := 0; row < 4; row++ {
+		for _, t := range self.tableaux[1:] {
+			MoveCard(self.stock, t)
+		}
+	}
+	for _, t := range self.tableaux {
+		MoveCard(self.stock, t)
+	}
+}
+
 func (*Yukon) TailMoveError([]*Card) (bool, error) {
 	return true, nil
 }
@@ -0,0 +1,76 @@
+package sol
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"oddstream.games/gosol/cardid"
+)
+
+// Validate checks that the baize is in a state the engine could have got it into:
+// all the cards are there, once each, every card knows which pile it is in,
+// the hashes are up to date, and a complete game has its piles in order.
+// It returns nil if all is well, or an error listing everything that isn't.
+func (b *Baize) Validate() error {
+	var problems []string
+	problem := func(format string, a ...any) {
+		problems = append(problems, fmt.Sprintf(format, a...))
+	}
+
+	var count int
+	seen := make(map[cardid.CardID]*Pile)
+	for i, p := range b.piles {
+		count += len(p.cards)
+		for j, c := range p.cards {
+			if c == nil {
+				problem("%s %d card %d is nil", p.category, i, j)
+				continue
+			}
+			if c.Owner() != p {
+				problem("%s in %s %d thinks it is in %s", c, p.category, i, pileName(c.Owner()))
+			}
+			id := c.id.PackSuitOrdinal()
+			if other, ok := seen[id]; ok {
+				problem("%s is in %s %d, and also in %s", c, p.category, i, pileName(other))
+			}
+			seen[id] = p
+		}
+		if h := p.hashCards(); h != p.hash {
+			problem("%s %d hash is out of date", p.category, i)
+		}
+	}
+	if b.cardCount == 0 {
+		problem("there are no cards")
+	} else if count != b.cardCount {
+		problem("%d cards on the baize, want %d", count, b.cardCount)
+	}
+	if b.moving == 0 && b.position != nil && b.position.hash() != b.Hash() {
+		problem("the move log does not match the cards on the baize")
+	}
+	if b.Complete() {
+		for i, p := range b.piles {
+			// discards are never Conformant, so the collect button isn't shown; their cards were checked on the way in
+			if _, ok := p.vtable.(*Discard); !ok && !p.vtable.Conformant() {
+				problem("game is complete, but %s %d is not in order", p.category, i)
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func pileName(p *Pile) string {
+	if p == nil {
+		return "no pile"
+	}
+	for i, q := range p.baize.piles {
+		if q == p {
+			return fmt.Sprintf("%s %d", p.category, i)
+		}
+	}
+	return fmt.Sprintf("a %s not on the baize", p.category)
+}
@@ -0,0 +1,67 @@
+package sol
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		corrupt func(b *Baize)
+		want    string
+	}{
+		{"lost card", func(b *Baize) { b.script.Stock().Pop() }, "51 cards on the baize, want 52"},
+		{"duplicated card", func(b *Baize) {
+			c := *b.script.Stock().Peek()
+			b.script.Waste().Push(&c)
+		}, "and also in Stock 0"},
+		{"wrong owner", func(b *Baize) { b.script.Stock().Peek().SetOwner(b.script.Waste()) }, "thinks it is in Waste 1"},
+		{"stale hash", func(b *Baize) { b.script.Stock().hash++ }, "Stock 0 hash is out of date"},
+		{"moved behind the move log's back", func(b *Baize) { MoveCard(b.script.Stock(), b.script.Waste()) }, "move log does not match"},
+	} {
+		b := NewBaize("Klondike", nil)
+		b.StartFreshGame()
+		if err := b.Validate(); err != nil {
+			t.Fatalf("a fresh deal is not valid: %s", err)
+		}
+		tc.corrupt(b)
+		if err := b.Validate(); err == nil {
+			t.Errorf("%s was not found", tc.name)
+		} else if !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s gave '%s', want '%s'", tc.name, err, tc.want)
+		}
+	}
+}
+
+// leakyKlondike loses a card from the stock after the fifth move
+type leakyKlondike struct {
+	Klondike
+}
+
+func (self *leakyKlondike) AfterMove() {
+	self.Klondike.AfterMove()
+	if self.baize.MovesMade() == 4 {
+		self.stock.Pop()
+	}
+}
+
+func TestSelfTest(t *testing.T) {
+	var variants []string
+	for v := range Variants {
+		variants = append(variants, v)
+	}
+	sort.Strings(variants)
+	for _, err := range SelfTest(context.Background(), variants, 2, 1, 60) {
+		t.Error(err)
+	}
+
+	Variants["Leaky Klondike"] = &leakyKlondike{Klondike: *Variants["Klondike"].(*Klondike)}
+	defer delete(Variants, "Leaky Klondike")
+	errs := SelfTest(context.Background(), []string{"Leaky Klondike"}, 1, 1, 60)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "Leaky Klondike deal 1, move 5") {
+		t.Errorf("SelfTest gave %v for a variant that loses a card", errs)
+	}
+}
@@ -0,0 +1,390 @@
+package sol
+
+//lint:file-ignore ST1005 Error messages are toasted, so need to be capitalized
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"oddstream.games/gosol/cardid"
+)
+
+// VariantDef describes a variant in a data file, so players can add their own
+// variants without writing any Go. See Defined for how it is played.
+type VariantDef struct {
+	Name           string
+	Wikipedia      string    `json:",omitempty"`
+	Packs          int       `json:",omitempty"` // default 1
+	Suits          int       `json:",omitempty"` // 1, 2 or 4 (the default)
+	CardColors     int       `json:",omitempty"` // 1, 2 (the default) or 4
+	Jokers         int       `json:",omitempty"` // in each pack, 0 (the default) to 4
+	Ranks          []int     `json:",omitempty"` // the ordinals in each suit, lowest first, from 1 (Ace) to 15; default Ace to King
+	Piles          []PileDef // in the order they are built; every variant needs a Stock
+	Stock          StockDef  `json:",omitempty"`
+	DealByRows     bool      `json:",omitempty"` // deal a card to each pile in turn, rather than fill one pile at a time
+	MicrosoftDeals bool      `json:",omitempty"` // deal numbers are Microsoft FreeCell deal numbers
+	Complete       string    `json:",omitempty"` // "Foundations" (the default) or "Sequences"
+}
+
+// PileDef describes a pile, or a row of piles side by side
+type PileDef struct {
+	Category string   // Stock, Waste, Foundation, Tableau, Cell, Reserve or Discard
+	Slot     [2]int   // where the (first) pile goes; negative to hide it off the baize
+	Repeat   int      `json:",omitempty"` // how many piles in the row, default 1
+	Fan      string   `json:",omitempty"` // None, Down, Left, Right, Down3, Left3 or Right3
+	MoveType string   `json:",omitempty"` // for tableaux, which tails can move: Any (the default), One, OnePlus, OneOrAll or None
+	Label    string   `json:",omitempty"` // the only card an empty pile will accept, eg "K", or "X" for none
+	Build    string   `json:",omitempty"` // how a card goes on the top card, from the CardPair Compare_ library, eg "DownAltColor"
+	Sequence string   `json:",omitempty"` // how the cards in a tail must be to move it, default the same as Build
+	Deal     []string `json:",omitempty"` // the cards dealt to each pile, d for face down and u for face up
+}
+
+// StockDef describes what tapping the stock does
+type StockDef struct {
+	DealTo            string `json:",omitempty"` // "Waste", "Tableaux", or "" if tapping the stock does nothing
+	Draw              int    `json:",omitempty"` // cards dealt to the waste at a time, default 1
+	Recycles          int    `json:",omitempty"` // times the waste can be turned back into the stock
+	RefillWaste       bool   `json:",omitempty"` // deal to the waste after a move leaves it empty
+	FillTableauxFirst bool   `json:",omitempty"` // no dealing to the tableaux while one is empty
+}
+
+// VariantDefError lists everything wrong with a variant definition
+type VariantDefError struct {
+	Name     string
+	Problems []string
+}
+
+func (e *VariantDefError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Name, strings.Join(e.Problems, "; "))
+}
+
+var fanTypes = map[string]FanType{
+	"":       FAN_NONE,
+	"None":   FAN_NONE,
+	"Down":   FAN_DOWN,
+	"Left":   FAN_LEFT,
+	"Right":  FAN_RIGHT,
+	"Down3":  FAN_DOWN3,
+	"Left3":  FAN_LEFT3,
+	"Right3": FAN_RIGHT3,
+}
+
+var moveTypes = map[string]MoveType{
+	"":         MOVE_ANY,
+	"Any":      MOVE_ANY,
+	"One":      MOVE_ONE,
+	"OnePlus":  MOVE_ONE_PLUS,
+	"OneOrAll": MOVE_ONE_OR_ALL,
+	"None":     MOVE_NONE,
+}
+
+var pileCategories = []string{"Stock", "Waste", "Foundation", "Tableau", "Cell", "Reserve", "Discard"}
+
+var pileLabels = []string{"", "A", "2", "3", "4", "5", "6", "7", "8", "9", "10", "J", "Q", "K", "X"}
+
+// compareFunc finds a compare function in the CardPair library by name, eg "DownAltColor"
+func compareFunc(name string) (CardPairCompareFunc, bool) {
+	m, ok := reflect.TypeOf(CardPair{}).MethodByName("Compare_" + name)
+	if !ok {
+		return nil, false
+	}
+	fn, ok := m.Func.Interface().(func(CardPair) (bool, error))
+	return fn, ok
+}
+
+// compareFuncNames lists the compare functions in the CardPair library
+func compareFuncNames() []string {
+	var names []string
+	t := reflect.TypeOf(CardPair{})
+	for i := 0; i < t.NumMethod(); i++ {
+		if name := strings.TrimPrefix(t.Method(i).Name, "Compare_"); name != t.Method(i).Name {
+			if _, ok := compareFunc(name); ok {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func mapKeys[T any](m map[string]T) []string {
+	var keys []string
+	for k := range m {
+		if k != "" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func contains(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+// lineOfOffset returns the line number of a byte offset in data
+func lineOfOffset(data []byte, offset int64) int {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
+
+// ParseVariantDef reads a variant definition from JSON, and checks it
+func ParseVariantDef(data []byte) (*VariantDef, error) {
+	var def VariantDef
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&def); err != nil {
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+		switch {
+		case errors.As(err, &syntaxErr):
+			return nil, fmt.Errorf("Line %d: %s", lineOfOffset(data, syntaxErr.Offset), syntaxErr)
+		case errors.Is(err, io.ErrUnexpectedEOF):
+			return nil, fmt.Errorf("Line %d: the file ends too soon; is a } or ] missing?", lineOfOffset(data, int64(len(data))))
+		case errors.As(err, &typeErr):
+			return nil, fmt.Errorf("Line %d: %s should be %s, not %s", lineOfOffset(data, typeErr.Offset), typeErr.Field, typeErr.Type, typeErr.Value)
+		default:
+			return nil, err // eg json: unknown field "Bulid"
+		}
+	}
+	if err := def.Validate(); err != nil {
+		return nil, err
+	}
+	return &def, nil
+}
+
+// Validate checks that a variant definition makes sense, and can be played
+func (def *VariantDef) Validate() error {
+	e := &VariantDefError{Name: def.Name}
+	problem := func(format string, a ...any) {
+		e.Problems = append(e.Problems, fmt.Sprintf(format, a...))
+	}
+	if def.Name == "" {
+		e.Name = "Variant"
+		problem("Name is missing")
+	} else if !fileSafeName(def.Name) {
+		problem("Name can't contain %s", unsafeNameParts)
+	} else if _, ok := LookupVariant(def.Name); ok {
+		problem("There is already a variant called %s", def.Name)
+	}
+	if def.Packs < 0 {
+		problem("Packs must be at least 1")
+	}
+	if def.Suits != 0 && def.Suits != 1 && def.Suits != 2 && def.Suits != 4 {
+		problem("Suits must be 1, 2 or 4, not %d", def.Suits)
+	}
+	if def.CardColors != 0 && def.CardColors != 1 && def.CardColors != 2 && def.CardColors != 4 {
+		problem("CardColors must be 1, 2 or 4, not %d", def.CardColors)
+	}
+	if def.Jokers < 0 || def.Jokers > cardid.MaxJokers {
+		problem("Jokers must be 0 to %d, not %d", cardid.MaxJokers, def.Jokers)
+	}
+	if def.Ranks != nil {
+		seen := make(map[int]bool)
+		for _, ord := range def.Ranks {
+			if ord < 1 || ord > cardid.MaxOrdinal {
+				problem("Ranks must be from 1 to %d, not %d", cardid.MaxOrdinal, ord)
+			} else if seen[ord] {
+				problem("Ranks has %d more than once", ord)
+			}
+			seen[ord] = true
+		}
+		if len(def.Ranks) == 0 {
+			problem("Ranks can't be empty; leave it out for Ace to King")
+		}
+	}
+	if def.Complete != "" && def.Complete != "Foundations" && def.Complete != "Sequences" {
+		problem("Complete must be Foundations or Sequences, not %s", def.Complete)
+	}
+
+	var counts = make(map[string]int)
+	var slots = make(map[image.Point]string)
+	var dealt int
+	for i, pd := range def.Piles {
+		where := fmt.Sprintf("Pile %d (%s)", i+1, pd.Category)
+		if !contains(pileCategories, pd.Category) {
+			problem("%s: Category must be one of %s", where, strings.Join(pileCategories, ", "))
+			continue
+		}
+		if pd.Repeat < 0 {
+			problem("%s: Repeat must be at least 1", where)
+		}
+		n := pd.repeat()
+		counts[pd.Category] += n
+		if _, ok := fanTypes[pd.Fan]; !ok {
+			problem("%s: Fan must be one of %s, not %s", where, strings.Join(mapKeys(fanTypes), ", "), pd.Fan)
+		}
+		if _, ok := moveTypes[pd.MoveType]; !ok {
+			problem("%s: MoveType must be one of %s, not %s", where, strings.Join(mapKeys(moveTypes), ", "), pd.MoveType)
+		} else if pd.MoveType != "" && pd.Category != "Tableau" {
+			problem("%s: only tableaux have a MoveType", where)
+		}
+		if !contains(pileLabels, pd.Label) {
+			problem("%s: Label must be one of %s, not %s", where, strings.Join(pileLabels[1:], ", "), pd.Label)
+		}
+		for _, rule := range []struct{ field, name string }{{"Build", pd.Build}, {"Sequence", pd.Sequence}} {
+			if rule.name == "" {
+				continue
+			}
+			if _, ok := compareFunc(rule.name); !ok {
+				problem("%s: %s must be one of %s, not %s", where, rule.field, strings.Join(compareFuncNames(), ", "), rule.name)
+			} else if pd.Category != "Foundation" && pd.Category != "Tableau" && pd.Category != "Reserve" {
+				problem("%s: only foundations, tableaux and reserves have a %s rule", where, rule.field)
+			}
+		}
+		if len(pd.Deal) > 0 {
+			switch {
+			case pd.Category == "Stock" || pd.Category == "Waste" || pd.Category == "Discard":
+				problem("%s: cards can't be dealt to a %s; the stock deals itself", where, pd.Category)
+			case len(pd.Deal) != 1 && len(pd.Deal) != n:
+				problem("%s: Deal must have one pattern for all %d piles, or one for each", where, n)
+			}
+			for j := 0; j < n; j++ {
+				pattern := pd.Deal[j%len(pd.Deal)]
+				if strings.Trim(pattern, "du") != "" {
+					problem("%s: Deal pattern %q must be made of d (face down) and u (face up)", where, pattern)
+					break
+				}
+				dealt += len(pattern)
+			}
+		}
+		for j := 0; j < n; j++ {
+			slot := pd.slot(j)
+			if slot.X < 0 || slot.Y < 0 {
+				continue
+			}
+			if other, ok := slots[slot]; ok {
+				problem("%s: slot %d,%d is already taken by a %s", where, slot.X, slot.Y, other)
+				break
+			}
+			slots[slot] = pd.Category
+		}
+	}
+	if counts["Stock"] != 1 {
+		problem("There must be exactly one Stock pile, not %d", counts["Stock"])
+	}
+	if counts["Waste"] > 1 {
+		problem("There can only be one Waste pile, not %d", counts["Waste"])
+	}
+	if def.Complete != "Sequences" && counts["Foundation"] == 0 {
+		problem("There must be Foundations, unless the game is Complete when the tableaux are Sequences")
+	}
+	if cards := def.packs() * (def.suits()*len(def.ranks()) + def.Jokers); dealt > cards {
+		problem("The piles are dealt %d cards, but there are only %d", dealt, cards)
+	}
+	if def.MicrosoftDeals && (def.packs() != 1 || def.suits() != 4 || len(def.ranks()) != 13 || def.Jokers != 0) {
+		problem("MicrosoftDeals needs one pack of 52 cards")
+	}
+
+	switch def.Stock.DealTo {
+	case "":
+	case "Waste":
+		if counts["Waste"] == 0 {
+			problem("The Stock deals to the Waste, but there isn't a Waste pile")
+		}
+	case "Tableaux":
+		if counts["Tableau"] == 0 {
+			problem("The Stock deals to the Tableaux, but there aren't any")
+		}
+	default:
+		problem("Stock DealTo must be Waste, Tableaux, or left out, not %s", def.Stock.DealTo)
+	}
+	if def.Stock.Draw < 0 {
+		problem("Stock Draw must be at least 1")
+	}
+	if def.Stock.Recycles < 0 {
+		problem("Stock Recycles can't be less than 0")
+	}
+	if (def.Stock.RefillWaste || def.Stock.Recycles > 0 || def.Stock.Draw > 0) && def.Stock.DealTo != "Waste" {
+		problem("Stock Draw, Recycles and RefillWaste only make sense when the Stock DealTo the Waste")
+	}
+	if def.Stock.FillTableauxFirst && def.Stock.DealTo != "Tableaux" {
+		problem("Stock FillTableauxFirst only makes sense when the Stock DealTo the Tableaux")
+	}
+
+	if len(e.Problems) > 0 {
+		return e
+	}
+	return nil
+}
+
+// unsafeNameParts describes what fileSafeName won't allow
+const unsafeNameParts = "/, \\, : or .."
+
+// fileSafeName returns true if a variant name can be used in the name of its saved game file,
+// without pointing somewhere outside the config directory
+func fileSafeName(name string) bool {
+	return !strings.ContainsAny(name, `/\:`) && !strings.Contains(name, "..")
+}
+
+func (def *VariantDef) packs() int {
+	if def.Packs == 0 {
+		return 1
+	}
+	return def.Packs
+}
+
+func (def *VariantDef) suits() int {
+	if def.Suits == 0 {
+		return 4
+	}
+	return def.Suits
+}
+
+// ranks returns the ordinals in each suit, lowest first
+func (def *VariantDef) ranks() []int {
+	if len(def.Ranks) == 0 {
+		return standardRanks
+	}
+	return def.Ranks
+}
+
+func (pd *PileDef) repeat() int {
+	if pd.Repeat == 0 {
+		return 1
+	}
+	return pd.Repeat
+}
+
+// slot returns where the i'th pile in the row goes
+func (pd *PileDef) slot(i int) image.Point {
+	return image.Point{pd.Slot[0] + i, pd.Slot[1]}
+}
+
+// AddVariant makes a defined variant playable, and puts it in the variant picker
+func AddVariant(def *VariantDef) error {
+	if err := def.Validate(); err != nil {
+		return err
+	}
+	variantsLock.Lock()
+	defer variantsLock.Unlock()
+	Variants[def.Name] = &Defined{
+		ScriptBase: ScriptBase{
+			wikipedia:  def.Wikipedia,
+			cardColors: def.CardColors,
+			packs:      def.packs(),
+			suits:      def.suits(),
+			jokers:     def.Jokers,
+			ranks:      def.Ranks,
+		},
+		def: def,
+	}
+	for _, group := range []string{"> All", "> All by Played", "> Your Own"} {
+		VariantGroups[group] = append(VariantGroups[group][:len(VariantGroups[group]):len(VariantGroups[group])], def.Name)
+	}
+	return nil
+}
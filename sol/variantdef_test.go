@@ -0,0 +1,117 @@
+package sol
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// addTestVariant adds a variant from a file in testdata/variants, for the length of a test
+func addTestVariant(t *testing.T, fname string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "variants", fname))
+	if err != nil {
+		t.Fatal(err)
+	}
+	def, err := ParseVariantDef(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return addTestVariantDef(t, def)
+}
+
+// addTestVariantDef adds a variant definition, for the length of a test
+func addTestVariantDef(t *testing.T, def *VariantDef) string {
+	t.Helper()
+	groups := make(map[string][]string)
+	for k, v := range VariantGroups {
+		groups[k] = v
+	}
+	if err := AddVariant(def); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		delete(Variants, def.Name)
+		VariantGroups = groups
+	})
+	return def.Name
+}
+
+// TestVariantDefs checks that the example variant files deal and play just like the variants they copy
+func TestVariantDefs(t *testing.T) {
+	for _, tc := range []struct{ fname, variant string }{
+		{"klondike.json", "Klondike"},
+		{"freecell.json", "Freecell"},
+		{"spider.json", "Spider Four Suits"},
+		{"piquet_klondike.json", "Piquet Klondike"},
+	} {
+		name := addTestVariant(t, tc.fname)
+		if !contains(VariantGroups["> Your Own"], name) {
+			t.Errorf("%s is not in the variant picker", name)
+		}
+		settings := DefaultSettings()
+		settings.AutoCollect = false
+		settings.PySolDeals = false
+		for seed := uint64(1); seed <= 5; seed++ {
+			defined, compiled := NewBaize(name, settings), NewBaize(tc.variant, settings)
+			defined.StartFreshGame()
+			compiled.StartFreshGame()
+			defined.NewDealFromSeed(seed)
+			compiled.NewDealFromSeed(seed)
+			if defined.Hash() != compiled.Hash() {
+				t.Errorf("%s deal %d is not the same as %s", name, seed, tc.variant)
+				continue
+			}
+			got, want := playRandomly(defined, 50, seed), playRandomly(compiled, 50, seed)
+			if len(got) != len(want) {
+				t.Errorf("%s deal %d made %d moves, %s made %d", name, seed, len(got)-1, tc.variant, len(want)-1)
+				continue
+			}
+			for i := range got {
+				if got[i] != want[i] {
+					t.Errorf("%s deal %d went a different way to %s at move %d", name, seed, tc.variant, i)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestBadVariantDefs(t *testing.T) {
+	for _, tc := range []struct{ json, want string }{
+		{`{"Name": "Bad",`, "Line 1: the file ends too soon"},
+		{`{"Name": "Bad", "Piles": [{"Category": "Stock", "Slot": [0, 0]},
+			{"Category": "Tableau", "Slot": [0, 1], "Repeat": "7"}]}`, "Repeat should be int, not string"},
+		{`{"Name": "Bad", "Piles": [{"Category": "Stock", "Slot": [0, 0], "Bulid": "Up"}]}`, `unknown field "Bulid"`},
+		{`{"Piles": [{"Category": "Stock"}], "Complete": "Sequences"}`, "Name is missing"},
+		{`{"Name": "../../evil", "Piles": [{"Category": "Stock"}], "Complete": "Sequences"}`, "Name can't contain"},
+		{`{"Name": "C:\\evil", "Piles": [{"Category": "Stock"}], "Complete": "Sequences"}`, "Name can't contain"},
+		{`{"Name": "Klondike", "Piles": [{"Category": "Stock"}], "Complete": "Sequences"}`, "already a variant called Klondike"},
+		{`{"Name": "Bad", "Piles": [{"Category": "Foundation", "Slot": [0, 0]}]}`, "exactly one Stock"},
+		{`{"Name": "Bad", "Piles": [{"Category": "Stock"}, {"Category": "Tableau", "Slot": [0, 1]}]}`, "There must be Foundations"},
+		{`{"Name": "Bad", "Piles": [{"Category": "Stock"}, {"Category": "Pyramid", "Slot": [0, 1]}]}`, "Category must be one of"},
+		{`{"Name": "Bad", "Piles": [{"Category": "Stock"}, {"Category": "Foundation", "Slot": [1, 0], "Build": "Upwards"}]}`, "Build must be one of"},
+		{`{"Name": "Bad", "Piles": [{"Category": "Stock"}, {"Category": "Foundation", "Slot": [1, 0], "Fan": "Up"}]}`, "Fan must be one of"},
+		{`{"Name": "Bad", "Piles": [{"Category": "Stock"}, {"Category": "Foundation", "Slot": [0, 0]}]}`, "slot 0,0 is already taken by a Stock"},
+		{`{"Name": "Bad", "Piles": [{"Category": "Stock"}, {"Category": "Foundation", "Slot": [1, 0], "Label": "Ace"}]}`, "Label must be one of"},
+		{`{"Name": "Bad", "Piles": [{"Category": "Stock"}, {"Category": "Cell", "Slot": [1, 0], "Build": "Up"}], "Complete": "Sequences"}`, "only foundations, tableaux and reserves"},
+		{`{"Name": "Bad", "Piles": [{"Category": "Stock"}, {"Category": "Tableau", "Slot": [1, 0], "Repeat": 2, "Deal": ["u", "u", "u"]}], "Complete": "Sequences"}`, "one for each"},
+		{`{"Name": "Bad", "Piles": [{"Category": "Stock"}, {"Category": "Tableau", "Slot": [1, 0], "Deal": ["dux"]}], "Complete": "Sequences"}`, "made of d (face down) and u (face up)"},
+		{`{"Name": "Bad", "Piles": [{"Category": "Stock"}, {"Category": "Tableau", "Slot": [1, 0], "Repeat": 2, "Deal": ["` + strings.Repeat("u", 27) + `"]}], "Complete": "Sequences"}`, "dealt 54 cards, but there are only 52"},
+		{`{"Name": "Bad", "Piles": [{"Category": "Stock"}, {"Category": "Foundation", "Slot": [1, 0]}], "Stock": {"DealTo": "Waste"}}`, "there isn't a Waste pile"},
+		{`{"Name": "Bad", "Piles": [{"Category": "Stock"}, {"Category": "Foundation", "Slot": [1, 0]}], "Stock": {"Recycles": 2}}`, "only make sense when the Stock DealTo the Waste"},
+		{`{"Name": "Bad", "Packs": 2, "MicrosoftDeals": true, "Piles": [{"Category": "Stock"}, {"Category": "Foundation", "Slot": [1, 0]}]}`, "MicrosoftDeals needs one pack"},
+		{`{"Name": "Bad", "Ranks": [1, 16], "Piles": [{"Category": "Stock"}, {"Category": "Foundation", "Slot": [1, 0]}]}`, "Ranks must be from 1 to 15, not 16"},
+		{`{"Name": "Bad", "Ranks": [1, 7, 7], "Piles": [{"Category": "Stock"}, {"Category": "Foundation", "Slot": [1, 0]}]}`, "Ranks has 7 more than once"},
+		{`{"Name": "Bad", "Ranks": [1, 7, 8, 9, 10, 11, 12, 13], "Piles": [{"Category": "Stock"}, {"Category": "Tableau", "Slot": [1, 0], "Repeat": 2, "Deal": ["` + strings.Repeat("u", 17) + `"]}], "Complete": "Sequences"}`, "dealt 34 cards, but there are only 32"},
+		{`{"Name": "Bad", "Jokers": 5, "Piles": [{"Category": "Stock"}, {"Category": "Foundation", "Slot": [1, 0]}]}`, "Jokers must be 0 to 4"},
+	} {
+		_, err := ParseVariantDef([]byte(tc.json))
+		if err == nil {
+			t.Errorf("%s was accepted, want error containing '%s'", tc.json, tc.want)
+		} else if !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s gave error '%s', want '%s'", tc.json, err, tc.want)
+		}
+	}
+}
@@ -1,6 +1,15 @@
 package sol
 
-import "sort"
+import (
+	"sort"
+	"sync"
+)
+
+// the ranks in each suit of some packs other than the usual Ace to King
+var (
+	piquetRanks = []int{1, 7, 8, 9, 10, 11, 12, 13}                    // 32 cards
+	tarotRanks  = []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 14, 12, 13} // with a Knight between the Jack and the Queen
+)
 
 var Variants = map[string]Scripter{
 	"Agnes Bernauer": &Agnes{
@@ -43,9 +52,11 @@ var Variants = map[string]Scripter{
 	"Baker's Game": &Freecell{
 		ScriptBase: ScriptBase{
 			wikipedia:  "https://en.wikipedia.org/wiki/Baker%27s_Game",
+			pysol:      "Baker's Game",
 			cardColors: 4,
 		},
 		tabCompareFunc: CardPair.Compare_DownSuit,
+		msDeals:        true,
 	},
 	"Bisley": &Bisley{
 		ScriptBase: ScriptBase{
@@ -60,6 +71,7 @@ var Variants = map[string]Scripter{
 		},
 		tabCompareFunc: CardPair.Compare_DownAltColor,
 		blind:          true,
+		msDeals:        true,
 	},
 	"Blockade": &Blockade{
 		ScriptBase: ScriptBase{
@@ -97,9 +109,34 @@ var Variants = map[string]Scripter{
 			packs:     2,
 		},
 	},
+	"Golf": &Golf{
+		ScriptBase: ScriptBase{
+			wikipedia: "https://en.wikipedia.org/wiki/Golf_(patience)",
+		},
+		tabs:        7,
+		cardsPerTab: 5,
+	},
+	"Black Hole": &Golf{
+		ScriptBase: ScriptBase{
+			wikipedia: "https://en.wikipedia.org/wiki/Black_Hole_(solitaire)",
+		},
+		tabs:        17,
+		cardsPerTab: 3,
+		blackHole:   true,
+		wrap:        true,
+	},
 	"Klondike": &Klondike{
 		ScriptBase: ScriptBase{
 			wikipedia: "https://en.wikipedia.org/wiki/Klondike_(solitaire)",
+			pysol:     "Klondike",
+		},
+		draw:     1,
+		recycles: 2,
+	},
+	"Klondike with Jokers": &Klondike{
+		ScriptBase: ScriptBase{
+			wikipedia: "https://en.wikipedia.org/wiki/Klondike_(solitaire)",
+			jokers:    2,
 		},
 		draw:     1,
 		recycles: 2,
@@ -107,13 +144,25 @@ var Variants = map[string]Scripter{
 	"Klondike Draw Three": &Klondike{
 		ScriptBase: ScriptBase{
 			wikipedia: "https://en.wikipedia.org/wiki/Klondike_(solitaire)",
+			pysol:     "Klondike by Threes",
 		},
 		draw:     3,
 		recycles: 2,
 	},
+	"Piquet Klondike": &Klondike{
+		ScriptBase: ScriptBase{
+			wikipedia: "https://en.wikipedia.org/wiki/Piquet_pack",
+			ranks:     piquetRanks,
+		},
+		draw:     1,
+		recycles: 2,
+		founds:   []int{2, 3, 4, 5},
+		tabs:     []int{0, 1, 2, 3, 4, 5},
+	},
 	"Thoughtful": &Klondike{
 		ScriptBase: ScriptBase{
 			wikipedia: "https://en.wikipedia.org/wiki/Klondike_(solitaire)",
+			pysol:     "Thoughtful",
 		},
 		draw:       1,
 		recycles:   2,
@@ -148,8 +197,10 @@ var Variants = map[string]Scripter{
 	"Freecell": &Freecell{
 		ScriptBase: ScriptBase{
 			wikipedia: "https://en.wikipedia.org/wiki/FreeCell",
+			pysol:     "FreeCell",
 		},
 		tabCompareFunc: CardPair.Compare_DownAltColor,
+		msDeals:        true,
 	},
 	"Freecell Easy": &Freecell{
 		ScriptBase: ScriptBase{
@@ -161,6 +212,7 @@ var Variants = map[string]Scripter{
 	"Forty Thieves": &FortyThieves{
 		ScriptBase: ScriptBase{
 			wikipedia:  "https://en.wikipedia.org/wiki/Forty_Thieves_(solitaire)",
+			pysol:      "Forty Thieves",
 			cardColors: 4,
 			packs:      2,
 		},
@@ -297,6 +349,26 @@ var Variants = map[string]Scripter{
 		tabs:        []int{3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14},
 		cardsPerTab: 5,
 	},
+	"Montana": &Montana{
+		ScriptBase: ScriptBase{
+			wikipedia:  "https://en.wikipedia.org/wiki/Gaps",
+			cardColors: 4,
+		},
+		randomGaps: true,
+	},
+	"Gaps": &Montana{
+		ScriptBase: ScriptBase{
+			wikipedia:  "https://en.wikipedia.org/wiki/Gaps",
+			cardColors: 4,
+		},
+	},
+	"Blue Moon": &Montana{
+		ScriptBase: ScriptBase{
+			wikipedia:  "https://en.wikipedia.org/wiki/Gaps",
+			cardColors: 4,
+		},
+		keepAces: true,
+	},
 	"Mrs Mop": &MrsMop{
 		ScriptBase: ScriptBase{
 			wikipedia:  "https://en.wikipedia.org/wiki/Mrs._Mop",
@@ -318,6 +390,28 @@ var Variants = map[string]Scripter{
 			cardColors: 4,
 		},
 	},
+	"Pyramid": &Pyramid{
+		ScriptBase: ScriptBase{
+			wikipedia:  "https://en.wikipedia.org/wiki/Pyramid_(solitaire)",
+			cardColors: 2,
+		},
+		recycles: 2,
+	},
+	"Pyramid Relaxed": &Pyramid{
+		ScriptBase: ScriptBase{
+			wikipedia:  "https://en.wikipedia.org/wiki/Pyramid_(solitaire)",
+			cardColors: 2,
+		},
+		recycles: 2,
+		relaxed:  true,
+	},
+	"Giza": &Pyramid{
+		ScriptBase: ScriptBase{
+			wikipedia:  "https://en.wikipedia.org/wiki/Pyramid_(solitaire)#Variations",
+			cardColors: 2,
+		},
+		nreserves: 8,
+	},
 	"Scorpion": &Scorpion{
 		ScriptBase: ScriptBase{
 			wikipedia:  "https://en.wikipedia.org/wiki/Scorpion_(solitaire)",
@@ -355,9 +449,28 @@ var Variants = map[string]Scripter{
 	"Spider Four Suits": &Spider{
 		ScriptBase: ScriptBase{
 			wikipedia:  "https://en.wikipedia.org/wiki/Spider_(solitaire)",
+			pysol:      "Spider",
+			cardColors: 4,
+			packs:      2,
+			suits:      4,
+		},
+	},
+	"Spider with Jokers": &Spider{
+		ScriptBase: ScriptBase{
+			wikipedia:  "https://en.wikipedia.org/wiki/Spider_(solitaire)",
+			cardColors: 4,
+			packs:      2,
+			suits:      4,
+			jokers:     1,
+		},
+	},
+	"Tarot Spider": &Spider{
+		ScriptBase: ScriptBase{
+			wikipedia:  "https://en.wikipedia.org/wiki/Tarot_card_games",
 			cardColors: 4,
 			packs:      2,
 			suits:      4,
+			ranks:      tarotRanks,
 		},
 	},
 	"Spiderette": &Spiderette{
@@ -389,6 +502,12 @@ var Variants = map[string]Scripter{
 			wikipedia: "https://en.wikipedia.org/wiki/Klondike_(solitaire)",
 		},
 	},
+	"TriPeaks": &Golf{
+		ScriptBase: ScriptBase{
+			wikipedia: "https://en.wikipedia.org/wiki/Tri_Peaks_(game)",
+		},
+		wrap: true,
+	},
 	"Usk": &Usk{
 		ScriptBase: ScriptBase{
 			wikipedia: "https://politaire.com/help/usk",
@@ -403,6 +522,7 @@ var Variants = map[string]Scripter{
 	"Yukon": &Yukon{
 		ScriptBase: ScriptBase{
 			wikipedia: "https://en.wikipedia.org/wiki/Yukon_(solitaire)",
+			pysol:     "Yukon",
 		},
 	},
 	"Yukon Cells": &Yukon{
@@ -417,15 +537,18 @@ var VariantGroups = map[string][]string{
 	// "> All" added dynamically by func init()
 	// don't have any group that comes alphabetically before "> All"
 	"> Canfields":     {"Canfield", "Storehouse", "Duchess", "American Toad"},
-	"> Easier":        {"American Toad", "American Westcliff", "Blockade", "Classic Westcliff", "Lucas", "Spider One Suit", "Usk Relaxed"},
+	"> Easier":        {"American Toad", "American Westcliff", "Blockade", "Classic Westcliff", "Lucas", "Pyramid Relaxed", "Spider One Suit", "Usk Relaxed"},
+	"> Golfs":         {"Golf", "TriPeaks", "Black Hole"},
+	"> Montanas":      {"Montana", "Gaps", "Blue Moon"},
 	"> Harder":        {"Baker's Dozen", "Easthaven", "Forty Thieves", "Spider Four Suits", "Usk"},
 	"> Forty Thieves": {"Forty Thieves", "Number Ten", "Red and Black", "Indian", "Rank and File", "Sixty Thieves", "Josephine", "Limited", "Forty and Eight", "Lucas", "Busy Aces", "Maria", "Streets"},
 	"> Freecells":     {"Baker's Game", "Blind Freecell", "Freecell", "Freecell Easy", "Eight Off", "Seahaven Towers"},
-	"> Klondikes":     {"Gargantua", "Triple Klondike", "Klondike", "Klondike Draw Three", "Thoughtful", "Whitehead"},
+	"> Klondikes":     {"Gargantua", "Triple Klondike", "Klondike", "Klondike Draw Three", "Klondike with Jokers", "Piquet Klondike", "Thoughtful", "Whitehead"},
 	"> People":        {"Agnes Bernauer", "Duchess", "Josephine", "Maria", "Simple Simon", "Baker's Game"},
-	"> Places":        {"Australian", "Bisley", "Yukon", "Klondike", "Usk", "Usk Relaxed"},
+	"> Places":        {"Australian", "Bisley", "Giza", "Montana", "Yukon", "Klondike", "Usk", "Usk Relaxed"},
 	"> Puzzlers":      {"Antares", "Demons and Thieves", "Bisley", "Usk", "Mrs Mop", "Penguin", "Simple Simon", "Baker's Dozen"},
-	"> Spiders":       {"Spider One Suit", "Spider Two Suits", "Spider Four Suits", "Scorpion", "Spiderette"},
+	"> Pyramids":      {"Pyramid", "Pyramid Relaxed", "Giza"},
+	"> Spiders":       {"Spider One Suit", "Spider Two Suits", "Spider Four Suits", "Spider with Jokers", "Tarot Spider", "Scorpion", "Spiderette"},
 	"> Yukons":        {"Yukon", "Yukon Cells"},
 }
 
@@ -440,23 +563,39 @@ func init() {
 	VariantGroups["> All by Played"] = vnames
 }
 
+// variantsLock guards Variants and VariantGroups, because data files and scripts add to them
+// while solver searches on other goroutines are making baizes
+var variantsLock sync.RWMutex
+
+// LookupVariant returns the prototype script of a variant, and false if there is no such variant
+func LookupVariant(name string) (Scripter, bool) {
+	variantsLock.RLock()
+	defer variantsLock.RUnlock()
+	proto, ok := Variants[name]
+	return proto, ok
+}
+
 // VariantGroupNames returns an alpha-sorted []string of the variant group names
 func VariantGroupNames() []string {
+	variantsLock.RLock()
 	var vnames []string = make([]string, 0, len(VariantGroups))
 	for k := range VariantGroups {
 		vnames = append(vnames, k)
 	}
+	variantsLock.RUnlock()
 	sort.Slice(vnames, func(i, j int) bool { return vnames[i] < vnames[j] })
 	return vnames
 }
 
 // VariantNames returns an alpha-sorted []string of the variants in a group
-func VariantNames(group string) []string {
+func VariantNames(group string, stats *Statistics) []string {
 	var vnames []string = nil
+	variantsLock.RLock()
 	vnames = append(vnames, VariantGroups[group]...)
+	variantsLock.RUnlock()
 	if group == "> All by Played" {
 		sort.Slice(vnames, func(i, j int) bool {
-			return TheGame.Statistics.Played(vnames[i]) > TheGame.Statistics.Played(vnames[j])
+			return stats.Played(vnames[i]) > stats.Played(vnames[j])
 		})
 	} else {
 		sort.Slice(vnames, func(i, j int) bool { return vnames[i] < vnames[j] })
@@ -0,0 +1,45 @@
+package sol
+
+import (
+	"context"
+)
+
+// WinnableDealTries is how many deals FindDeal looks at before giving up
+var WinnableDealTries = 20
+
+// WinnableDealNodes is how many positions the solver may expand to prove each deal can be won
+var WinnableDealNodes = 20000
+
+// FindDeal looks for a deal of a variant that the solver can win,
+// and that has the wanted rating (EASY, MEDIUM, HARD, or "" for any),
+// trying seed first, then random seeds.
+// It plays on its own baizes, so it can be run on a goroutine of its own,
+// as long as the settings are not being changed at the same time.
+// Returns the seed and its difficulty, or false if nothing was found or ctx was cancelled.
+func FindDeal(ctx context.Context, variant string, settings *Settings, seed uint64, rating string) (uint64, *Difficulty, bool) {
+	if _, ok := LookupVariant(variant); !ok {
+		return 0, nil, false
+	}
+	for try := 0; try < WinnableDealTries; try++ {
+		if ctx.Err() != nil {
+			return 0, nil, false
+		}
+		d, outcome := RateDeal(ctx, variant, settings, seed)
+		if outcome == SOLVED && (rating == "" || d.Rating() == rating) {
+			return seed, d, true
+		}
+		seed = NewSeed()
+	}
+	return 0, nil, false
+}
+
+// Winnable returns true if this deal has been proven to be winnable
+func (b *Baize) Winnable() bool {
+	return b.winnable
+}
+
+// SetWinnable records that this deal has been proven to be winnable,
+// so the game can be recorded in its own statistics
+func (b *Baize) SetWinnable(winnable bool) {
+	b.winnable = winnable
+}
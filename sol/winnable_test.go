@@ -0,0 +1,50 @@
+package sol
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFindDeal(t *testing.T) {
+	// Klondike deal #1 can be won, so it should be accepted as it is
+	seed, d, ok := FindDeal(context.Background(), "Klondike", nil, 1, "")
+	if !ok || seed != 1 || d == nil {
+		t.Errorf("got deal #%d %v, want deal #1", seed, ok)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, ok := FindDeal(ctx, "Klondike", nil, 1, ""); ok {
+		t.Error("cancelled search found a deal")
+	}
+}
+
+func TestWinnableSurvivesUndo(t *testing.T) {
+	b := NewBaize("Klondike", nil)
+	b.StartFreshGame()
+	b.NewDealFromSeed(1)
+	b.SetWinnable(true)
+	moves := b.LegalMoves()
+	if len(moves) == 0 || !b.ApplyMove(moves[0]) {
+		t.Fatal("no move to make")
+	}
+	b.Undo()
+	if !b.Winnable() {
+		t.Error("undo forgot the deal was winnable")
+	}
+	b.NewDeal()
+	if b.Winnable() {
+		t.Error("a new deal is not known to be winnable")
+	}
+}
+
+func TestWinnableGamesInTotals(t *testing.T) {
+	s := &Statistics{StatsMap: map[string]*VariantStatistics{
+		"Klondike": {Won: 1, Lost: 2, Winnable: &VariantStatistics{Won: 3, Lost: 4}},
+		"Freecell": {Won: 5},
+	}}
+	strs := s.strings()
+	if len(strs) < 3 || strs[0] != "Played: 15" || strs[1] != "Won: 9" || strs[2] != "Lost: 6" {
+		t.Errorf("got %v, want 15 played, 9 won, 6 lost", strs)
+	}
+}
@@ -0,0 +1,39 @@
+package ui
+
+import "oddstream.games/gosol/schriftbank"
+
+// AnalysisDrawer provides a drawer for showing where a game was lost, and what can be done about it
+type AnalysisDrawer struct {
+	DrawerBase
+}
+
+// Action is a line of text in a drawer that sends a command when it is tapped
+type Action struct {
+	Text    string
+	Command string
+}
+
+// NewAnalysisDrawer creates a new container
+func NewAnalysisDrawer() *AnalysisDrawer {
+	r := &AnalysisDrawer{DrawerBase: DrawerBase{WindowBase: WindowBase{x: -400, y: ToolbarHeight, width: 400}}} // height will be set when drawn
+	return r
+}
+
+// ShowAnalysisDrawer makes the analysis drawer visible, with some text followed by some actions
+func (u *UI) ShowAnalysisDrawer(content []string, actions []Action) {
+	con := u.VisibleDrawer()
+	if con != nil {
+		con.Hide()
+	}
+
+	u.analysisDrawer.widgets = nil
+	for _, c := range content {
+		u.analysisDrawer.widgets = append(u.analysisDrawer.widgets, NewText(u.analysisDrawer, "", c))
+	}
+	for _, a := range actions {
+		u.analysisDrawer.widgets = append(u.analysisDrawer.widgets, NewLabel(u.analysisDrawer, "", 0, a.Text, schriftbank.RobotoMedium24, a.Command))
+	}
+	u.analysisDrawer.ResetScroll()
+	u.analysisDrawer.LayoutWidgets()
+	u.analysisDrawer.Show()
+}
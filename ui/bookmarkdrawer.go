@@ -0,0 +1,33 @@
+package ui
+
+import "oddstream.games/gosol/schriftbank"
+
+// BookmarkDrawer provides a drawer for listing the bookmarks in a game
+type BookmarkDrawer struct {
+	DrawerBase
+}
+
+// NewBookmarkDrawer creates a new container
+func NewBookmarkDrawer() *BookmarkDrawer {
+	r := &BookmarkDrawer{DrawerBase: DrawerBase{WindowBase: WindowBase{x: -400, y: ToolbarHeight, width: 400}}} // height will be set when drawn
+	return r
+}
+
+// ShowBookmarkDrawer makes the bookmark drawer visible, with a thumbnail for each bookmark followed by some actions
+func (u *UI) ShowBookmarkDrawer(thumbnails []Thumbnail, actions []Action) {
+	con := u.VisibleDrawer()
+	if con != nil {
+		con.Hide()
+	}
+
+	u.bookmarkDrawer.widgets = nil
+	for _, t := range thumbnails {
+		u.bookmarkDrawer.widgets = append(u.bookmarkDrawer.widgets, NewThumbnailWidget(u.bookmarkDrawer, "", t))
+	}
+	for _, a := range actions {
+		u.bookmarkDrawer.widgets = append(u.bookmarkDrawer.widgets, NewLabel(u.bookmarkDrawer, "", 0, a.Text, schriftbank.RobotoMedium24, a.Command))
+	}
+	u.bookmarkDrawer.ResetScroll()
+	u.bookmarkDrawer.LayoutWidgets()
+	u.bookmarkDrawer.Show()
+}
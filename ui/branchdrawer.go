@@ -0,0 +1,28 @@
+package ui
+
+// BranchDrawer provides a drawer for listing the other lines of play in a game
+type BranchDrawer struct {
+	DrawerBase
+}
+
+// NewBranchDrawer creates a new container
+func NewBranchDrawer() *BranchDrawer {
+	r := &BranchDrawer{DrawerBase: DrawerBase{WindowBase: WindowBase{x: -400, y: ToolbarHeight, width: 400}}} // height will be set when drawn
+	return r
+}
+
+// ShowBranchDrawer makes the branch drawer visible, with a thumbnail of the end of each line of play
+func (u *UI) ShowBranchDrawer(thumbnails []Thumbnail) {
+	con := u.VisibleDrawer()
+	if con != nil {
+		con.Hide()
+	}
+
+	u.branchDrawer.widgets = nil
+	for _, t := range thumbnails {
+		u.branchDrawer.widgets = append(u.branchDrawer.widgets, NewThumbnailWidget(u.branchDrawer, "", t))
+	}
+	u.branchDrawer.ResetScroll()
+	u.branchDrawer.LayoutWidgets()
+	u.branchDrawer.Show()
+}
@@ -45,6 +45,9 @@ var radio_button_checkedIconBytes []byte
 //go:embed icons/radio_button_unchecked.png
 var radio_button_uncheckedIconBytes []byte
 
+//go:embed icons/redo.png
+var redoIconBytes []byte
+
 //go:embed icons/restore.png
 var restoreIconBytes []byte
 
@@ -96,6 +99,7 @@ func LoadIconMapFromEmbedded() {
 	decode("menu", menuIconBytes)
 	decode("radio_button_checked", radio_button_checkedIconBytes)
 	decode("radio_button_unchecked", radio_button_uncheckedIconBytes)
+	decode("redo", redoIconBytes)
 	decode("restore", restoreIconBytes)
 	decode("search", searchIconBytes)
 	decode("settings", settingsIconBytes)
@@ -23,9 +23,15 @@ func NewNavDrawer() *NavDrawer {
 		// widget x, y will be set by LayoutWidgets()
 		NewNavItem(nd, "newDeal", "star", "New deal", ebiten.KeyN),
 		NewNavItem(nd, "restartDeal", "restore", "Restart deal", ebiten.KeyR),
+		NewNavItem(nd, "dealNumber", "list", "Deal number...", ebiten.KeyD),
+		NewNavItem(nd, "ratedDeal", "poll", "Easy, medium or hard deal...", ebiten.KeyE),
 		NewNavItem(nd, "findGame", "search", "Find game...", ebiten.KeyF),
 		NewNavItem(nd, "bookmark", "bookmark_add", "Set bookmark", ebiten.KeyS),
 		NewNavItem(nd, "gotoBookmark", "bookmark", "Go to bookmark", ebiten.KeyL),
+		NewNavItem(nd, "bookmarks", "list", "Bookmarks...", ebiten.KeyK),
+		NewNavItem(nd, "branches", "list", "Other lines of play...", ebiten.KeyT),
+		NewNavItem(nd, "analyse", "lightbulb", "Where did it go wrong?", ebiten.KeyW),
+		NewNavItem(nd, "demo", "speed", "Watch a demo", ebiten.KeyP),
 		NewNavItem(nd, "wikipedia", "wikipedia", "Wikipedia...", ebiten.KeyF1),
 		NewNavItem(nd, "statistics", "poll", "Statistics...", ebiten.KeyF2),
 		NewNavItem(nd, "settings", "settings", "Settings...", ebiten.KeyF3),
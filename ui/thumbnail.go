@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"image"
+
+	"github.com/fogleman/gg"
+	"github.com/hajimehoshi/ebiten/v2"
+	"oddstream.games/gosol/schriftbank"
+)
+
+// Thumbnail is a line in a drawer, with a small picture, that sends a command when it is tapped
+type Thumbnail struct {
+	Text    string
+	Img     image.Image
+	Command string
+	Data    string
+}
+
+// ThumbnailWidget shows a Thumbnail
+type ThumbnailWidget struct {
+	WidgetBase
+	thumbnail Thumbnail
+}
+
+func (tw *ThumbnailWidget) createImg() *ebiten.Image {
+	dc := gg.NewContext(tw.width, tw.height)
+
+	dc.DrawImage(tw.thumbnail.Img, 0, 0)
+
+	// nota bene - text is drawn with y as a baseline
+	dc.SetColor(ForegroundColor)
+	dc.SetFontFace(schriftbank.RobotoMedium24)
+	dc.DrawString(tw.thumbnail.Text, float64(tw.thumbnail.Img.Bounds().Dx()+24), float64(tw.height)*0.6)
+
+	return ebiten.NewImageFromImage(dc.Image())
+}
+
+// NewThumbnailWidget creates a new widget for a Thumbnail
+func NewThumbnailWidget(parent Containery, id string, thumbnail Thumbnail) *ThumbnailWidget {
+	w, _ := parent.Size()
+	// widget x, y will be set by LayoutWidgets
+	tw := &ThumbnailWidget{WidgetBase: WidgetBase{parent: parent, id: id, width: w - 24, height: thumbnail.Img.Bounds().Dy()},
+		thumbnail: thumbnail}
+	tw.Activate()
+	return tw
+}
+
+// Activate tells the input we need notifications
+func (tw *ThumbnailWidget) Activate() {
+	tw.disabled = false
+	tw.img = tw.createImg()
+}
+
+// Deactivate tells the input we no longer need notifications
+func (tw *ThumbnailWidget) Deactivate() {
+	tw.disabled = true
+	tw.img = tw.createImg()
+}
+
+func (tw *ThumbnailWidget) Tapped() {
+	if tw.disabled {
+		return
+	}
+	cmdFn(Command{Command: tw.thumbnail.Command, Data: tw.thumbnail.Data})
+}
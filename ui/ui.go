@@ -32,6 +32,9 @@ type UI struct {
 	settingsDrawer, aniSpeedDrawer *SettingsDrawer
 	variantPicker                  *Picker
 	textDrawer                     *TextDrawer
+	analysisDrawer                 *AnalysisDrawer
+	bookmarkDrawer                 *BookmarkDrawer
+	branchDrawer                   *BranchDrawer
 	containers                     []Containery // all the containers
 	bars                           []Containery // just the status, toolbar, fab
 	drawers                        []Containery // just the drawers
@@ -55,11 +58,14 @@ func New(fn func(interface{})) *UI {
 	ui.settingsDrawer = NewSettingsDrawer()
 	ui.aniSpeedDrawer = NewSettingsDrawer()
 	ui.variantPicker = NewVariantPicker()
-	ui.textDrawer = NewTextDrawer() // contents are added when shown
+	ui.textDrawer = NewTextDrawer()         // contents are added when shown
+	ui.analysisDrawer = NewAnalysisDrawer() // contents are added when shown
+	ui.bookmarkDrawer = NewBookmarkDrawer() // contents are added when shown
+	ui.branchDrawer = NewBranchDrawer()     // contents are added when shown
 
 	ui.bars = []Containery{ui.toolbar, ui.statusbar, ui.fab}
-	ui.drawers = []Containery{ui.navDrawer, ui.settingsDrawer, ui.aniSpeedDrawer, ui.variantPicker, ui.textDrawer}
-	ui.containers = []Containery{ui.toolbar, ui.statusbar, ui.fab, ui.navDrawer, ui.settingsDrawer, ui.aniSpeedDrawer, ui.variantPicker, ui.textDrawer}
+	ui.drawers = []Containery{ui.navDrawer, ui.settingsDrawer, ui.aniSpeedDrawer, ui.variantPicker, ui.textDrawer, ui.analysisDrawer, ui.bookmarkDrawer, ui.branchDrawer}
+	ui.containers = []Containery{ui.toolbar, ui.statusbar, ui.fab, ui.navDrawer, ui.settingsDrawer, ui.aniSpeedDrawer, ui.variantPicker, ui.textDrawer, ui.analysisDrawer, ui.bookmarkDrawer, ui.branchDrawer}
 
 	return ui
 }
@@ -170,9 +170,13 @@ func Distance(p1, p2 image.Point) float64 {
 // 	return xOverlap * yOverlap
 // }
 
-// OrdinalToShortString converts an ordinal (1..13) to a single(ish) character (A .. K)
+// OrdinalToShortString converts an ordinal (1..15) to a single(ish) character (A .. K, C for the Tarot Knight,
+// and 15 for the one rank left after that)
 func OrdinalToShortString(ord int) string {
-	var chars = [14]string{"?", "A", "2", "3", "4", "5", "6", "7", "8", "9", "10", "J", "Q", "K"}
+	var chars = [16]string{"?", "A", "2", "3", "4", "5", "6", "7", "8", "9", "10", "J", "Q", "K", "C", "15"}
+	if ord < 0 || ord >= len(chars) {
+		return "?"
+	}
 	return chars[ord]
 }
 
@@ -231,6 +235,8 @@ func ShortOrdinalToLongOrdinal(str string) string {
 		return "Queen"
 	case "K":
 		return "King"
+	case "C":
+		return "Knight"
 	default:
 		return str
 	}
@@ -343,6 +349,43 @@ func makeConfigDir() {
 	// if path is already a directory, MkdirAll does nothing and returns nil
 }
 
+// ConfigDirFiles returns the names of the files in a directory within the config directory
+func ConfigDirFiles(dir string) []string {
+	if runtime.GOARCH == "wasm" {
+		log.Fatal("WASM detected")
+	}
+
+	path, err := fullConfigPath(dir)
+	if err != nil {
+		return nil
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil // directory does not exist (which is ok)
+	}
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+	return names
+}
+
+// ConfigFileModTime returns when a file in the config directory was last changed,
+// or the zero time if it can't be found
+func ConfigFileModTime(fname string) time.Time {
+	path, err := fullConfigPath(fname)
+	if err != nil {
+		return time.Time{}
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return fi.ModTime()
+}
+
 func LoadBytesFromFile(fname string, leaveNoTrace bool) ([]byte, int, error) {
 
 	if runtime.GOARCH == "wasm" {